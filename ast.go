@@ -22,6 +22,55 @@ type Node interface {
 type AST struct {
 	Statements []Statement
 	Errors     map[int]error
+
+	// source and sourceSpans are only set when parsing with the Lossless option.
+	source      string
+	sourceSpans []sourceSpan
+}
+
+// sourceSpan is the [start, end) byte range of a statement in the input.
+type sourceSpan struct {
+	start, end int
+}
+
+// Source returns the original text the AST was parsed from, preserving whitespace and casing.
+// If the AST wasn't parsed with the Lossless option, it returns the canonical representation.
+func (node *AST) Source() string {
+	if node.sourceSpans == nil {
+		return node.String()
+	}
+	return node.source
+}
+
+// StatementSource returns the original text of the i-th statement, without the surrounding whitespace and semicolons.
+// If the AST wasn't parsed with the Lossless option, it returns the canonical representation of the statement.
+// Changes made to the statement after parsing are not reflected in its source.
+// It reports false if i is out of range.
+func (node *AST) StatementSource(i int) (string, bool) {
+	if i < 0 || i >= len(node.Statements) {
+		return "", false
+	}
+	if node.sourceSpans == nil || i >= len(node.sourceSpans) {
+		return node.Statements[i].String(), true
+	}
+	span := node.sourceSpans[i]
+	return node.source[span.start:span.end], true
+}
+
+// NodeSource returns the original text of n, which must be the AST or one of its statements.
+// The lossless mode is statement-level only: the source of the nodes inside a statement, like a clause or
+// an expression, is not recorded, so it reports false for them, as well as for nodes that are not in the AST.
+// If the AST wasn't parsed with the Lossless option, it returns the canonical representation.
+func (node *AST) NodeSource(n Node) (string, bool) {
+	if n == Node(node) {
+		return node.Source(), true
+	}
+	for i, stmt := range node.Statements {
+		if n == Node(stmt) {
+			return node.StatementSource(i)
+		}
+	}
+	return "", false
 }
 
 func (node *AST) String() string {
@@ -106,13 +155,19 @@ type Statement interface {
 
 func (*Select) iStatement()         {}
 func (*CompoundSelect) iStatement() {}
+func (*Values) iStatement()         {}
 func (*CreateTable) iStatement()    {}
+func (*CreateView) iStatement()     {}
 func (*Insert) iStatement()         {}
 func (*Delete) iStatement()         {}
 func (*Update) iStatement()         {}
 func (*Grant) iStatement()          {}
 func (*Revoke) iStatement()         {}
 func (*AlterTable) iStatement()     {}
+func (*Vacuum) iStatement()         {}
+func (*Analyze) iStatement()        {}
+func (*Reindex) iStatement()        {}
+func (*Pragma) iStatement()         {}
 
 // ReadStatementResolver resolves Tableland Custom Functions for a read statement.
 type ReadStatementResolver interface {
@@ -124,6 +179,16 @@ type ReadStatementResolver interface {
 	GetBindValues() []Expr
 }
 
+// ReadStatementBindListResolver is a ReadStatementResolver that can bind a list of values
+// to a param used as the right operand of IN, e.g. a IN ?.
+type ReadStatementBindListResolver interface {
+	ReadStatementResolver
+
+	// GetBindList returns the list of values bound to the param at index. If it returns nil,
+	// the param is bound to the single value at index returned by GetBindValues.
+	GetBindList(index int) []Expr
+}
+
 // WriteStatementResolver resolves Tableland Custom Functions for a write statement.
 type WriteStatementResolver interface {
 	// GetTxnHash returns the transaction hash of the transaction containing the query being processed.
@@ -131,6 +196,10 @@ type WriteStatementResolver interface {
 
 	// GetBlockNumber returns the block number of the block containing query being processed.
 	GetBlockNumber() int64
+
+	// GetBlockNumberForChain returns the last known block number for the provided chainID. If the chainID isn't known,
+	// it returns (0, false).
+	GetBlockNumberForChain(chainID int64) (int64, bool)
 }
 
 // ReadStatement is any SELECT statement or UNION statement.
@@ -145,6 +214,7 @@ type ReadStatement interface {
 
 func (*Select) iReadStatement()         {}
 func (*CompoundSelect) iReadStatement() {}
+func (*Values) iReadStatement()         {}
 
 // CreateTableStatement is any CREATE TABLE statement.
 type CreateTableStatement interface {
@@ -171,6 +241,16 @@ func (*Update) iWriteStatement()     {}
 func (*Delete) iWriteStatement()     {}
 func (*AlterTable) iWriteStatement() {}
 
+// AdminStatement is any VACUUM, ANALYZE or REINDEX maintenance statement.
+type AdminStatement interface {
+	Statement
+	iAdminStatement()
+}
+
+func (*Vacuum) iAdminStatement()  {}
+func (*Analyze) iAdminStatement() {}
+func (*Reindex) iAdminStatement() {}
+
 // GrantOrRevokeStatement is any GRANT/REVOKE statement.
 type GrantOrRevokeStatement interface {
 	Statement
@@ -178,6 +258,7 @@ type GrantOrRevokeStatement interface {
 	GetRoles() []string
 	GetPrivileges() Privileges
 	GetTable() *Table
+	Triples() []GrantTriple
 }
 
 func (*Grant) iGrantOrRevokeStatement()  {}
@@ -197,12 +278,15 @@ type Select struct {
 
 // String returns the string representation of the node.
 func (node *Select) String() string {
+	var from string
+	if node.From != nil {
+		from = nodeStringsConcat("from", node.From.String())
+	}
 	return nodeStringsConcat(
 		"select",
 		node.Distinct,
 		node.SelectColumnList.String(),
-		"from",
-		node.From.String(),
+		from,
 		node.Where.String(),
 		node.GroupBy.String(),
 		node.Having.String(),
@@ -217,6 +301,43 @@ func (node *Select) Resolve(resolver ReadStatementResolver) (string, error) {
 	return resolveReadStatementWalk(node, resolver)
 }
 
+// IsAggregated checks if the SELECT produces aggregated rows, because it has a GROUP BY or HAVING clause,
+// or an aggregate function call in its result columns. Aggregate functions in subqueries are not considered.
+func (node *Select) IsAggregated() bool {
+	if len(node.GroupBy) > 0 || node.Having != nil {
+		return true
+	}
+
+	var aggregated bool
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(n Node) (bool, error) {
+		switch n := n.(type) {
+		case *Subquery:
+			return true, nil
+		case *FuncExpr:
+			// an aggregate function with an OVER clause is a window function, which doesn't aggregate rows
+			if n.Over == nil && isAggregateFunction(strings.ToLower(string(n.Name)), n.Args) {
+				aggregated = true
+				return true, nil
+			}
+		}
+		return aggregated, nil
+	}, node.SelectColumnList)
+
+	return aggregated
+}
+
+// Unordered returns a copy of the SELECT without its ORDER BY clause, which is meaningless when the SELECT
+// is used as a subquery, e.g. in IN (SELECT ...). The LIMIT clause is kept because removing it changes the result,
+// and so is the ORDER BY if there is a LIMIT, because it determines which rows are returned. The node is not modified.
+func (node *Select) Unordered() *Select {
+	unordered := cloneNode(node).(*Select)
+	if unordered.Limit == nil {
+		unordered.OrderBy = nil
+	}
+	return unordered
+}
+
 func (node *Select) walkSubtree(visit Visit) error {
 	if node == nil {
 		return nil
@@ -229,8 +350,8 @@ func (node *Select) walkSubtree(visit Visit) error {
 		node.Where,
 		node.GroupBy,
 		node.Having,
-		node.Limit,
 		node.OrderBy,
+		node.Limit,
 	)
 }
 
@@ -242,9 +363,12 @@ const (
 	CompoundExceptStr    = "except"
 )
 
-// CompoundSelect represents a compound operation of selects.
+// CompoundSelect represents a compound operation of selects. Compound operators are left-associative,
+// so Left is a *CompoundSelect when there are more than two operands, or else a *Select or a *Values,
+// while Right is always a *Select or a *Values. The ORDER BY and LIMIT of the compound belong to
+// its last operand, the Right of the outermost CompoundSelect.
 type CompoundSelect struct {
-	Left  *Select
+	Left  ReadStatement
 	Type  string
 	Right ReadStatement
 }
@@ -270,6 +394,38 @@ func (node *CompoundSelect) walkSubtree(visit Visit) error {
 	return Walk(visit, node.Left, node.Right)
 }
 
+// Values represents a standalone VALUES statement, e.g. VALUES (1, 'a'), (2, 'b').
+type Values struct {
+	Rows []Exprs
+}
+
+// String returns the string representation of the node.
+func (node *Values) String() string {
+	var rows []string
+	for _, row := range node.Rows {
+		rows = append(rows, row.String())
+	}
+	return nodeStringsConcat("values", strings.Join(rows, ","))
+}
+
+// Resolve returns a string representation with custom function nodes resolved to the values
+// passed by resolver.
+func (node *Values) Resolve(resolver ReadStatementResolver) (string, error) {
+	return resolveReadStatementWalk(node, resolver)
+}
+
+func (node *Values) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	for _, row := range node.Rows {
+		if err := Walk(visit, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Distinct/All.
 const (
 	DistinctStr = "distinct "
@@ -473,11 +629,17 @@ const (
 
 // String returns the string representation of the node.
 func (node *JoinTableExpr) String() string {
+	// Joins are left associative, so a parenthesized join on the right side must keep its parens.
+	rightExpr := node.RightExpr
+	if _, ok := rightExpr.(*JoinTableExpr); ok {
+		rightExpr = &ParenTableExpr{TableExpr: rightExpr}
+	}
+
 	if node.On != nil {
 		return nodeStringsConcat(
 			node.LeftExpr.String(),
 			node.JoinOperator.String(),
-			node.RightExpr.String(),
+			rightExpr.String(),
 			"on",
 			node.On.String(),
 		)
@@ -487,7 +649,7 @@ func (node *JoinTableExpr) String() string {
 		return nodeStringsConcat(
 			node.LeftExpr.String(),
 			node.JoinOperator.String(),
-			node.RightExpr.String(),
+			rightExpr.String(),
 			"using",
 			node.Using.String(),
 		)
@@ -496,7 +658,7 @@ func (node *JoinTableExpr) String() string {
 	return nodeStringsConcat(
 		node.LeftExpr.String(),
 		node.JoinOperator.String(),
-		node.RightExpr.String(),
+		rightExpr.String(),
 	)
 }
 
@@ -618,23 +780,25 @@ const (
 	NullsLast
 )
 
+// String returns the string representation of the NULLS clause, or an empty string if there is none.
+// It's shared by every ORDER BY, so ordering terms are rendered the same way in every context.
+func (n NullsType) String() string {
+	switch n {
+	case NullsFirst:
+		return "nulls first"
+	case NullsLast:
+		return "nulls last"
+	}
+	return ""
+}
+
 // String returns the string representation of the node.
 func (node *OrderingTerm) String() string {
 	if node, ok := node.Expr.(*NullValue); ok {
 		return node.String()
 	}
 
-	var nullsStr string
-	switch node.Nulls {
-	case NullsNil:
-		nullsStr = ""
-	case NullsFirst:
-		nullsStr = "nulls first"
-	case NullsLast:
-		nullsStr = "nulls last"
-	}
-
-	return nodeStringsConcat(node.Expr.String(), node.Direction, nullsStr)
+	return nodeStringsConcat(node.Expr.String(), node.Direction, node.Nulls.String())
 }
 
 func (node *OrderingTerm) walkSubtree(visit Visit) error {
@@ -648,6 +812,9 @@ func (node *OrderingTerm) walkSubtree(visit Visit) error {
 type Limit struct {
 	Limit  Expr
 	Offset Expr
+
+	// CommaSyntax indicates that the limit was written as LIMIT offset, limit, which is how it's rendered.
+	CommaSyntax bool
 }
 
 // String returns the string representation of the node.
@@ -660,6 +827,10 @@ func (node *Limit) String() string {
 		return nodeStringsConcat("limit", node.Limit.String())
 	}
 
+	if node.CommaSyntax {
+		return nodeStringsConcat("limit", node.Offset.String()+","+node.Limit.String())
+	}
+
 	return nodeStringsConcat("limit", node.Limit.String(), "offset", node.Offset.String())
 }
 
@@ -668,6 +839,9 @@ func (node *Limit) walkSubtree(visit Visit) error {
 		return nil
 	}
 
+	if node.CommaSyntax {
+		return Walk(visit, node.Offset, node.Limit)
+	}
 	return Walk(visit, node.Limit, node.Offset)
 }
 
@@ -700,6 +874,7 @@ func (*ExistsExpr) iExpr()     {}
 func (*FuncExpr) iExpr()       {}
 func (*CustomFuncExpr) iExpr() {}
 func (*ParenExpr) iExpr()      {}
+func (*DefaultExpr) iExpr()    {}
 
 // NullValue represents null values.
 type NullValue struct{}
@@ -713,6 +888,21 @@ func (node *NullValue) walkSubtree(_ Visit) error {
 	return nil
 }
 
+// DefaultExpr represents the DEFAULT keyword used as a value in an INSERT row,
+// e.g. INSERT INTO t (a, b) VALUES (1, DEFAULT).
+// SQLite doesn't accept this form, so it's only parsed with the AllowDefaultKeyword option,
+// for statements that are rewritten before execution.
+type DefaultExpr struct{}
+
+// String returns the string representation of the node.
+func (node *DefaultExpr) String() string {
+	return "default"
+}
+
+func (node *DefaultExpr) walkSubtree(_ Visit) error {
+	return nil
+}
+
 // BoolValue represents booleans.
 type BoolValue bool
 
@@ -781,10 +971,12 @@ const (
 
 // String returns the string representation of the node.
 func (node *UnaryExpr) String() string {
-	if expr, ok := node.Expr.(*UnaryExpr); ok {
-		return fmt.Sprintf("%s %s", node.Operator, expr.String())
+	expr := node.Expr.String()
+	// a minus operator followed by a negative operand would start a -- comment
+	if _, ok := node.Expr.(*UnaryExpr); ok || (node.Operator == UMinusStr && strings.HasPrefix(expr, "-")) {
+		return fmt.Sprintf("%s %s", node.Operator, expr)
 	}
-	return fmt.Sprintf("%s%s", node.Operator, node.Expr.String())
+	return fmt.Sprintf("%s%s", node.Operator, expr)
 }
 
 func (node *UnaryExpr) walkSubtree(visit Visit) error {
@@ -818,7 +1010,12 @@ const (
 
 // String returns the string representation of the node.
 func (node *BinaryExpr) String() string {
-	return fmt.Sprintf("%s%s%s", node.Left.String(), node.Operator, node.Right.String())
+	right := node.Right.String()
+	// a minus operator followed by a negative operand would start a -- comment
+	if node.Operator == MinusStr && strings.HasPrefix(right, "-") {
+		return fmt.Sprintf("%s%s %s", node.Left.String(), node.Operator, right)
+	}
+	return fmt.Sprintf("%s%s%s", node.Left.String(), node.Operator, right)
 }
 
 func (node *BinaryExpr) walkSubtree(visit Visit) error {
@@ -836,6 +1033,8 @@ type CmpExpr struct {
 }
 
 // Operators for CmpExpr.
+// MATCH is only meaningful on the virtual tables of the FTS and R-Tree modules. The parser can't tell
+// whether a table is virtual, so it accepts MATCH on any column and SQLite fails to execute it on regular tables.
 const (
 	EqualStr        = "="
 	LessThanStr     = "<"
@@ -1285,7 +1484,8 @@ func (node *ExistsExpr) walkSubtree(visit Visit) error {
 }
 
 // ColTuple represents a list of column values for IN operator.
-// It can be ValTuple or Subquery.
+// It can be ValTuple, Subquery or Param. A Param is only allowed in read statements,
+// which expand it to a list when resolved.
 type ColTuple interface {
 	iColTuple()
 	Expr
@@ -1293,35 +1493,45 @@ type ColTuple interface {
 
 func (Exprs) iColTuple()     {}
 func (*Subquery) iColTuple() {}
+func (*Param) iColTuple()    {}
 
 // FuncExpr represents a function call.
+// A nil Args represents a call with a star argument, e.g. count(*),
+// and Distinct is only valid when there is at least one argument.
 type FuncExpr struct {
 	Name     Identifier
 	Distinct bool
 	Args     Exprs
 	Filter   *Where
+	Over     *WindowDef
 }
 
 // String returns the string representation of the node.
 func (node *FuncExpr) String() string {
-	var distinct string
-	if node.Distinct {
-		distinct = "distinct "
+	args := "*"
+	if node.Args != nil {
+		strs := make([]string, len(node.Args))
+		for i, arg := range node.Args {
+			strs[i] = arg.String()
+		}
+		args = strings.Join(strs, ",")
+
+		if node.Distinct && len(node.Args) > 0 {
+			args = "distinct " + args
+		}
 	}
 
 	var filter string
-	if node.Filter != nil {
-		filter = nodeStringsConcat("filter(", node.Filter.String()[1:], ")")
+	if node.Filter != nil && node.Filter.Expr != nil {
+		filter = nodeStringsConcat("filter(", node.Filter.String(), ")")
 	}
 
-	var argsStr string
-	if node.Args != nil {
-		argsStr = node.Args.String()
-	} else {
-		argsStr = "(*)"
+	var over string
+	if node.Over != nil {
+		over = node.Over.String()
 	}
 
-	return nodeStringsConcat(node.Name.String(), argsStr[:1]+distinct+argsStr[1:], filter)
+	return nodeStringsConcat(node.Name.String()+"("+args+")", filter, over)
 }
 
 func (node *FuncExpr) walkSubtree(visit Visit) error {
@@ -1329,7 +1539,117 @@ func (node *FuncExpr) walkSubtree(visit Visit) error {
 		return nil
 	}
 
-	return Walk(visit, node.Name, node.Args, node.Filter)
+	return Walk(visit, node.Name, node.Args, node.Filter, node.Over)
+}
+
+// WindowDef represents the window definition of an OVER clause.
+// OVER is only a keyword right after the parens of a function call, or of its FILTER clause, followed by a '('.
+// PARTITION, ROWS, RANGE, GROUPS, UNBOUNDED, PRECEDING, FOLLOWING, CURRENT, ROW, EXCLUDE, NO, OTHERS and TIES
+// are only keywords directly inside the window definition's parens, so a column with one of those names
+// must be parenthesized there, e.g. over(partition by (rows)). Elsewhere, they're regular identifiers.
+type WindowDef struct {
+	PartitionBy Exprs
+	OrderBy     OrderBy
+	Frame       *FrameSpec
+}
+
+// String returns the string representation of the node.
+func (node *WindowDef) String() string {
+	var partitionBy string
+	if len(node.PartitionBy) > 0 {
+		strs := make([]string, len(node.PartitionBy))
+		for i, expr := range node.PartitionBy {
+			strs[i] = expr.String()
+		}
+		partitionBy = nodeStringsConcat("partition by", strings.Join(strs, ","))
+	}
+
+	var frame string
+	if node.Frame != nil {
+		frame = node.Frame.String()
+	}
+
+	return nodeStringsConcat("over(", partitionBy, node.OrderBy.String(), frame, ")")
+}
+
+func (node *WindowDef) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.PartitionBy, node.OrderBy, node.Frame)
+}
+
+// FrameSpec represents the frame specification of a window definition.
+// End is nil if the frame is not specified with BETWEEN, e.g. ROWS UNBOUNDED PRECEDING.
+type FrameSpec struct {
+	Unit    string
+	Start   *FrameBound
+	End     *FrameBound
+	Exclude string
+}
+
+// Units of FrameSpec.
+const (
+	FrameRowsStr   = "rows"
+	FrameRangeStr  = "range"
+	FrameGroupsStr = "groups"
+)
+
+// Exclusions of FrameSpec.
+const (
+	FrameExcludeNoOthersStr   = "exclude no others"
+	FrameExcludeCurrentRowStr = "exclude current row"
+	FrameExcludeGroupStr      = "exclude group"
+	FrameExcludeTiesStr       = "exclude ties"
+)
+
+// String returns the string representation of the node.
+func (node *FrameSpec) String() string {
+	if node.End != nil {
+		return nodeStringsConcat(node.Unit, "between", node.Start.String(), "and", node.End.String(), node.Exclude)
+	}
+	return nodeStringsConcat(node.Unit, node.Start.String(), node.Exclude)
+}
+
+func (node *FrameSpec) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.Start, node.End)
+}
+
+// FrameBound represents the start or the end of a frame.
+// Expr is only set for the PRECEDING and FOLLOWING types.
+type FrameBound struct {
+	Type string
+	Expr Expr
+}
+
+// Types of FrameBound.
+const (
+	FrameUnboundedPrecedingStr = "unbounded preceding"
+	FramePrecedingStr          = "preceding"
+	FrameCurrentRowStr         = "current row"
+	FrameFollowingStr          = "following"
+	FrameUnboundedFollowingStr = "unbounded following"
+)
+
+// String returns the string representation of the node.
+func (node *FrameBound) String() string {
+	if node.Expr != nil {
+		return nodeStringsConcat(node.Expr.String(), node.Type)
+	}
+	return node.Type
+}
+
+func (node *FrameBound) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.Expr)
 }
 
 // CustomFuncExpr represents a function call.
@@ -1340,19 +1660,22 @@ type CustomFuncExpr struct {
 }
 
 // String returns the string representation of the node.
+// The resolved string is treated as an already formatted value. It's rendered as is if it's a single token,
+// e.g. 100 or X'abcd', and parenthesized otherwise, so it keeps its meaning next to operators.
 func (node *CustomFuncExpr) String() string {
-	if node.ResolvedString != "" {
-		return node.ResolvedString
+	if resolved := strings.TrimSpace(node.ResolvedString); resolved != "" {
+		if !isSingleToken(resolved) {
+			return "(" + resolved + ")"
+		}
+		return resolved
 	}
 
-	var argsStr string
+	argsStr := "(*)"
 	if node.Args != nil {
 		argsStr = node.Args.String()
-	} else {
-		argsStr = "(*)"
 	}
 
-	return nodeStringsConcat(node.Name.String(), argsStr[:1]+argsStr[1:])
+	return nodeStringsConcat(node.Name.String(), argsStr)
 }
 
 func (node *CustomFuncExpr) walkSubtree(visit Visit) error {
@@ -1476,9 +1799,15 @@ func (node *CreateTable) walkSubtree(visit Visit) error {
 
 // StructureHash returns the hash of the structure of the statement.
 func (node *CreateTable) StructureHash() string {
-	cols := make([]string, len(node.ColumnsDef))
-	for i := range node.ColumnsDef {
-		cols[i] = fmt.Sprintf("%s:%s", node.ColumnsDef[i].Column.String(), strings.ToUpper(node.ColumnsDef[i].Type))
+	return SchemaHash(node.ColumnsDef)
+}
+
+// SchemaHash returns the hash of a list of column definitions, the same way StructureHash does for CREATE TABLE.
+// Only the columns' names and types, in order, are part of the hash.
+func SchemaHash(columns []*ColumnDef) string {
+	cols := make([]string, len(columns))
+	for i := range columns {
+		cols[i] = fmt.Sprintf("%s:%s", columns[i].Column.String(), strings.ToUpper(columns[i].Type))
 	}
 	stringifiedColDef := strings.Join(cols, ",")
 	sh := sha256.New()
@@ -1487,6 +1816,141 @@ func (node *CreateTable) StructureHash() string {
 	return hex.EncodeToString(hash)
 }
 
+// Apply changes the table definition according to the ALTER TABLE statement, so it describes the table
+// after the ALTER TABLE is executed. Renamed columns are also renamed in the constraints, while dropping
+// a column used by a constraint returns an error, as it does in SQLite.
+// The table definition is not changed if an error is returned.
+func (node *CreateTable) Apply(alter *AlterTable) error {
+	if normalizeIdentifier(alter.Table.Name) != normalizeIdentifier(node.Table.Name) {
+		return &ErrUnknownTable{Name: alter.Table.Name.String()}
+	}
+
+	switch clause := alter.AlterTableClause.(type) {
+	case *AlterTableAdd:
+		if node.columnIndex(clause.ColumnDef.Column.Name) != -1 {
+			return &ErrDuplicateColumnName{Name: clause.ColumnDef.Column.Name.String()}
+		}
+		node.ColumnsDef = append(node.ColumnsDef, cloneNode(clause.ColumnDef).(*ColumnDef))
+	case *AlterTableDrop:
+		i := node.columnIndex(clause.Column.Name)
+		if i == -1 {
+			return &ErrUnknownColumn{Table: node.Table.String(), Column: clause.Column.Name.String()}
+		}
+		if err := node.validateDropColumn(i); err != nil {
+			return err
+		}
+		node.ColumnsDef = append(node.ColumnsDef[:i:i], node.ColumnsDef[i+1:]...)
+	case *AlterTableRename:
+		if node.columnIndex(clause.OldColumn.Name) == -1 {
+			return &ErrUnknownColumn{Table: node.Table.String(), Column: clause.OldColumn.Name.String()}
+		}
+		if node.columnIndex(clause.NewColumn.Name) != -1 {
+			return &ErrDuplicateColumnName{Name: clause.NewColumn.Name.String()}
+		}
+
+		oldName := normalizeIdentifier(clause.OldColumn.Name)
+		// it's ok to ignore the error because the visit function does not throw an error
+		_ = Walk(func(n Node) (bool, error) {
+			if column, ok := n.(*Column); ok && column.TableRef == nil && normalizeIdentifier(column.Name) == oldName {
+				column.Name = clause.NewColumn.Name
+			}
+			return false, nil
+		}, node)
+	default:
+		return fmt.Errorf("unsupported alter table clause: %s", alter.AlterTableClause)
+	}
+
+	return nil
+}
+
+// validateDropColumn checks that the i-th column can be dropped, the same way SQLite does: it can't be part of
+// a PRIMARY KEY or UNIQUE constraint, or be referenced by the constraints of other columns or of the table.
+func (node *CreateTable) validateDropColumn(i int) error {
+	columnDef := node.ColumnsDef[i]
+	for _, constraint := range columnDef.Constraints {
+		switch constraint.(type) {
+		case *ColumnConstraintPrimaryKey, *ColumnConstraintUnique:
+			return &ErrDropColumnInUse{Column: columnDef.Column.Name.String(), Constraint: constraint.String()}
+		}
+	}
+
+	name := normalizeIdentifier(columnDef.Column.Name)
+	references := func(constraint Node) bool {
+		return containsNode(constraint, func(n Node) bool {
+			column, ok := n.(*Column)
+			return ok && column != nil && normalizeIdentifier(column.Name) == name
+		})
+	}
+	for j, other := range node.ColumnsDef {
+		if j == i {
+			continue
+		}
+		for _, constraint := range other.Constraints {
+			if references(constraint) {
+				return &ErrDropColumnInUse{Column: columnDef.Column.Name.String(), Constraint: constraint.String()}
+			}
+		}
+	}
+	for _, constraint := range node.Constraints {
+		if references(constraint) {
+			return &ErrDropColumnInUse{Column: columnDef.Column.Name.String(), Constraint: constraint.String()}
+		}
+	}
+	return nil
+}
+
+// PrimaryKeyColumns returns the names of the columns that form the primary key, whether it is defined
+// as a column constraint or as a table constraint. It returns an empty slice if the table has no primary key.
+func (node *CreateTable) PrimaryKeyColumns() []string {
+	columns := []string{}
+	for _, columnDef := range node.ColumnsDef {
+		if columnDef.HasPrimaryKey() {
+			columns = append(columns, columnDef.Column.Name.String())
+		}
+	}
+
+	for _, constraint := range node.Constraints {
+		if pk, ok := constraint.(*TableConstraintPrimaryKey); ok {
+			for _, indexedColumn := range pk.Columns {
+				columns = append(columns, indexedColumn.Column.Name.String())
+			}
+		}
+	}
+
+	return columns
+}
+
+// columnIndex returns the index of the column definition with the given name, or -1 if there is none.
+func (node *CreateTable) columnIndex(name Identifier) int {
+	for i, column := range node.ColumnsDef {
+		if normalizeIdentifier(column.Column.Name) == normalizeIdentifier(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateView represents a CREATE VIEW statement.
+// Columns is empty if the view's column names are taken from the SELECT.
+type CreateView struct {
+	View    *Table
+	Columns ColumnList
+	Select  ReadStatement
+}
+
+// String returns the string representation of the node.
+func (node *CreateView) String() string {
+	return nodeStringsConcat("create view", node.View.String()+node.Columns.String(), "as", node.Select.String())
+}
+
+func (node *CreateView) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.View, node.Columns, node.Select)
+}
+
 // ColumnDef represents the column definition of a CREATE TABLE statement.
 type ColumnDef struct {
 	Column      *Column
@@ -1890,7 +2354,8 @@ func (node *Insert) walkSubtree(visit Visit) error {
 		return nil
 	}
 
-	if err := Walk(visit, node.Table, node.Columns, node.Upsert, node.Select); err != nil {
+	// the children are visited in the order they appear in the statement
+	if err := Walk(visit, node.Table, node.Columns); err != nil {
 		return err
 	}
 
@@ -1900,7 +2365,7 @@ func (node *Insert) walkSubtree(visit Visit) error {
 		}
 	}
 
-	return Walk(visit, node.Upsert)
+	return Walk(visit, node.Select, node.Upsert)
 }
 
 // Upsert represents an upsert clause, which is a list of on conflict clause.
@@ -2137,6 +2602,12 @@ func (node *Grant) GetPrivileges() Privileges {
 	return node.Privileges
 }
 
+// Triples returns the grant flattened into one triple per role and privilege.
+// Triples are ordered by role, in the order they appear, and then by privilege.
+func (node *Grant) Triples() []GrantTriple {
+	return grantTriples(node.Roles, node.Table, node.Privileges)
+}
+
 func (node *Grant) walkSubtree(visit Visit) error {
 	if node == nil {
 		return nil
@@ -2149,14 +2620,17 @@ type Privileges map[string]struct{}
 
 // String returns the string representation of the node.
 func (node Privileges) String() string {
+	return strings.Join(node.sorted(), ",")
+}
+
+// sorted returns the privileges sorted, because we cannot guarantee map order.
+func (node Privileges) sorted() []string {
 	var privileges []string
 	for priv := range node {
 		privileges = append(privileges, priv)
 	}
-
-	// we cannot guarantee map order, so we sort it so the string is deterministic
 	sort.Strings(privileges)
-	return strings.Join(privileges, ",")
+	return privileges
 }
 
 // Len returns the length of privileges slice.
@@ -2168,6 +2642,24 @@ func (node Privileges) walkSubtree(_ Visit) error {
 	return nil
 }
 
+// GrantTriple is a privilege on a table granted to, or revoked from, a role.
+type GrantTriple struct {
+	Role      string
+	Table     string
+	Privilege string
+}
+
+func grantTriples(roles []string, table *Table, privileges Privileges) []GrantTriple {
+	sortedPrivileges := privileges.sorted()
+	triples := make([]GrantTriple, 0, len(roles)*len(sortedPrivileges))
+	for _, role := range roles {
+		for _, privilege := range sortedPrivileges {
+			triples = append(triples, GrantTriple{Role: role, Table: table.String(), Privilege: privilege})
+		}
+	}
+	return triples
+}
+
 // Revoke represents a REVOKE statement.
 type Revoke struct {
 	Privileges Privileges
@@ -2202,6 +2694,12 @@ func (node *Revoke) GetPrivileges() Privileges {
 	return node.Privileges
 }
 
+// Triples returns the revoke flattened into one triple per role and privilege.
+// Triples are ordered by role, in the order they appear, and then by privilege.
+func (node *Revoke) Triples() []GrantTriple {
+	return grantTriples(node.Roles, node.Table, node.Privileges)
+}
+
 func (node *Revoke) walkSubtree(visit Visit) error {
 	if node == nil {
 		return nil
@@ -2304,6 +2802,145 @@ func (node *AlterTableAdd) walkSubtree(visit Visit) error {
 	return Walk(visit, node.ColumnDef)
 }
 
+// Vacuum represents a VACUUM statement.
+type Vacuum struct {
+	Schema Identifier
+}
+
+// String returns the string representation of the node.
+func (node *Vacuum) String() string {
+	return nodeStringsConcat("vacuum", node.Schema.String())
+}
+
+func (node *Vacuum) walkSubtree(_ Visit) error {
+	return nil
+}
+
+// Analyze represents an ANALYZE statement.
+type Analyze struct {
+	Table *Table
+}
+
+// String returns the string representation of the node.
+func (node *Analyze) String() string {
+	if node.Table == nil {
+		return "analyze"
+	}
+	return nodeStringsConcat("analyze", node.Table.String())
+}
+
+func (node *Analyze) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.Table)
+}
+
+// Reindex represents a REINDEX statement.
+type Reindex struct {
+	Table *Table
+}
+
+// String returns the string representation of the node.
+func (node *Reindex) String() string {
+	if node.Table == nil {
+		return "reindex"
+	}
+	return nodeStringsConcat("reindex", node.Table.String())
+}
+
+func (node *Reindex) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.Table)
+}
+
+// Pragma represents a PRAGMA statement.
+// Value is set for the PRAGMA name = value form, and Arg for the PRAGMA name(arg) form.
+type Pragma struct {
+	Name  Identifier
+	Value Expr
+	Arg   Expr
+}
+
+// String returns the string representation of the node.
+func (node *Pragma) String() string {
+	if node.Value != nil {
+		return nodeStringsConcat("pragma", node.Name.String(), "=", node.Value.String())
+	}
+	if node.Arg != nil {
+		return nodeStringsConcat("pragma", node.Name.String()+"("+node.Arg.String()+")")
+	}
+	return nodeStringsConcat("pragma", node.Name.String())
+}
+
+func (node *Pragma) walkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+
+	return Walk(visit, node.Value, node.Arg)
+}
+
+// IsReadOnly returns true if the pragma only queries the database.
+func (node *Pragma) IsReadOnly() bool {
+	if node.Value != nil {
+		return false
+	}
+
+	name := strings.ToLower(string(node.Name))
+	if node.Arg != nil {
+		_, ok := pragmasWithQueryArg[name]
+		return ok
+	}
+
+	_, ok := readOnlyPragmas[name]
+	return ok
+}
+
+// readOnlyPragmas are pragmas that only query the database when called without an argument or a value.
+// Some of them, like user_version or foreign_keys, also have setting forms, but those are called with
+// a value or an argument, and IsReadOnly handles them before looking up this list.
+var readOnlyPragmas = map[string]struct{}{
+	"application_id":   {},
+	"collation_list":   {},
+	"compile_options":  {},
+	"data_version":     {},
+	"database_list":    {},
+	"encoding":         {},
+	"foreign_key_list": {},
+	"foreign_keys":     {},
+	"freelist_count":   {},
+	"function_list":    {},
+	"index_info":       {},
+	"index_list":       {},
+	"index_xinfo":      {},
+	"journal_mode":     {},
+	"page_count":       {},
+	"page_size":        {},
+	"pragma_list":      {},
+	"schema_version":   {},
+	"table_info":       {},
+	"table_list":       {},
+	"table_xinfo":      {},
+	"user_version":     {},
+}
+
+// pragmasWithQueryArg are pragmas that only query the database when called with an argument.
+// Their argument is the name of the table or index being queried.
+var pragmasWithQueryArg = map[string]struct{}{
+	"foreign_key_list": {},
+	"index_info":       {},
+	"index_list":       {},
+	"index_xinfo":      {},
+	"table_info":       {},
+	"table_list":       {},
+	"table_xinfo":      {},
+}
+
 // resolvers
 
 func resolveReadStatementWalk(node Node, resolver ReadStatementResolver) (string, error) {
@@ -2312,7 +2949,12 @@ func resolveReadStatementWalk(node Node, resolver ReadStatementResolver) (string
 	}
 
 	resolveReadStatementParam := resolveReadStatementParam(resolver)
+	listParams := map[*Param]struct{}{}
 	err := Walk(func(node Node) (bool, error) {
+		if paramNode, ok := isInListParam(node); ok {
+			listParams[paramNode] = struct{}{}
+		}
+
 		if funcExpr, ok := node.(*CustomFuncExpr); ok && funcExpr != nil {
 			resolvedString, err := resolveReadStatementCustomFunc(funcExpr, resolver)
 			if err != nil {
@@ -2322,7 +2964,8 @@ func resolveReadStatementWalk(node Node, resolver ReadStatementResolver) (string
 		}
 
 		if paramNode, ok := node.(*Param); ok {
-			resolvedString, err := resolveReadStatementParam()
+			_, isList := listParams[paramNode]
+			resolvedString, err := resolveReadStatementParam(isList)
 			if err != nil {
 				return true, fmt.Errorf("resolve read statement: %s", err)
 			}
@@ -2343,18 +2986,9 @@ func resolveReadStatementCustomFunc(node *CustomFuncExpr, resolver ReadStatement
 			return "", errors.New("block_num function should have exactly one argument")
 		}
 
-		value, ok := node.Args[0].(*Value)
-		if !ok {
-			return "", errors.New("argument of block_num is not a literal value")
-		}
-
-		if value.Type != IntValue {
-			return "", errors.New("argument of block_num is not an integer")
-		}
-
-		chainID, err := strconv.ParseInt(string(value.Value), 10, 64)
+		chainID, err := blockNumChainID(node.Args[0])
 		if err != nil {
-			return "", fmt.Errorf("parsing argument to int: %s", err)
+			return "", err
 		}
 		blockNumber, exists := resolver.GetBlockNumber(chainID)
 		if !exists {
@@ -2370,16 +3004,29 @@ func resolveReadStatementCustomFunc(node *CustomFuncExpr, resolver ReadStatement
 
 // resolveReadStatementParam returns a function that acts like an iterator.
 // Every time the function is called it gets the next bind value.
-func resolveReadStatementParam(resolver ReadStatementResolver) func() (string, error) {
+// When isList is true, the value is resolved as a list for the IN operator.
+func resolveReadStatementParam(resolver ReadStatementResolver) func(isList bool) (string, error) {
 	bindValues := resolver.GetBindValues()
 	i := 0
 
-	return func() (string, error) {
+	return func(isList bool) (string, error) {
+		if isList {
+			if listResolver, ok := resolver.(ReadStatementBindListResolver); ok {
+				if list := listResolver.GetBindList(i); list != nil {
+					i++
+					return Exprs(list).String(), nil
+				}
+			}
+		}
+
 		if i >= len(bindValues) {
 			return "", fmt.Errorf("number of params is greater than the number of bind values")
 		}
 
 		s := bindValues[i].String()
+		if isList {
+			s = Exprs{bindValues[i]}.String()
+		}
 		i++
 
 		return s, nil
@@ -2414,11 +3061,24 @@ func resolveWriteStatement(node *CustomFuncExpr, resolver WriteStatementResolver
 			return "", errors.New("block_num arguments cannot be nil")
 		}
 
-		if len(node.Args) != 0 {
-			return "", errors.New("block_num function should have exactly zero arguments")
+		if len(node.Args) > 1 {
+			return "", errors.New("block_num function should have zero or one argument")
 		}
 
 		blockNumber := resolver.GetBlockNumber()
+		if len(node.Args) == 1 {
+			chainID, err := blockNumChainID(node.Args[0])
+			if err != nil {
+				return "", err
+			}
+
+			var exists bool
+			blockNumber, exists = resolver.GetBlockNumberForChain(chainID)
+			if !exists {
+				return "", errors.New("chain id does not exist")
+			}
+		}
+
 		valueNode := &Value{Type: IntValue, Value: []byte(strconv.Itoa(int(blockNumber)))}
 		return valueNode.String(), nil
 	case "txn_hash":
@@ -2437,3 +3097,22 @@ func resolveWriteStatement(node *CustomFuncExpr, resolver WriteStatementResolver
 
 	return "", fmt.Errorf("custom function %s is not resolvable", node.Name)
 }
+
+// blockNumChainID returns the chain id passed as argument to block_num.
+func blockNumChainID(arg Expr) (int64, error) {
+	value, ok := arg.(*Value)
+	if !ok {
+		return 0, errors.New("argument of block_num is not a literal value")
+	}
+
+	if value.Type != IntValue {
+		return 0, errors.New("argument of block_num is not an integer")
+	}
+
+	chainID, err := strconv.ParseInt(string(value.Value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing argument to int: %s", err)
+	}
+
+	return chainID, nil
+}
@@ -0,0 +1,273 @@
+package sqlparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// SelectBuilder builds a SELECT statement programmatically.
+// The table, column and alias names are validated by Build, so a name can't change the statement's structure.
+type SelectBuilder struct {
+	node   *Select
+	offset *Value
+}
+
+// NewSelect creates a new SELECT builder.
+func NewSelect() *SelectBuilder {
+	return &SelectBuilder{node: &Select{SelectColumnList: SelectColumnList{}}}
+}
+
+// Distinct makes the SELECT distinct.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	b.node.Distinct = DistinctStr
+	return b
+}
+
+// Columns adds columns to the SELECT column list. The name "*" adds a star column.
+func (b *SelectBuilder) Columns(names ...string) *SelectBuilder {
+	for _, name := range names {
+		if name == "*" {
+			b.node.SelectColumnList = append(b.node.SelectColumnList, &StarSelectColumn{})
+			continue
+		}
+		b.node.SelectColumnList = append(b.node.SelectColumnList, &AliasedSelectColumn{Expr: Col(name)})
+	}
+	return b
+}
+
+// Exprs adds expressions to the SELECT column list.
+func (b *SelectBuilder) Exprs(exprs ...Expr) *SelectBuilder {
+	for _, expr := range exprs {
+		b.node.SelectColumnList = append(b.node.SelectColumnList, &AliasedSelectColumn{Expr: expr})
+	}
+	return b
+}
+
+// ExprAs adds an aliased expression to the SELECT column list.
+func (b *SelectBuilder) ExprAs(expr Expr, alias string) *SelectBuilder {
+	b.node.SelectColumnList = append(b.node.SelectColumnList, &AliasedSelectColumn{Expr: expr, As: Identifier(alias)})
+	return b
+}
+
+// From sets the table the SELECT reads from.
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.node.From = &AliasedTableExpr{Expr: &Table{Name: Identifier(table), IsTarget: true}}
+	return b
+}
+
+// FromAs sets the table the SELECT reads from with an alias.
+func (b *SelectBuilder) FromAs(table string, alias string) *SelectBuilder {
+	b.node.From = &AliasedTableExpr{Expr: &Table{Name: Identifier(table), IsTarget: true}, As: Identifier(alias)}
+	return b
+}
+
+// Where sets the WHERE clause.
+func (b *SelectBuilder) Where(expr Expr) *SelectBuilder {
+	b.node.Where = NewWhere(WhereStr, expr)
+	return b
+}
+
+// GroupBy sets the GROUP BY clause.
+func (b *SelectBuilder) GroupBy(exprs ...Expr) *SelectBuilder {
+	b.node.GroupBy = GroupBy(exprs)
+	return b
+}
+
+// Having sets the HAVING clause.
+func (b *SelectBuilder) Having(expr Expr) *SelectBuilder {
+	b.node.Having = NewWhere(HavingStr, expr)
+	return b
+}
+
+// OrderBy appends ordering terms to the ORDER BY clause.
+func (b *SelectBuilder) OrderBy(terms ...*OrderingTerm) *SelectBuilder {
+	b.node.OrderBy = append(b.node.OrderBy, terms...)
+	return b
+}
+
+// Limit sets the LIMIT clause.
+func (b *SelectBuilder) Limit(limit int64) *SelectBuilder {
+	if b.node.Limit == nil {
+		b.node.Limit = &Limit{}
+	}
+	b.node.Limit.Limit = Int(limit)
+	return b
+}
+
+// Offset sets the OFFSET of the LIMIT clause. Build returns an error if the limit isn't set.
+func (b *SelectBuilder) Offset(offset int64) *SelectBuilder {
+	b.offset = Int(offset)
+	return b
+}
+
+// Build returns the built SELECT statement.
+// It returns an error if a table, column or alias name isn't a valid identifier, e.g. "t; drop table x",
+// or if the offset is set without a limit.
+func (b *SelectBuilder) Build() (*Select, error) {
+	if b.offset != nil {
+		if b.node.Limit == nil {
+			return nil, errors.New("offset requires a limit")
+		}
+		b.node.Limit.Offset = b.offset
+	}
+
+	if err := Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *Column:
+			return false, validateBuilderIdentifier(node.Name)
+		case *Table:
+			if node != nil {
+				return false, validateBuilderIdentifier(node.Name)
+			}
+		case *AliasedSelectColumn:
+			if !node.As.IsEmpty() {
+				return false, validateBuilderIdentifier(node.As)
+			}
+		case *AliasedTableExpr:
+			if !node.As.IsEmpty() {
+				return false, validateBuilderIdentifier(node.As)
+			}
+		}
+		return false, nil
+	}, b.node); err != nil {
+		return nil, err
+	}
+
+	return b.node, nil
+}
+
+// String returns the string representation of the built statement, or an empty string if Build fails.
+func (b *SelectBuilder) String() string {
+	node, err := b.Build()
+	if err != nil {
+		return ""
+	}
+	return node.String()
+}
+
+// validateBuilderIdentifier checks that the name is lexed as a single identifier, the same way
+// the parser would read it back, and that it's not a keyword that isn't allowed as an identifier.
+func validateBuilderIdentifier(name Identifier) error {
+	// the lexer doesn't start at a statement, so VACUUM and the other statement keywords are identifiers
+	lexer := &Lexer{lastToken: IDENTIFIER}
+	lexer.input = []byte(name)
+	lexer.readByte()
+
+	var lval yySymType
+	if lexer.Lex(&lval) != IDENTIFIER || string(lval.bytes) != string(name) || lexer.Lex(&lval) != EOF {
+		return &ErrInvalidIdentifier{Name: string(name)}
+	}
+	if _, ok := keywordsNotAllowed[strings.ToUpper(string(name))]; ok {
+		return &ErrKeywordIsNotAllowed{Keyword: string(name)}
+	}
+	return nil
+}
+
+// Col creates a column. A name in the form "table.column" creates a column with a table reference.
+func Col(name string) *Column {
+	if table, column, found := strings.Cut(name, "."); found {
+		return &Column{Name: Identifier(column), TableRef: &Table{Name: Identifier(table)}}
+	}
+	return &Column{Name: Identifier(name)}
+}
+
+// Int creates an integer literal.
+func Int(value int64) *Value {
+	return &Value{Type: IntValue, Value: []byte(strconv.FormatInt(value, 10))}
+}
+
+// Str creates a string literal. Single quotes are escaped.
+func Str(value string) *Value {
+	return &Value{Type: StrValue, Value: []byte(strings.ReplaceAll(value, "'", "''"))}
+}
+
+// Null creates a NULL literal.
+func Null() *NullValue {
+	return &NullValue{}
+}
+
+// Bool creates a boolean literal.
+func Bool(value bool) BoolValue {
+	return BoolValue(value)
+}
+
+// Eq creates an equality comparison.
+func Eq(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: EqualStr, Left: left, Right: right}
+}
+
+// Ne creates an inequality comparison.
+func Ne(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: NotEqualStr, Left: left, Right: right}
+}
+
+// Lt creates a less than comparison.
+func Lt(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: LessThanStr, Left: left, Right: right}
+}
+
+// Le creates a less than or equal comparison.
+func Le(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: LessEqualStr, Left: left, Right: right}
+}
+
+// Gt creates a greater than comparison.
+func Gt(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: GreaterThanStr, Left: left, Right: right}
+}
+
+// Ge creates a greater than or equal comparison.
+func Ge(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: GreaterEqualStr, Left: left, Right: right}
+}
+
+// In creates an IN comparison against a list of values.
+func In(left Expr, values ...Expr) *CmpExpr {
+	return &CmpExpr{Operator: InStr, Left: left, Right: Exprs(values)}
+}
+
+// Like creates a LIKE comparison.
+func Like(left, right Expr) *CmpExpr {
+	return &CmpExpr{Operator: LikeStr, Left: left, Right: right}
+}
+
+// And combines the expressions with AND. The expressions are grouped from left to right,
+// the same way the parser does.
+func And(left, right Expr, others ...Expr) *AndExpr {
+	expr := &AndExpr{Left: left, Right: right}
+	for _, other := range others {
+		expr = &AndExpr{Left: expr, Right: other}
+	}
+	return expr
+}
+
+// Or combines the expressions with OR. The expressions are grouped from left to right,
+// the same way the parser does.
+func Or(left, right Expr, others ...Expr) *OrExpr {
+	expr := &OrExpr{Left: left, Right: right}
+	for _, other := range others {
+		expr = &OrExpr{Left: expr, Right: other}
+	}
+	return expr
+}
+
+// Not negates the expression.
+func Not(expr Expr) *NotExpr {
+	return &NotExpr{Expr: expr}
+}
+
+// Paren wraps the expression in parentheses.
+func Paren(expr Expr) *ParenExpr {
+	return &ParenExpr{Expr: expr}
+}
+
+// Asc creates an ascending ordering term.
+func Asc(expr Expr) *OrderingTerm {
+	return &OrderingTerm{Expr: expr, Direction: AscStr}
+}
+
+// Desc creates a descending ordering term.
+func Desc(expr Expr) *OrderingTerm {
+	return &OrderingTerm{Expr: expr, Direction: DescStr}
+}
@@ -0,0 +1,127 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		builder  *SelectBuilder
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "columns",
+			builder:  NewSelect().Columns("a", "b").From("t"),
+			deparsed: "select a,b from t",
+		},
+		{
+			name:     "star",
+			builder:  NewSelect().Columns("*").From("t"),
+			deparsed: "select * from t",
+		},
+		{
+			name:     "where",
+			builder:  NewSelect().Columns("a", "b").From("t").Where(Eq(Col("a"), Int(1))),
+			deparsed: "select a,b from t where a=1",
+		},
+		{
+			name: "where and or",
+			builder: NewSelect().Columns("a").From("t").Where(
+				Or(And(Gt(Col("a"), Int(1)), Le(Col("b"), Int(-2)), Ne(Col("c"), Str("x"))), Like(Col("d"), Str("%y"))),
+			),
+			deparsed: "select a from t where a>1 and b<=-2 and c!='x' or d like '%y'",
+		},
+		{
+			name:     "string escaping",
+			builder:  NewSelect().Columns("a").From("t").Where(Eq(Col("a"), Str("it's"))),
+			deparsed: "select a from t where a='it''s'",
+		},
+		{
+			name:     "qualified column and alias",
+			builder:  NewSelect().Exprs(Col("t.a")).ExprAs(Col("b"), "c").FromAs("t", "t"),
+			deparsed: "select t.a,b as c from t as t",
+		},
+		{
+			name: "in and paren",
+			builder: NewSelect().Distinct().Columns("a").From("t").Where(
+				And(In(Col("a"), Int(1), Int(2)), Paren(Or(Eq(Col("b"), Null()), Eq(Col("c"), Bool(true))))),
+			),
+			deparsed: "select distinct a from t where a in(1,2)and(b=null or c=true)",
+		},
+		{
+			name: "group by having order by limit offset",
+			builder: NewSelect().Columns("a").From("t").
+				GroupBy(Col("a")).
+				Having(Ge(Col("a"), Int(0))).
+				OrderBy(Asc(Col("a")), Desc(Col("b"))).
+				Limit(10).
+				Offset(5),
+			deparsed: "select a from t group by a having a>=0 order by a asc,b desc limit 10 offset 5",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				stmt, err := tc.builder.Build()
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, stmt.String())
+				require.Equal(t, tc.deparsed, tc.builder.String())
+
+				ast, err := Parse(stmt.String())
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, &AST{Statements: []Statement{stmt}}, ast)
+			}
+		}(tc))
+	}
+
+	t.Run("hostile names", func(t *testing.T) {
+		t.Parallel()
+
+		for _, builder := range []*SelectBuilder{
+			NewSelect().Columns("a").From("t; drop table x --"),
+			NewSelect().Columns("a").From("t").Where(Eq(Col("a) or (1"), Int(1))),
+			NewSelect().Columns("a /* x */").From("t"),
+			NewSelect().Columns("a").FromAs("t", "u where 1=1"),
+			NewSelect().ExprAs(Col("a"), "b from t --").From("t"),
+			NewSelect().Columns("[a] or 1").From("t"),
+			NewSelect().Columns("a").From("t").Where(Eq(Col("t.a.b"), Int(1))),
+		} {
+			stmt, err := builder.Build()
+			require.ErrorAs(t, err, new(*ErrInvalidIdentifier))
+			require.Nil(t, stmt)
+			require.Empty(t, builder.String())
+		}
+
+		_, err := NewSelect().Columns("current_time").From("t").Build()
+		require.ErrorAs(t, err, new(*ErrKeywordIsNotAllowed))
+	})
+
+	t.Run("quoted and keyword-like names", func(t *testing.T) {
+		t.Parallel()
+
+		stmt, err := NewSelect().Columns("[a]", "`b`", `"c"`, "analyze").From("t_1_2").Build()
+		require.NoError(t, err)
+		require.Equal(t, "select [a],`b`,\"c\",analyze from t_1_2", stmt.String())
+	})
+
+	t.Run("offset without limit", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSelect().Columns("a").From("t").Offset(5).Build()
+		require.EqualError(t, err, "offset requires a limit")
+
+		stmt, err := NewSelect().Columns("a").From("t").Offset(5).Limit(10).Build()
+		require.NoError(t, err)
+		require.Equal(t, "select a from t limit 10 offset 5", stmt.String())
+	})
+}
@@ -2,8 +2,6 @@
 package main
 
 import (
-	"regexp"
-	"strings"
 	"syscall/js"
 
 	"github.com/tablelandnetwork/sqlparser"
@@ -12,8 +10,7 @@ import (
 const GLOBAL_NAME = "sqlparser"
 
 var (
-	maxQuerySize   = 35000
-	tableNameRegEx = regexp.MustCompile("^([A-Za-z]+[A-Za-z0-9_.]*)*$")
+	maxQuerySize = 35000
 )
 
 type StatementType string
@@ -23,48 +20,9 @@ const (
 	Read   StatementType = "read"
 	Write  StatementType = "write"
 	Acl    StatementType = "acl"
+	Admin  StatementType = "admin"
 )
 
-type EnclosingType struct {
-	open  string
-	close string
-}
-
-func getEnclosures() []EnclosingType {
-	return []EnclosingType{
-		{open: "`", close: "`"},
-		{open: "\"", close: "\""},
-		{open: "[", close: "]"},
-	}
-}
-
-// UpdateTableNames mutates a Node in place, mapping a set of input table names to output table names.
-func UpdateTableNames(node sqlparser.Node, nameMapper func(string) (string, bool)) (sqlparser.Node, error) {
-	if node == nil {
-		return node, nil
-	}
-	if err := sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
-		if table, ok := node.(*sqlparser.Table); ok && table != nil {
-			if tableName, ok := nameMapper(table.Name.String()); ok {
-
-				// to do name format validation we have to take it out of the enclosure
-				tableName, enclosure, isEnclosed := getEnclosedName(tableName)
-				if !tableNameRegEx.MatchString(tableName) {
-					return true, &sqlparser.ErrTableNameWrongFormat{Name: tableName}
-				}
-				if isEnclosed {
-					tableName = enclosure.open + tableName + enclosure.close
-				}
-				table.Name = sqlparser.Identifier(tableName)
-			}
-		}
-		return false, nil
-	}, node); err != nil {
-		return nil, err
-	}
-	return node, nil
-}
-
 func validateTableName(this js.Value, args []js.Value) interface{} {
 	Error := js.Global().Get("Error")
 	Promise := js.Global().Get("Promise")
@@ -157,21 +115,8 @@ func normalize(this js.Value, args []js.Value) interface{} {
 				return reject.Invoke(Error.New("error parsing statement: empty string"))
 			}
 			if !nameMap.IsUndefined() {
-				if _, err := UpdateTableNames(ast, func(name string) (string, bool) {
-					// take the name and see if it's captured by any of our enclosure characters
-					// if so, map what's inside the enclosure, if not try to map the original name
-					var value js.Value
-					_name, enclosure, isEnclosed := getEnclosedName(name)
-
-					if isEnclosed {
-						value = nameMap.Get(_name)
-						if value.IsUndefined() {
-							return "", false
-						}
-						return enclosure.open + value.String() + enclosure.close, true
-					}
-
-					value = nameMap.Get(name)
+				if err := sqlparser.RenameTables(ast, func(name string) (string, bool) {
+					value := nameMap.Get(name)
 					if value.IsUndefined() {
 						return "", false
 					}
@@ -186,8 +131,8 @@ func normalize(this js.Value, args []js.Value) interface{} {
 			statements := make([]interface{}, len(ast.Statements))
 			var statementType StatementType
 			for i, stmt := range ast.Statements {
-				switch stmt.(type) {
-				case sqlparser.CreateTableStatement:
+				switch stmt := stmt.(type) {
+				case sqlparser.CreateTableStatement, *sqlparser.CreateView:
 					statementType = Create
 				case sqlparser.ReadStatement:
 					statementType = Read
@@ -197,14 +142,21 @@ func normalize(this js.Value, args []js.Value) interface{} {
 					}
 				case sqlparser.WriteStatement:
 					statementType = Write
+				case sqlparser.AdminStatement:
+					statementType = Admin
+				case *sqlparser.Pragma:
+					if stmt.IsReadOnly() {
+						statementType = Read
+					} else {
+						statementType = Admin
+					}
 				}
 				statements[i] = stmt.String()
 			}
 			tableReferences := sqlparser.GetUniqueTableReferences(ast)
 			tables := make([]interface{}, len(tableReferences))
 			for i := range tableReferences {
-				_name, _, _ := getEnclosedName(tableReferences[i])
-				tables[i] = _name
+				tables[i], _, _ = sqlparser.UnquoteTableName(tableReferences[i])
 			}
 			response := map[string]interface{}{
 				"type":       string(statementType),
@@ -218,21 +170,6 @@ func normalize(this js.Value, args []js.Value) interface{} {
 	return Promise.New(handler)
 }
 
-func getEnclosedName(name string) (string, EnclosingType, bool) {
-	var _name string
-	var _enclosure EnclosingType
-	for _, encloseChar := range getEnclosures() {
-		if strings.HasPrefix(name, encloseChar.open) && strings.HasSuffix(name, encloseChar.close) {
-			_, _name, _ = strings.Cut(name, encloseChar.open)
-			_name, _, _ = strings.Cut(_name, encloseChar.close)
-			_enclosure = encloseChar
-			return _name, _enclosure, true
-		}
-	}
-
-	return name, EnclosingType{open: "", close: ""}, false
-}
-
 func main() {
 	// Outer object is exported globally and contains these keys
 	js.Global().Set(GLOBAL_NAME, js.ValueOf(map[string]interface{}{
@@ -1,10 +1,16 @@
 package sqlparser
 
 const (
-	// MaxTextLength is the limit for the length of a TEXT literal value.
+	// MaxTextLength is the limit for the length of a TEXT literal value, in bytes of its UTF-8 encoding,
+	// the same unit SQLite uses for SQLITE_MAX_LENGTH. A non-ASCII character may take up to 4 bytes.
 	MaxTextLength = 1024
 	// MaxBlobLength is the limit for the length of a BLOB literal value.
 	MaxBlobLength = 1024
 	// MaxAllowedColumns is the limit for the number of columns in a CREATE TABLE statement.
 	MaxAllowedColumns = 24
+	// MaxAllowedRoles is the default limit for the number of roles in a GRANT or REVOKE statement.
+	MaxAllowedRoles = 24
+	// MaxLikePatternLength is the default limit for the length of a LIKE or GLOB pattern,
+	// the same as SQLite's SQLITE_MAX_LIKE_PATTERN_LENGTH.
+	MaxLikePatternLength = 50000
 )
@@ -0,0 +1,257 @@
+package sqlparser
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// DeparseOptions controls how Deparse renders a node.
+// The zero value renders the same string as the node's String method.
+type DeparseOptions struct {
+	// SortRoles renders GRANT and REVOKE roles in lexicographical order,
+	// so semantically equal statements are rendered identically.
+	SortRoles bool
+
+	// BoolAsInt renders the TRUE and FALSE literals as 1 and 0, for SQLite versions older than 3.23.0.
+	// The IS TRUE and IS FALSE operators are kept, because IS 1 and IS 0 have a different meaning.
+	BoolAsInt bool
+
+	// NormalizeLimit renders LIMIT offset, limit as LIMIT limit OFFSET offset.
+	// By default the LIMIT is rendered with the syntax it was written with.
+	NormalizeLimit bool
+
+	// CanonicalizeNumbers renders hexadecimal integer literals in decimal, e.g. 0x10 as 16,
+	// so equal numbers are rendered identically. Literals greater than the maximum int64 are kept as they are,
+	// because SQLite reads them as negative numbers.
+	CanonicalizeNumbers bool
+
+	// FullyParenthesize renders every operator expression and its operands in parentheses,
+	// e.g. a+b*c as ((a)+((b)*(c))), to debug precedence issues. The output parses to an equivalent AST,
+	// which has the same semantics. The IN lists, the ESCAPE literal of LIKE and the TRUE and FALSE of IS TRUE
+	// and IS FALSE are not parenthesized, because that would change their meaning.
+	FullyParenthesize bool
+}
+
+// Deparse returns the string representation of the node according to the options.
+// The node is not modified.
+func Deparse(node Node, opts DeparseOptions) string {
+	if node == nil {
+		return ""
+	}
+
+	node = cloneNode(node)
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *Grant:
+			if opts.SortRoles && node != nil {
+				sort.Strings(node.Roles)
+			}
+		case *Revoke:
+			if opts.SortRoles && node != nil {
+				sort.Strings(node.Roles)
+			}
+		case *Limit:
+			if opts.NormalizeLimit && node != nil {
+				node.CommaSyntax = false
+			}
+		case *Value:
+			if opts.CanonicalizeNumbers && node != nil && node.Type == HexNumValue {
+				hexToDecimal(node)
+			}
+		}
+		return false, nil
+	}, node)
+
+	if opts.BoolAsInt {
+		if b, ok := node.(BoolValue); ok {
+			node = boolAsInt(b)
+		}
+		replaceBools(reflect.ValueOf(node))
+	}
+
+	if opts.FullyParenthesize {
+		parenthesize(reflect.ValueOf(node), false)
+		if expr, ok := node.(Expr); ok && exprPrecedence(expr) < atomicPrecedence {
+			node = &ParenExpr{Expr: expr}
+		}
+	}
+
+	return node.String()
+}
+
+// parenthesize wraps the operator expressions held by v's children, and their operands, in parentheses.
+// operand is true if v's children are operands of an operator.
+func parenthesize(v reflect.Value, operand bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		switch node := v.Interface().(type) {
+		case *ParenExpr:
+			// the expression is already parenthesized, so only its children are
+			parenthesize(v.Elem().FieldByName("Expr").Elem(), false)
+			return
+		case *CmpExpr:
+			parenthesize(v.Elem().FieldByName("Left"), true)
+			if node.Operator != InStr && node.Operator != NotInStr {
+				parenthesize(v.Elem().FieldByName("Right"), true)
+			} else {
+				parenthesize(v.Elem().FieldByName("Right").Elem(), false)
+			}
+			return
+		case *IsExpr:
+			parenthesize(v.Elem().FieldByName("Left"), true)
+			if isBoolOperand(node.Right) {
+				return
+			}
+			if not, ok := node.Right.(*NotExpr); ok {
+				// the NOT of IS NOT is part of the operator
+				parenthesize(reflect.ValueOf(not).Elem().FieldByName("Expr"), true)
+				return
+			}
+			parenthesize(v.Elem().FieldByName("Right"), true)
+			return
+		case Expr:
+			parenthesize(v.Elem(), exprPrecedence(node) < atomicPrecedence)
+			return
+		}
+		parenthesize(v.Elem(), false)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		parenthesize(v.Elem(), false)
+		expr, ok := v.Interface().(Expr)
+		if !ok || !v.CanSet() {
+			return
+		}
+		if exprPrecedence(expr) < atomicPrecedence || (operand && isParenthesizableOperand(expr)) {
+			if paren := reflect.ValueOf(&ParenExpr{Expr: expr}); paren.Type().AssignableTo(v.Type()) {
+				v.Set(paren)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			parenthesize(v.Index(i), false)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				parenthesize(v.Field(i), operand)
+			}
+		}
+	}
+}
+
+// isParenthesizableOperand checks if an operand that isn't an operator expression can be parenthesized.
+// Lists and subqueries already have parentheses.
+func isParenthesizableOperand(expr Expr) bool {
+	switch expr.(type) {
+	case *ParenExpr, Exprs, *Subquery:
+		return false
+	}
+	return true
+}
+
+// hexToDecimal converts a hexadecimal integer literal into a decimal one, if it fits in an int64.
+func hexToDecimal(value *Value) {
+	n, err := strconv.ParseUint(string(value.Value[2:]), 16, 63)
+	if err != nil {
+		return
+	}
+	value.Type = IntValue
+	value.Value = []byte(strconv.FormatUint(n, 10))
+}
+
+// replaceBools replaces the boolean literals held by v's children with integer literals.
+func replaceBools(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if is, ok := v.Interface().(*IsExpr); ok {
+			replaceBools(v.Elem().FieldByName("Left"))
+			if !isBoolOperand(is.Right) {
+				replaceBools(v.Elem().FieldByName("Right"))
+			}
+			return
+		}
+		replaceBools(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if b, ok := v.Interface().(BoolValue); ok {
+			if value := reflect.ValueOf(boolAsInt(b)); v.CanSet() && value.Type().AssignableTo(v.Type()) {
+				v.Set(value)
+			}
+			return
+		}
+		replaceBools(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			replaceBools(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				replaceBools(v.Field(i))
+			}
+		}
+	}
+}
+
+// isBoolOperand checks if the right operand of an IS expression is TRUE or FALSE, including the NOT of IS NOT.
+func isBoolOperand(expr Expr) bool {
+	if not, ok := expr.(*NotExpr); ok {
+		expr = not.Expr
+	}
+	_, ok := expr.(BoolValue)
+	return ok
+}
+
+func boolAsInt(b BoolValue) *Value {
+	if b {
+		return &Value{Type: IntValue, Value: []byte("1")}
+	}
+	return &Value{Type: IntValue, Value: []byte("0")}
+}
+
+// WriteTo writes the string representation of the node to w and returns the number of bytes written.
+// An AST is written one statement at a time, so the string of the whole AST is never built in memory.
+func WriteTo(w io.Writer, node Node) (int64, error) {
+	if node == nil {
+		return 0, nil
+	}
+
+	ast, ok := node.(*AST)
+	if !ok {
+		n, err := io.WriteString(w, node.String())
+		return int64(n), err
+	}
+
+	var written int64
+	for i, stmt := range ast.Statements {
+		if i > 0 {
+			n, err := io.WriteString(w, ";")
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+
+		n, err := io.WriteString(w, stmt.String())
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,370 @@
+package sqlparser
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeparse(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		opts     DeparseOptions
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "grant default options",
+			stmt:     "GRANT INSERT on t TO 'b', 'a'",
+			opts:     DeparseOptions{},
+			deparsed: "grant insert on t to 'b', 'a'",
+		},
+		{
+			name:     "grant sort roles",
+			stmt:     "GRANT INSERT, DELETE on t TO 'c', 'a', 'b'",
+			opts:     DeparseOptions{SortRoles: true},
+			deparsed: "grant delete,insert on t to 'a', 'b', 'c'",
+		},
+		{
+			name:     "revoke sort roles",
+			stmt:     "REVOKE UPDATE ON t FROM 'b', 'a'",
+			opts:     DeparseOptions{SortRoles: true},
+			deparsed: "revoke update on t from 'a', 'b'",
+		},
+		{
+			name:     "multiple statements sort roles",
+			stmt:     "GRANT INSERT on t TO 'b', 'a'; REVOKE UPDATE ON t FROM 'd', 'c'",
+			opts:     DeparseOptions{SortRoles: true},
+			deparsed: "grant insert on t to 'a', 'b';revoke update on t from 'c', 'd'",
+		},
+		{
+			name:     "limit comma syntax is kept",
+			stmt:     "SELECT a FROM t LIMIT 1, 2",
+			deparsed: "select a from t limit 1,2",
+		},
+		{
+			name:     "limit comma syntax normalized",
+			stmt:     "SELECT a FROM t LIMIT 1, 2",
+			opts:     DeparseOptions{NormalizeLimit: true},
+			deparsed: "select a from t limit 2 offset 1",
+		},
+		{
+			name:     "limit offset syntax is kept",
+			stmt:     "SELECT a FROM t LIMIT 2 OFFSET 1",
+			opts:     DeparseOptions{NormalizeLimit: true},
+			deparsed: "select a from t limit 2 offset 1",
+		},
+		{
+			name:     "limit comma syntax in subquery normalized",
+			stmt:     "SELECT a FROM t WHERE a IN (SELECT b FROM t2 LIMIT ?, ?)",
+			opts:     DeparseOptions{NormalizeLimit: true},
+			deparsed: "select a from t where a in(select b from t2 limit ? offset ?)",
+		},
+		{
+			name:     "hex numbers are kept by default",
+			stmt:     "SELECT 0x10 FROM t WHERE a = 0XfF",
+			deparsed: "select 0x10 from t where a=0XfF",
+		},
+		{
+			name:     "canonicalize numbers",
+			stmt:     "SELECT 0x10, -0x1, 0x0 FROM t WHERE a = 0XfF LIMIT 0x2",
+			opts:     DeparseOptions{CanonicalizeNumbers: true},
+			deparsed: "select 16,-1,0 from t where a=255 limit 2",
+		},
+		{
+			name:     "canonicalize max int64",
+			stmt:     "SELECT 0x7fffffffffffffff FROM t",
+			opts:     DeparseOptions{CanonicalizeNumbers: true},
+			deparsed: "select 9223372036854775807 from t",
+		},
+		{
+			name:     "hex numbers greater than max int64 are kept",
+			stmt:     "SELECT 0x8000000000000000, 0xffffffffffffffff FROM t",
+			opts:     DeparseOptions{CanonicalizeNumbers: true},
+			deparsed: "select 0x8000000000000000,0xffffffffffffffff from t",
+		},
+		{
+			name:     "hex numbers in writes",
+			stmt:     "INSERT INTO t VALUES (0x1a); UPDATE t SET a = 0x00ff WHERE b = 1",
+			opts:     DeparseOptions{CanonicalizeNumbers: true},
+			deparsed: "insert into t values(26);update t set a=255 where b=1",
+		},
+		{
+			name:     "bools",
+			stmt:     "SELECT true FROM t WHERE a = FALSE",
+			deparsed: "select true from t where a=false",
+		},
+		{
+			name:     "bools as ints",
+			stmt:     "SELECT true FROM t WHERE a = FALSE",
+			opts:     DeparseOptions{BoolAsInt: true},
+			deparsed: "select 1 from t where a=0",
+		},
+		{
+			name:     "bools as ints in nested expressions",
+			stmt:     "SELECT CASE WHEN a THEN true ELSE false END, coalesce(a, true) FROM t WHERE a IN (true, false) AND (b OR TRUE)",
+			opts:     DeparseOptions{BoolAsInt: true},
+			deparsed: "select case when a then 1 else 0 end,coalesce(a,1)from t where a in(1,0)and(b or 1)",
+		},
+		{
+			name:     "bools as ints in writes",
+			stmt:     "INSERT INTO t (a, b) VALUES (true, false); UPDATE t SET a = true WHERE b = false",
+			opts:     DeparseOptions{BoolAsInt: true},
+			deparsed: "insert into t(a,b)values(1,0);update t set a=1 where b=0",
+		},
+		{
+			name:     "is true is kept",
+			stmt:     "SELECT a IS TRUE, a IS NOT FALSE, true IS a FROM t",
+			opts:     DeparseOptions{BoolAsInt: true},
+			deparsed: "select a is true,a is not false,1 is a from t",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				original := ast.String()
+				require.Equal(t, tc.deparsed, Deparse(ast, tc.opts))
+
+				// the ast is not modified
+				require.Equal(t, original, ast.String())
+			}
+		}(tc))
+	}
+
+	t.Run("equal grants", func(t *testing.T) {
+		t.Parallel()
+		ast1, err := Parse("GRANT INSERT, UPDATE on t TO 'a', 'b'")
+		require.NoError(t, err)
+		ast2, err := Parse("GRANT UPDATE, INSERT on t TO 'b', 'a'")
+		require.NoError(t, err)
+
+		require.NotEqual(t, ast1.String(), ast2.String())
+		require.Equal(t, Deparse(ast1, DeparseOptions{SortRoles: true}), Deparse(ast2, DeparseOptions{SortRoles: true}))
+	})
+
+	t.Run("canonical numbers have the same value in SQLite", func(t *testing.T) {
+		t.Parallel()
+
+		stmt := "SELECT 0x10, 0xABCDEF, 0x7fffffffffffffff, 0x8000000000000000, -0x01 + 0x2"
+		ast, err := Parse(stmt)
+		require.NoError(t, err)
+		canonical := Deparse(ast, DeparseOptions{CanonicalizeNumbers: true})
+		require.Equal(t, "select 16,11259375,9223372036854775807,0x8000000000000000,-1+2", canonical)
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+		require.Equal(t, queryRows(t, db, stmt), queryRows(t, db, canonical))
+	})
+
+	t.Run("bool expression as int", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, "1", Deparse(BoolValue(true), DeparseOptions{BoolAsInt: true}))
+		require.Equal(t, "(0)", Deparse(&ParenExpr{Expr: BoolValue(false)}, DeparseOptions{BoolAsInt: true}))
+	})
+}
+
+func TestFullyParenthesize(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "arithmetic",
+			stmt:     "select a + b * c from t",
+			deparsed: "select((a)+((b)*(c)))from t",
+		},
+		{
+			name:     "existing parentheses",
+			stmt:     "select (a + b) * -c from t",
+			deparsed: "select(((a)+(b))*(-(c)))from t",
+		},
+		{
+			name:     "logical and comparison",
+			stmt:     "select a from t where a > 1 and not b = 2 or c between 1 and a + 1",
+			deparsed: "select a from t where ((((a)>(1))and(not((b)=(2))))or((c)between(1)and((a)+(1))))",
+		},
+		{
+			name:     "in, like and is",
+			stmt:     "select a from t where a in (1, b + 1) and b like 'x%' escape 'x' and c is not true and a is not b and (b isnull)",
+			deparsed: "select a from t where ((((((a)in(1,((b)+(1))))and((b)like('x%')escape 'x'))and((c)is not true))and((a)is not(b)))and((b)isnull))",
+		},
+		{
+			name:     "subquery and functions",
+			stmt:     "select max(a + 1) from t where a not in (select a from t where b = 1) and -a < abs(b)",
+			deparsed: "select max(((a)+(1)))from t where (((a)not in(select a from t where ((b)=(1))))and((-(a))<(abs(b))))",
+		},
+		{
+			name:     "update and collate",
+			stmt:     "update t set a = a * 2 where b collate nocase = 'x' or b is null",
+			deparsed: "update t set a=((a)*(2))where ((((b)collate nocase)=('x'))or((b)is(null)))",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				deparsed := Deparse(ast, DeparseOptions{FullyParenthesize: true})
+				require.Equal(t, tc.deparsed, deparsed)
+				require.Equal(t, original, ast.String())
+
+				// the output parses to an AST with the same structure, which is only wrapped in more parentheses
+				reparsed, err := Parse(deparsed)
+				require.NoError(t, err)
+				require.Equal(t, deparsed, Deparse(reparsed, DeparseOptions{FullyParenthesize: true}))
+				require.Equal(t, deparsed, reparsed.String())
+
+				if _, ok := ast.Statements[0].(*Select); ok {
+					db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+					require.NoError(t, err)
+					defer func() { require.NoError(t, db.Close()) }()
+
+					_, err = db.Exec(`
+						CREATE TABLE t (a int, b text, c int);
+						INSERT INTO t VALUES (1, '2', 3), (2, 'X', NULL), (3, NULL, 1), (-1, 'x', 2);
+					`)
+					require.NoError(t, err)
+					require.Equal(t, queryRows(t, db, original), queryRows(t, db, deparsed))
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("expression", func(t *testing.T) {
+		t.Parallel()
+
+		expr := &BinaryExpr{
+			Operator: PlusStr,
+			Left:     &Column{Name: "a"},
+			Right:    &BinaryExpr{Operator: MultStr, Left: &Column{Name: "b"}, Right: &Column{Name: "c"}},
+		}
+		require.Equal(t, "((a)+((b)*(c)))", Deparse(expr, DeparseOptions{FullyParenthesize: true}))
+		require.Equal(t, "a+b*c", expr.String())
+	})
+}
+
+func BenchmarkDeparse(b *testing.B) {
+	for _, query := range benchmarkQueries() {
+		ast, err := Parse(query.stmt)
+		require.NoError(b, err)
+
+		b.Run(query.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = ast.String()
+			}
+		})
+	}
+}
+
+type limitedWriter struct {
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("write limit reached")
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriteTo(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name string
+		stmt string
+	}
+
+	tests := []testCase{
+		{
+			name: "select",
+			stmt: "SELECT a, b FROM t WHERE a IN (1, 2, 3) ORDER BY b DESC LIMIT 10",
+		},
+		{
+			name: "multiple statements",
+			stmt: "INSERT INTO t (a) VALUES (1), (2); UPDATE t SET a = 3 WHERE a = 1; DELETE FROM t WHERE a = 2",
+		},
+		{
+			name: "create table",
+			stmt: "CREATE TABLE t (id INTEGER PRIMARY KEY, a TEXT NOT NULL DEFAULT 'x', CHECK (length(a) > 0))",
+		},
+		{
+			name: "empty",
+			stmt: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				n, err := WriteTo(&buf, ast)
+				require.NoError(t, err)
+				require.Equal(t, ast.String(), buf.String())
+				require.Equal(t, int64(len(ast.String())), n)
+
+				for _, stmt := range ast.Statements {
+					buf.Reset()
+					n, err := WriteTo(&buf, stmt)
+					require.NoError(t, err)
+					require.Equal(t, stmt.String(), buf.String())
+					require.Equal(t, int64(len(stmt.String())), n)
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("nil node", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		n, err := WriteTo(&buf, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("writer error", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("DELETE FROM t WHERE a = 1; DELETE FROM t WHERE a = 2")
+		require.NoError(t, err)
+
+		n, err := WriteTo(&limitedWriter{limit: 30}, ast)
+		require.EqualError(t, err, "write limit reached")
+		require.Equal(t, int64(30), n)
+	})
+}
@@ -0,0 +1,234 @@
+package sqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DiffKind is the kind of a structural difference between two nodes.
+type DiffKind string
+
+const (
+	// DiffAdded indicates a node that is only in the new tree.
+	DiffAdded = DiffKind("added")
+
+	// DiffRemoved indicates a node that is only in the old tree.
+	DiffRemoved = DiffKind("removed")
+
+	// DiffChanged indicates a node that is in both trees, but differs.
+	DiffChanged = DiffKind("changed")
+)
+
+// DiffEntry describes a structural difference between two nodes.
+// Path locates the difference, e.g. columns.a.type or constraints.kind:unique. Old and New are the
+// string representations of the old and new nodes, and are empty when
+// the node was added or removed, respectively.
+type DiffEntry struct {
+	Kind DiffKind
+	Path string
+	Old  string
+	New  string
+}
+
+// String returns a human readable description of the difference.
+func (e DiffEntry) String() string {
+	switch e.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("%s added: %s", e.Path, e.New)
+	case DiffRemoved:
+		return fmt.Sprintf("%s removed: %s", e.Path, e.Old)
+	default:
+		return fmt.Sprintf("%s changed: %s -> %s", e.Path, e.Old, e.New)
+	}
+}
+
+// Diff returns the structural differences between the nodes a and b.
+// CREATE TABLE statements are compared by table name, columns, column types and constraints,
+// with columns matched by name, so reordering columns is not reported.
+// Any other nodes are compared by their string representation.
+func Diff(a, b Node) []DiffEntry {
+	switch aNil, bNil := isNilNode(a), isNilNode(b); {
+	case aNil && bNil:
+		return []DiffEntry{}
+	case aNil:
+		return []DiffEntry{{Kind: DiffAdded, New: b.String()}}
+	case bNil:
+		return []DiffEntry{{Kind: DiffRemoved, Old: a.String()}}
+	}
+
+	if createA, ok := a.(*CreateTable); ok {
+		if createB, ok := b.(*CreateTable); ok {
+			return diffCreateTable(createA, createB)
+		}
+	}
+
+	if a.String() != b.String() {
+		return []DiffEntry{{Kind: DiffChanged, Old: a.String(), New: b.String()}}
+	}
+	return []DiffEntry{}
+}
+
+// isNilNode checks if the node is nil, or a nil pointer to a node, like a (*CreateTable)(nil).
+func isNilNode(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func diffCreateTable(a, b *CreateTable) []DiffEntry {
+	diff := []DiffEntry{}
+	if a.Table.String() != b.Table.String() {
+		diff = append(diff, DiffEntry{Kind: DiffChanged, Path: "table", Old: a.Table.String(), New: b.Table.String()})
+	}
+
+	columnsB := map[string]*ColumnDef{}
+	for _, column := range b.ColumnsDef {
+		columnsB[normalizeIdentifier(column.Column.Name)] = column
+	}
+
+	columnsA := map[string]struct{}{}
+	for _, columnA := range a.ColumnsDef {
+		name := normalizeIdentifier(columnA.Column.Name)
+		columnsA[name] = struct{}{}
+
+		path := "columns." + name
+		columnB, ok := columnsB[name]
+		if !ok {
+			diff = append(diff, DiffEntry{Kind: DiffRemoved, Path: path, Old: columnA.String()})
+			continue
+		}
+
+		if !strings.EqualFold(columnA.Type, columnB.Type) {
+			diff = append(diff, DiffEntry{Kind: DiffChanged, Path: path + ".type", Old: columnA.Type, New: columnB.Type})
+		}
+
+		constraintsA, constraintsB := make([]Node, len(columnA.Constraints)), make([]Node, len(columnB.Constraints))
+		for i, constraint := range columnA.Constraints {
+			constraintsA[i] = constraint
+		}
+		for i, constraint := range columnB.Constraints {
+			constraintsB[i] = constraint
+		}
+		diff = append(diff, diffConstraints(path, constraintsA, constraintsB)...)
+	}
+
+	for _, columnB := range b.ColumnsDef {
+		name := normalizeIdentifier(columnB.Column.Name)
+		if _, ok := columnsA[name]; !ok {
+			diff = append(diff, DiffEntry{Kind: DiffAdded, Path: "columns." + name, New: columnB.String()})
+		}
+	}
+
+	constraintsA, constraintsB := make([]Node, len(a.Constraints)), make([]Node, len(b.Constraints))
+	for i, constraint := range a.Constraints {
+		constraintsA[i] = constraint
+	}
+	for i, constraint := range b.Constraints {
+		constraintsB[i] = constraint
+	}
+	diff = append(diff, diffConstraints("constraints", constraintsA, constraintsB)...)
+
+	if a.StrictMode != b.StrictMode {
+		diff = append(diff, DiffEntry{
+			Kind: DiffChanged,
+			Path: "strict",
+			Old:  fmt.Sprint(a.StrictMode),
+			New:  fmt.Sprint(b.StrictMode),
+		})
+	}
+
+	return diff
+}
+
+// diffConstraints compares two lists of column or table constraints.
+// Constraints are grouped by name, or by kind if they're unnamed. When a group has a single constraint
+// in both lists, a difference is reported as a change, otherwise as removed and added constraints.
+func diffConstraints(path string, a, b []Node) []DiffEntry {
+	keys := []string{}
+	groupsA, groupsB := map[string][]Node{}, map[string][]Node{}
+	for _, constraint := range a {
+		key := constraintKey(constraint)
+		if _, ok := groupsA[key]; !ok {
+			keys = append(keys, key)
+		}
+		groupsA[key] = append(groupsA[key], constraint)
+	}
+	for _, constraint := range b {
+		key := constraintKey(constraint)
+		if _, ok := groupsA[key]; !ok {
+			if _, ok := groupsB[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		groupsB[key] = append(groupsB[key], constraint)
+	}
+
+	diff := []DiffEntry{}
+	for _, key := range keys {
+		groupA, groupB := groupsA[key], groupsB[key]
+		keyPath := path + "." + key
+		if len(groupA) == 1 && len(groupB) == 1 {
+			if groupA[0].String() != groupB[0].String() {
+				diff = append(diff, DiffEntry{Kind: DiffChanged, Path: keyPath, Old: groupA[0].String(), New: groupB[0].String()})
+			}
+			continue
+		}
+
+		for _, constraint := range groupA {
+			if !containsNodeString(groupB, constraint) {
+				diff = append(diff, DiffEntry{Kind: DiffRemoved, Path: keyPath, Old: constraint.String()})
+			}
+		}
+		for _, constraint := range groupB {
+			if !containsNodeString(groupA, constraint) {
+				diff = append(diff, DiffEntry{Kind: DiffAdded, Path: keyPath, New: constraint.String()})
+			}
+		}
+	}
+
+	return diff
+}
+
+// constraintKey returns the name of a named constraint, or the kind of an unnamed one, e.g. name:pos or kind:check.
+// The prefixes keep a constraint named like a kind, e.g. CONSTRAINT "unique", apart from the unnamed ones.
+func constraintKey(constraint Node) string {
+	var name Identifier
+	var kind string
+	switch constraint := constraint.(type) {
+	case *ColumnConstraintPrimaryKey:
+		name, kind = constraint.Name, "primary_key"
+	case *ColumnConstraintNotNull:
+		name, kind = constraint.Name, "not_null"
+	case *ColumnConstraintUnique:
+		name, kind = constraint.Name, "unique"
+	case *ColumnConstraintCheck:
+		name, kind = constraint.Name, "check"
+	case *ColumnConstraintDefault:
+		name, kind = constraint.Name, "default"
+	case *ColumnConstraintGenerated:
+		name, kind = constraint.Name, "generated"
+	case *TableConstraintPrimaryKey:
+		name, kind = constraint.Name, "primary_key"
+	case *TableConstraintUnique:
+		name, kind = constraint.Name, "unique"
+	case *TableConstraintCheck:
+		name, kind = constraint.Name, "check"
+	}
+
+	if !name.IsEmpty() {
+		return "name:" + normalizeIdentifier(name)
+	}
+	return "kind:" + kind
+}
+
+func containsNodeString(nodes []Node, node Node) bool {
+	for _, n := range nodes {
+		if n.String() == node.String() {
+			return true
+		}
+	}
+	return false
+}
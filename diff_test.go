@@ -0,0 +1,186 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		old      string
+		new      string
+		expected []DiffEntry
+	}
+
+	tests := []testCase{
+		{
+			name:     "equal",
+			old:      "create table t (a int primary key, b text not null)",
+			new:      "CREATE TABLE t (a INT PRIMARY KEY, b TEXT NOT NULL)",
+			expected: []DiffEntry{},
+		},
+		{
+			name:     "reordered columns",
+			old:      "create table t (a int, b text)",
+			new:      "create table t (b text, a int)",
+			expected: []DiffEntry{},
+		},
+		{
+			name: "column added",
+			old:  "create table t (a int)",
+			new:  "create table t (a int, c text)",
+			expected: []DiffEntry{
+				{Kind: DiffAdded, Path: "columns.c", New: "c text"},
+			},
+		},
+		{
+			name: "column removed",
+			old:  "create table t (a int, b blob)",
+			new:  "create table t (a int)",
+			expected: []DiffEntry{
+				{Kind: DiffRemoved, Path: "columns.b", Old: "b blob"},
+			},
+		},
+		{
+			name: "column type changed",
+			old:  "create table t (a int, b text)",
+			new:  "create table t (a text, B TEXT)",
+			expected: []DiffEntry{
+				{Kind: DiffChanged, Path: "columns.a.type", Old: "int", New: "text"},
+			},
+		},
+		{
+			name: "table renamed",
+			old:  "create table t (a int)",
+			new:  "create table t2 (a int)",
+			expected: []DiffEntry{
+				{Kind: DiffChanged, Path: "table", Old: "t", New: "t2"},
+			},
+		},
+		{
+			name: "column constraints",
+			old:  "create table t (a int not null default 1, b int unique)",
+			new:  "create table t (a int default 2 check (a > 0), b int)",
+			expected: []DiffEntry{
+				{Kind: DiffRemoved, Path: "columns.a.kind:not_null", Old: "not null"},
+				{Kind: DiffChanged, Path: "columns.a.kind:default", Old: "default 1", New: "default 2"},
+				{Kind: DiffAdded, Path: "columns.a.kind:check", New: "check(a>0)"},
+				{Kind: DiffRemoved, Path: "columns.b.kind:unique", Old: "unique"},
+			},
+		},
+		{
+			name: "multiple checks",
+			old:  "create table t (a int check (a > 0) check (a < 10))",
+			new:  "create table t (a int check (a > 0) check (a < 20))",
+			expected: []DiffEntry{
+				{Kind: DiffRemoved, Path: "columns.a.kind:check", Old: "check(a<10)"},
+				{Kind: DiffAdded, Path: "columns.a.kind:check", New: "check(a<20)"},
+			},
+		},
+		{
+			name: "table constraints",
+			old:  "create table t (a int, b int, primary key (a), unique (a, b))",
+			new:  "create table t (a int, b int, primary key (a, b), check (a > b))",
+			expected: []DiffEntry{
+				{Kind: DiffChanged, Path: "constraints.kind:primary_key", Old: "primary key(a)", New: "primary key(a,b)"},
+				{Kind: DiffRemoved, Path: "constraints.kind:unique", Old: "unique(a,b)"},
+				{Kind: DiffAdded, Path: "constraints.kind:check", New: "check(a>b)"},
+			},
+		},
+		{
+			name: "named table constraints",
+			old:  "create table t (a int, b int, constraint ab unique (a, b), constraint pos check (a > 0))",
+			new:  "create table t (a int, b int, constraint ab unique (a), constraint positive check (a > 0))",
+			expected: []DiffEntry{
+				{Kind: DiffChanged, Path: "constraints.name:ab", Old: "constraint ab unique(a,b)", New: "constraint ab unique(a)"},
+				{Kind: DiffRemoved, Path: "constraints.name:pos", Old: "constraint pos check(a>0)"},
+				{Kind: DiffAdded, Path: "constraints.name:positive", New: "constraint positive check(a>0)"},
+			},
+		},
+		{
+			name: "constraint named like a kind",
+			old:  "create table t (a int, b int, unique (a))",
+			new:  "create table t (a int, b int, unique (a), constraint \"unique\" unique (b))",
+			expected: []DiffEntry{
+				{Kind: DiffAdded, Path: "constraints.name:unique", New: "constraint \"unique\" unique(b)"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				oldAST, err := Parse(tc.old)
+				require.NoError(t, err)
+				newAST, err := Parse(tc.new)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.expected, Diff(oldAST.Statements[0], newAST.Statements[0]))
+			}
+		}(tc))
+	}
+
+	t.Run("strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("create table t (a int)")
+		require.NoError(t, err)
+		strict := cloneNode(ast.Statements[0]).(*CreateTable)
+		strict.StrictMode = true
+
+		diff := Diff(ast.Statements[0], strict)
+		require.Equal(t, []DiffEntry{{Kind: DiffChanged, Path: "strict", Old: "false", New: "true"}}, diff)
+		require.Equal(t, "strict changed: false -> true", diff[0].String())
+	})
+
+	t.Run("other nodes", func(t *testing.T) {
+		t.Parallel()
+
+		oldAST, err := Parse("select a from t")
+		require.NoError(t, err)
+		newAST, err := Parse("select b from t")
+		require.NoError(t, err)
+
+		require.Equal(t, []DiffEntry{}, Diff(oldAST, oldAST))
+		require.Equal(t, []DiffEntry{{Kind: DiffChanged, Old: "select a from t", New: "select b from t"}}, Diff(oldAST, newAST))
+		require.Equal(t, []DiffEntry{{Kind: DiffAdded, New: "select b from t"}}, Diff(nil, newAST))
+		require.Equal(t, []DiffEntry{{Kind: DiffRemoved, Old: "select a from t"}}, Diff(oldAST, nil))
+	})
+
+	t.Run("typed nil", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("create table t (a int)")
+		require.NoError(t, err)
+
+		var create *CreateTable
+		require.Equal(t, []DiffEntry{}, Diff(create, create))
+		require.Equal(t, []DiffEntry{{Kind: DiffAdded, New: "create table t(a int)"}}, Diff(create, ast.Statements[0]))
+		require.Equal(t, []DiffEntry{{Kind: DiffRemoved, Old: "create table t(a int)"}}, Diff(ast.Statements[0], create))
+	})
+
+	t.Run("descriptions", func(t *testing.T) {
+		t.Parallel()
+
+		oldAST, err := Parse("create table t (a int, b int)")
+		require.NoError(t, err)
+		newAST, err := Parse("create table t (a text, c int)")
+		require.NoError(t, err)
+
+		descriptions := []string{}
+		for _, entry := range Diff(oldAST.Statements[0], newAST.Statements[0]) {
+			descriptions = append(descriptions, entry.String())
+		}
+		require.Equal(t, []string{
+			"columns.a.type changed: int -> text",
+			"columns.b removed: b int",
+			"columns.c added: c int",
+		}, descriptions)
+	})
+}
@@ -1,6 +1,9 @@
 package sqlparser
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ErrSyntaxError indicates a syntax error.
 type ErrSyntaxError struct {
@@ -23,7 +26,7 @@ func (e *ErrKeywordIsNotAllowed) Error() string {
 }
 
 // ErrTextTooLong is an error returned when a query contains a
-// text constant that is too long.
+// text constant that is too long. The length is in bytes.
 type ErrTextTooLong struct {
 	Length     int
 	MaxAllowed int
@@ -179,3 +182,395 @@ type ErrNotNullConstraintDefaultNotNull struct{}
 func (e *ErrNotNullConstraintDefaultNotNull) Error() string {
 	return "cannot add a NOT NULL column with default value NULL"
 }
+
+// ErrInvalidRole indicates that a GRANT or REVOKE role has an invalid format.
+type ErrInvalidRole struct {
+	Role string
+}
+
+func (e *ErrInvalidRole) Error() string {
+	return fmt.Sprintf("role %s has an invalid format", e.Role)
+}
+
+// ErrTooManyRoles is an error returned when a GRANT or REVOKE statement has
+// more roles than allowed.
+type ErrTooManyRoles struct {
+	RolesCount int
+	MaxAllowed int
+}
+
+func (e *ErrTooManyRoles) Error() string {
+	return fmt.Sprintf("statement has too many roles (has %d, max %d)",
+		e.RolesCount, e.MaxAllowed)
+}
+
+// ErrMaintenanceStatementNotAllowed indicates that a VACUUM, ANALYZE, REINDEX or a PRAGMA that is not read-only
+// was used without being allowed by the parse options.
+type ErrMaintenanceStatementNotAllowed struct{}
+
+func (e *ErrMaintenanceStatementNotAllowed) Error() string {
+	return "maintenance statements are not allowed"
+}
+
+// ErrStatementNotAllowed indicates that a statement kind is not allowed by the Tableland policy.
+type ErrStatementNotAllowed struct {
+	Statement string
+}
+
+func (e *ErrStatementNotAllowed) Error() string {
+	return fmt.Sprintf("statement not allowed: %s", e.Statement)
+}
+
+// ErrWrongChainID indicates that a table written or created by a query belongs to a different chain.
+type ErrWrongChainID struct {
+	Table    string
+	ChainID  int64
+	Expected int64
+}
+
+func (e *ErrWrongChainID) Error() string {
+	return fmt.Sprintf("table %s belongs to chain %d, expected chain %d", e.Table, e.ChainID, e.Expected)
+}
+
+// ErrMultipleTargetTables indicates that the statements of a write query target different tables.
+type ErrMultipleTargetTables struct {
+	Tables []string
+}
+
+func (e *ErrMultipleTargetTables) Error() string {
+	return fmt.Sprintf("write statements must target a single table (has %s)", strings.Join(e.Tables, ", "))
+}
+
+// ErrMultipleStatements indicates that the input has more than one statement
+// when a single statement is expected.
+type ErrMultipleStatements struct {
+	StatementsCount int
+}
+
+func (e *ErrMultipleStatements) Error() string {
+	return fmt.Sprintf("expected a single statement (has %d)", e.StatementsCount)
+}
+
+// ErrTooManyStatements indicates that the input has more statements than allowed.
+type ErrTooManyStatements struct {
+	StatementsCount int
+	MaxAllowed      int
+}
+
+func (e *ErrTooManyStatements) Error() string {
+	return fmt.Sprintf("too many statements (has %d, max %d)", e.StatementsCount, e.MaxAllowed)
+}
+
+// ErrAutoIncrementNotAllowed indicates that AUTOINCREMENT was used in a column that is not an
+// INTEGER PRIMARY KEY in ascending order.
+type ErrAutoIncrementNotAllowed struct{}
+
+func (e *ErrAutoIncrementNotAllowed) Error() string {
+	return "AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY"
+}
+
+// ErrExcludedOutsideUpsert indicates that the excluded pseudo-table was referenced
+// outside of an upsert DO UPDATE clause.
+type ErrExcludedOutsideUpsert struct{}
+
+func (e *ErrExcludedOutsideUpsert) Error() string {
+	return "excluded can only be referenced in an upsert DO UPDATE clause"
+}
+
+// ErrInParamNotAllowed indicates that a param was used as the list of an IN operator outside of a read statement,
+// where there's no resolve step to expand it to a list of values.
+type ErrInParamNotAllowed struct{}
+
+func (e *ErrInParamNotAllowed) Error() string {
+	return "a param can only be used as an IN list in a read statement"
+}
+
+// ErrDefaultKeywordNotAllowed indicates that the DEFAULT keyword was used as a value in the rows of an INSERT
+// without the AllowDefaultKeyword option.
+type ErrDefaultKeywordNotAllowed struct{}
+
+func (e *ErrDefaultKeywordNotAllowed) Error() string {
+	return "the DEFAULT keyword is not allowed as a value"
+}
+
+// ErrWrongNumberOfArguments indicates that a function was called with a wrong number of arguments.
+type ErrWrongNumberOfArguments struct {
+	FunctionName string
+	ArgsCount    int
+	Min          int
+	Max          int
+}
+
+func (e *ErrWrongNumberOfArguments) Error() string {
+	switch {
+	case e.Min == e.Max:
+		return fmt.Sprintf("wrong number of arguments to function %s (has %d, expected %d)",
+			e.FunctionName, e.ArgsCount, e.Min)
+	case e.Max == -1:
+		return fmt.Sprintf("wrong number of arguments to function %s (has %d, expected at least %d)",
+			e.FunctionName, e.ArgsCount, e.Min)
+	default:
+		return fmt.Sprintf("wrong number of arguments to function %s (has %d, expected between %d and %d)",
+			e.FunctionName, e.ArgsCount, e.Min, e.Max)
+	}
+}
+
+// ErrUnknownTable indicates that a statement references a table that is not in the schema.
+type ErrUnknownTable struct {
+	Name string
+}
+
+func (e *ErrUnknownTable) Error() string {
+	return fmt.Sprintf("no such table: %s", e.Name)
+}
+
+// ErrAliasConflict indicates that an alias can't be renamed, because a table or another alias has the same name.
+type ErrAliasConflict struct {
+	Name string
+}
+
+func (e *ErrAliasConflict) Error() string {
+	return fmt.Sprintf("alias conflicts with a table or alias: %s", e.Name)
+}
+
+// ErrUnknownTableInBatch indicates that a statement of a batch targets a table
+// that is not created by a preceding statement of the batch.
+type ErrUnknownTableInBatch struct {
+	Name           string
+	StatementIndex int
+}
+
+func (e *ErrUnknownTableInBatch) Error() string {
+	return fmt.Sprintf("statement %d targets table %s that is not created in the batch", e.StatementIndex, e.Name)
+}
+
+// ErrUnknownColumn indicates that a statement references a column that is not in the schema.
+// Table is empty if the column is not qualified.
+type ErrUnknownColumn struct {
+	Table  string
+	Column string
+}
+
+func (e *ErrUnknownColumn) Error() string {
+	if e.Table != "" {
+		return fmt.Sprintf("no such column: %s.%s", e.Table, e.Column)
+	}
+	return fmt.Sprintf("no such column: %s", e.Column)
+}
+
+// ErrAmbiguousColumn indicates that an unqualified column may refer to more than one table.
+type ErrAmbiguousColumn struct {
+	Column string
+}
+
+func (e *ErrAmbiguousColumn) Error() string {
+	return fmt.Sprintf("ambiguous column name: %s", e.Column)
+}
+
+// ErrLikePatternTooLong is an error returned when a LIKE or GLOB pattern is longer than allowed.
+type ErrLikePatternTooLong struct {
+	Length     int
+	MaxAllowed int
+}
+
+func (e *ErrLikePatternTooLong) Error() string {
+	return fmt.Sprintf("LIKE or GLOB pattern is too long (has %d, max %d)",
+		e.Length, e.MaxAllowed)
+}
+
+// ErrInvalidLimitExpr indicates that a LIMIT or OFFSET expression is not an integer literal or a param.
+type ErrInvalidLimitExpr struct {
+	Expr string
+}
+
+func (e *ErrInvalidLimitExpr) Error() string {
+	return fmt.Sprintf("LIMIT and OFFSET must be an integer literal or a param: %s", e.Expr)
+}
+
+// ErrInvalidCastType indicates that the target type of a CAST expression is not supported.
+type ErrInvalidCastType struct {
+	Type string
+}
+
+func (e *ErrInvalidCastType) Error() string {
+	return fmt.Sprintf("invalid cast type: %s", e.Type)
+}
+
+// ErrInvalidEscape indicates that the ESCAPE expression of a LIKE is not a single character string literal.
+type ErrInvalidEscape struct {
+	Expr string
+}
+
+func (e *ErrInvalidEscape) Error() string {
+	return fmt.Sprintf("ESCAPE expression must be a single character: %s", e.Expr)
+}
+
+// ErrInvalidBlobLiteral indicates that a BLOB literal is not an even number of hexadecimal digits.
+type ErrInvalidBlobLiteral struct {
+	Literal string
+}
+
+func (e *ErrInvalidBlobLiteral) Error() string {
+	return fmt.Sprintf("blob literal must be an even number of hex digits: x'%s'", e.Literal)
+}
+
+// ErrParamsCountMismatch indicates that the number of params differs from the number of values bound to them.
+type ErrParamsCountMismatch struct {
+	ParamsCount int
+	ValuesCount int
+}
+
+func (e *ErrParamsCountMismatch) Error() string {
+	return fmt.Sprintf("%d params bound to %d values", e.ParamsCount, e.ValuesCount)
+}
+
+// ErrTooManyJoins is an error returned when a read statement has more joins than allowed.
+type ErrTooManyJoins struct {
+	JoinsCount int
+	MaxAllowed int
+}
+
+func (e *ErrTooManyJoins) Error() string {
+	return fmt.Sprintf("statement has too many joins (has %d, max %d)",
+		e.JoinsCount, e.MaxAllowed)
+}
+
+// ErrTooManyIdentifiers is an error returned when a statement references more distinct identifiers than allowed.
+type ErrTooManyIdentifiers struct {
+	IdentifiersCount int
+	MaxAllowed       int
+}
+
+func (e *ErrTooManyIdentifiers) Error() string {
+	return fmt.Sprintf("statement has too many identifiers (has %d, max %d)",
+		e.IdentifiersCount, e.MaxAllowed)
+}
+
+// ErrMissingLimit indicates that a read statement doesn't have a LIMIT clause.
+type ErrMissingLimit struct{}
+
+func (e *ErrMissingLimit) Error() string {
+	return "select statement must have a LIMIT clause"
+}
+
+// ErrMissingLimitAfterValues indicates that a compound select that must have a LIMIT clause ends with VALUES,
+// which can't be followed by one.
+type ErrMissingLimitAfterValues struct{}
+
+func (e *ErrMissingLimitAfterValues) Error() string {
+	return "compound select must have a LIMIT clause, which can't follow VALUES: " +
+		"move the VALUES before a SELECT operand"
+}
+
+// ErrMissingWhere indicates that an UPDATE or DELETE statement doesn't have a WHERE clause.
+type ErrMissingWhere struct {
+	StatementKind string
+}
+
+func (e *ErrMissingWhere) Error() string {
+	return fmt.Sprintf("%s statement must have a WHERE clause", e.StatementKind)
+}
+
+// ErrInsertArityMismatch indicates that a row of an INSERT has a different number of values than the columns,
+// or than the first row if there is no column list.
+type ErrInsertArityMismatch struct {
+	RowIndex     int
+	ColumnsCount int
+	ValuesCount  int
+}
+
+func (e *ErrInsertArityMismatch) Error() string {
+	return fmt.Sprintf("row %d has %d values for %d columns", e.RowIndex, e.ValuesCount, e.ColumnsCount)
+}
+
+// ErrFilterOnNonAggregate indicates that a FILTER clause was used with a function that is not an aggregate function.
+type ErrFilterOnNonAggregate struct {
+	FunctionName string
+}
+
+func (e *ErrFilterOnNonAggregate) Error() string {
+	return fmt.Sprintf("FILTER clause may only be used with aggregate functions: %s", e.FunctionName)
+}
+
+// ErrOverOnNonAggregate indicates that an OVER clause was used with a function that is not an aggregate function.
+type ErrOverOnNonAggregate struct {
+	FunctionName string
+}
+
+func (e *ErrOverOnNonAggregate) Error() string {
+	return fmt.Sprintf("OVER clause may only be used with aggregate functions: %s", e.FunctionName)
+}
+
+// ErrOrderByOrdinalOutOfRange indicates that an ORDER BY ordinal does not refer to a result column.
+type ErrOrderByOrdinalOutOfRange struct {
+	Ordinal      string
+	ColumnsCount int
+}
+
+func (e *ErrOrderByOrdinalOutOfRange) Error() string {
+	return fmt.Sprintf("ORDER BY term %s out of range - should be between 1 and %d", e.Ordinal, e.ColumnsCount)
+}
+
+// ErrGroupByOrdinalOutOfRange indicates that a GROUP BY ordinal does not refer to a result column.
+type ErrGroupByOrdinalOutOfRange struct {
+	Ordinal      string
+	ColumnsCount int
+}
+
+func (e *ErrGroupByOrdinalOutOfRange) Error() string {
+	return fmt.Sprintf("GROUP BY term %s out of range - should be between 1 and %d", e.Ordinal, e.ColumnsCount)
+}
+
+// ErrDuplicateColumnName indicates that a table has more than one column with the same name.
+type ErrDuplicateColumnName struct {
+	Name string
+}
+
+func (e *ErrDuplicateColumnName) Error() string {
+	return fmt.Sprintf("duplicate column name: %s", e.Name)
+}
+
+// ErrDuplicateConstraintName indicates that a table has more than one constraint with the same name.
+type ErrDuplicateConstraintName struct {
+	Name string
+}
+
+func (e *ErrDuplicateConstraintName) Error() string {
+	return fmt.Sprintf("duplicate constraint name: %s", e.Name)
+}
+
+// ErrCustomFuncInDDL indicates that a custom function was used in a CREATE TABLE or ALTER TABLE statement.
+type ErrCustomFuncInDDL struct {
+	FunctionName string
+}
+
+func (e *ErrCustomFuncInDDL) Error() string {
+	return fmt.Sprintf("custom function %s cannot be used in a table definition", e.FunctionName)
+}
+
+// ErrDropColumnInUse indicates that a dropped column is part of a PRIMARY KEY or UNIQUE constraint,
+// or is referenced by a constraint of another column or of the table, so SQLite can't drop it.
+type ErrDropColumnInUse struct {
+	Column     string
+	Constraint string
+}
+
+func (e *ErrDropColumnInUse) Error() string {
+	return fmt.Sprintf("cannot drop column %s: used by %s", e.Column, e.Constraint)
+}
+
+// ErrTriggersNotSupported indicates that the statement is a CREATE TRIGGER, which is not supported.
+type ErrTriggersNotSupported struct{}
+
+func (e *ErrTriggersNotSupported) Error() string {
+	return "triggers are not supported"
+}
+
+// ErrInvalidIdentifier indicates that a name given to a builder isn't a valid identifier.
+type ErrInvalidIdentifier struct {
+	Name string
+}
+
+func (e *ErrInvalidIdentifier) Error() string {
+	return fmt.Sprintf("invalid identifier: %q", e.Name)
+}
@@ -118,3 +118,96 @@ var AllowedFunctions = map[string]bool{
 	"txn_hash":  true,
 	"block_num": true,
 }
+
+// functionArity is the allowed number of arguments of a function.
+// A max of -1 means there is no upper limit.
+type functionArity struct {
+	min int
+	max int
+}
+
+// functionArities is a map of the number of arguments allowed for functions.
+// Functions that are not in the map are not validated.
+var functionArities = map[string]functionArity{
+	// core functions
+	"abs":       {min: 1, max: 1},
+	"char":      {min: 0, max: -1},
+	"coalesce":  {min: 2, max: -1},
+	"hex":       {min: 1, max: 1},
+	"ifnull":    {min: 2, max: 2},
+	"iif":       {min: 3, max: 3},
+	"instr":     {min: 2, max: 2},
+	"length":    {min: 1, max: 1},
+	"lower":     {min: 1, max: 1},
+	"ltrim":     {min: 1, max: 2},
+	"max":       {min: 1, max: -1}, // one argument is the aggregate form
+	"min":       {min: 1, max: -1}, // one argument is the aggregate form
+	"nullif":    {min: 2, max: 2},
+	"quote":     {min: 1, max: 1},
+	"replace":   {min: 3, max: 3},
+	"round":     {min: 1, max: 2},
+	"rtrim":     {min: 1, max: 2},
+	"substr":    {min: 2, max: 3},
+	"substring": {min: 2, max: 3},
+	"trim":      {min: 1, max: 2},
+	"typeof":    {min: 1, max: 1},
+	"unicode":   {min: 1, max: 1},
+	"upper":     {min: 1, max: 1},
+
+	// json functions
+	"json":              {min: 1, max: 1},
+	"json_array":        {min: 0, max: -1},
+	"json_array_length": {min: 1, max: 2},
+	"json_extract":      {min: 2, max: -1},
+	"json_group_array":  {min: 1, max: 1},
+	"json_group_object": {min: 2, max: 2},
+	"json_insert":       {min: 3, max: -1},
+	"json_object":       {min: 0, max: -1},
+	"json_patch":        {min: 2, max: 2},
+	"json_quote":        {min: 1, max: 1},
+	"json_remove":       {min: 1, max: -1},
+	"json_replace":      {min: 3, max: -1},
+	"json_set":          {min: 3, max: -1},
+	"json_type":         {min: 1, max: 2},
+	"json_valid":        {min: 1, max: 1},
+}
+
+// validateFunctionArity checks if the function can be called with the arguments.
+// Nil arguments represent the star argument, e.g. count(*), and count as zero arguments.
+func validateFunctionArity(name string, args Exprs) error {
+	arity, ok := functionArities[name]
+	if !ok {
+		return nil
+	}
+
+	if len(args) < arity.min || (arity.max != -1 && len(args) > arity.max) {
+		return &ErrWrongNumberOfArguments{FunctionName: name, ArgsCount: len(args), Min: arity.min, Max: arity.max}
+	}
+
+	return nil
+}
+
+// aggregateFunctions is a set of the allowed aggregate functions.
+var aggregateFunctions = map[string]struct{}{
+	"avg":               {},
+	"count":             {},
+	"group_concat":      {},
+	"json_group_array":  {},
+	"json_group_object": {},
+	"max":               {},
+	"min":               {},
+	"sum":               {},
+	"total":             {},
+}
+
+// isAggregateFunction checks if the function call is an aggregate function call.
+// max and min are only aggregate functions when called with a single argument.
+func isAggregateFunction(name string, args Exprs) bool {
+	if _, ok := aggregateFunctions[name]; !ok {
+		return false
+	}
+	if name == "max" || name == "min" {
+		return len(args) == 1
+	}
+	return true
+}
@@ -38,6 +38,31 @@ func isRowID(column Identifier) bool {
 	return false
 }
 
+func endsWithRowIDOrderingTerm(orderBy OrderBy) bool {
+	if len(orderBy) == 0 {
+		return false
+	}
+
+	term := orderBy[len(orderBy)-1]
+	column, ok := term.Expr.(*Column)
+	return ok && column.TableRef == nil && string(column.Name) == "rowid" && term.Direction == AscStr && term.Nulls == NullsNil
+}
+
+// likePatternLength returns the length in bytes of a LIKE or GLOB pattern, if it's a string literal.
+func likePatternLength(operator string, pattern Expr) (int, bool) {
+	switch operator {
+	case LikeStr, NotLikeStr, GlobStr, NotGlobStr:
+	default:
+		return 0, false
+	}
+
+	value, ok := pattern.(*Value)
+	if !ok || value.Type != StrValue {
+		return 0, false
+	}
+	return len(bytes.ReplaceAll(value.Value, []byte("''"), []byte("'"))), true
+}
+
 %}
 
 %union{
@@ -96,6 +121,9 @@ func isRowID(column Identifier) bool {
   collateOpt Identifier
   joinOperator *JoinOperator
   param *Param
+  windowDef *WindowDef
+  frameSpec *FrameSpec
+  frameBound *FrameBound
 }
 
 %token <bytes> IDENTIFIER STRING INTEGRAL HEXNUM FLOAT BLOBVAL
@@ -105,10 +133,12 @@ func isRowID(column Identifier) bool {
 %token <empty> NONE INTEGER TEXT CAST AS
 %token <empty> CASE WHEN THEN ELSE END
 %token <empty> SELECT FROM WHERE GROUP BY HAVING LIMIT OFFSET ORDER ASC DESC NULLS FIRST LAST DISTINCT ALL EXISTS FILTER UNION EXCEPT INTERSECT
-%token <empty> CREATE TABLE INT BLOB PRIMARY KEY UNIQUE CHECK DEFAULT GENERATED ALWAYS STORED VIRTUAL CONSTRAINT
+%token <empty> CREATE TABLE VIEW INT BLOB PRIMARY KEY UNIQUE CHECK DEFAULT GENERATED ALWAYS STORED VIRTUAL CONSTRAINT
 %token <empty> INSERT INTO VALUES DELETE UPDATE SET CONFLICT DO NOTHING
 %token <empty> GRANT TO REVOKE
 %token <empty> ALTER RENAME COLUMN ADD DROP
+%token <empty> VACUUM ANALYZE REINDEX PRAGMA
+%token <empty> OVER PARTITION ROWS RANGE GROUPS UNBOUNDED PRECEDING FOLLOWING CURRENT ROW EXCLUDE NO OTHERS TIES
 
 %left <empty> RIGHT FULL INNER LEFT NATURAL OUTER CROSS JOIN
 %left <empty> ON USING
@@ -126,12 +156,12 @@ func isRowID(column Identifier) bool {
 %left <empty> COLLATE
 %right <empty> '~' UNARY
 
-%type <statement> multi_stmt single_stmt
-%type <readStmt> select_stmt
+%type <statement> multi_stmt single_stmt admin_stmt maintenance_stmt pragma_stmt create_view_stmt
+%type <readStmt> read_stmt select_stmt values_select select_compound_head values_compound_head
 %type <baseSelect> base_select
 %type <createTableStmt> create_table_stmt
-%type <expr> expr literal_value function_call_keyword function_call_generic expr_opt else_expr_opt exists_subquery signed_number
-%type <exprs> expr_list expr_list_opt group_by_opt
+%type <expr> expr literal_value pragma_value function_call_keyword function_call_generic expr_opt else_expr_opt exists_subquery signed_number insert_value
+%type <exprs> expr_list expr_list_opt group_by_opt insert_value_list
 %type <string> cmp_op cmp_inequality_op like_op between_op asc_desc_opt distinct_opt type_name primary_key_order privilege compound_op
 %type <column> column_name 
 %type <identifier> as_column_opt col_alias as_table_opt table_alias constraint_name identifier collate_opt
@@ -146,7 +176,7 @@ func isRowID(column Identifier) bool {
 %type <orderBy> order_by_opt order_list
 %type <orderingTerm> ordering_term
 %type <nulls> nulls
-%type <tableExpr> table_expr from_clause
+%type <tableExpr> table_expr from_clause from_clause_opt
 %type <joinTableExpr> join_clause join_constraint
 %type <columnList> column_name_list column_name_list_opt
 %type <indexedColumnList> indexed_column_list
@@ -162,7 +192,7 @@ func isRowID(column Identifier) bool {
 %type <tableConstraint> table_constraint
 %type <tableConstraints> table_constraint_list table_constraint_list_opt
 %type <insertStmt> insert_stmt
-%type <insertRows> insert_rows
+%type <insertRows> insert_rows insert_value_rows
 %type <deleteStmt> delete_stmt
 %type <updateStmt> update_stmt
 %type <updateExpression> update_expression
@@ -179,6 +209,11 @@ func isRowID(column Identifier) bool {
 %type <onConflictTarget> conflict_target_opt
 %type <joinOperator> join_op
 %type <param> param
+%type <windowDef> over_opt
+%type <exprs> partition_by_opt
+%type <frameSpec> frame_spec_opt
+%type <frameBound> frame_single_bound frame_start_bound frame_end_bound
+%type <string> frame_unit frame_exclude_opt
 
 %%
 start: 
@@ -197,11 +232,34 @@ stmts:
 ;
 
 single_stmt:
-  select_stmt
+  read_stmt
   {
+    if containsExcludedOutsideUpsert($1) {
+      yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+    }
+    yylex.(*Lexer).validateJoins($1)
+    yylex.(*Lexer).validateLimitOnSelect($1)
     $$ = $1
   }
 | create_table_stmt
+  {
+    if containsInListParam($1) {
+      yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+    }
+    $$ = $1
+  }
+| create_view_stmt
+  {
+    if containsInListParam($1) {
+      yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+    }
+    $$ = $1
+  }
+| admin_stmt
+  {
+    $$ = $1
+  }
+| pragma_stmt
   {
     $$ = $1
   }
@@ -212,7 +270,7 @@ multi_stmts:
   {
     $$ = []Statement{$1}
   }
-| multi_stmts ';' multi_stmt
+| multi_stmts semicolons multi_stmt
   {
     $$ = append($1, $3)
   }
@@ -221,16 +279,34 @@ multi_stmts:
 multi_stmt:
   insert_stmt
   {
+    if containsExcludedOutsideUpsert($1) {
+      yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+    }
+    if containsInListParam($1) {
+      yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+    }
     yylex.(*Lexer).statementIdx++ 
     $$ = $1
   }
 | delete_stmt
   {
+    if containsExcludedOutsideUpsert($1) {
+      yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+    }
+    if containsInListParam($1) {
+      yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+    }
     yylex.(*Lexer).statementIdx++ 
     $$ = $1 
   }
 | update_stmt
   {
+    if containsExcludedOutsideUpsert($1) {
+      yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+    }
+    if containsInListParam($1) {
+      yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+    }
     yylex.(*Lexer).statementIdx++ 
     $$ = $1 
   }
@@ -253,21 +329,92 @@ multi_stmt:
 
 semicolon_opt:
   {}
-| ';' 
+| semicolons
+  {}
+;
+
+semicolons:
+  ';'
+  {}
+| semicolons ';'
   {}
 ;
 
 select_stmt:
   base_select order_by_opt limit_opt
   {
+    yylex.(*Lexer).validateOrderByOrdinals($1.SelectColumnList, $2)
     $1.OrderBy = $2
     $1.Limit = $3
     $$ = $1
   }
-| base_select compound_op select_stmt
+| select_compound_head compound_op base_select order_by_opt limit_opt
   {
+    // The ORDER BY and LIMIT of a compound select are kept in its last select.
+    yylex.(*Lexer).validateOrderByOrdinals($3.SelectColumnList, $4)
+    $3.OrderBy = $4
+    $3.Limit = $5
     $$ = &CompoundSelect{Type: $2, Left: $1, Right: $3}
   }
+| select_compound_head compound_op VALUES insert_rows
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: &Values{Rows: $4}}
+  }
+;
+
+values_select:
+  VALUES insert_rows
+  {
+    $$ = &Values{Rows: $2}
+  }
+| values_compound_head compound_op base_select order_by_opt limit_opt
+  {
+    yylex.(*Lexer).validateOrderByOrdinals($3.SelectColumnList, $4)
+    $3.OrderBy = $4
+    $3.Limit = $5
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: $3}
+  }
+| values_compound_head compound_op VALUES insert_rows
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: &Values{Rows: $4}}
+  }
+;
+
+// Compound operators are left associative, as in SQLite, so A EXCEPT B EXCEPT C is (A EXCEPT B) EXCEPT C.
+// The heads are all the operands of a compound select but the last one.
+select_compound_head:
+  base_select
+  {
+    $$ = $1
+  }
+| select_compound_head compound_op base_select
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: $3}
+  }
+| select_compound_head compound_op VALUES insert_rows
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: &Values{Rows: $4}}
+  }
+;
+
+values_compound_head:
+  VALUES insert_rows
+  {
+    $$ = &Values{Rows: $2}
+  }
+| values_compound_head compound_op base_select
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: $3}
+  }
+| values_compound_head compound_op VALUES insert_rows
+  {
+    $$ = &CompoundSelect{Type: $2, Left: $1, Right: &Values{Rows: $4}}
+  }
+;
+
+read_stmt:
+  select_stmt
+| values_select
 ;
 
 compound_op:
@@ -290,8 +437,9 @@ compound_op:
 ;
 
 base_select:
-  SELECT distinct_opt select_column_list from_clause where_opt group_by_opt having_opt
+  SELECT distinct_opt select_column_list from_clause_opt where_opt group_by_opt having_opt
   {
+    yylex.(*Lexer).validateGroupByOrdinals($3, $6)
     $$ = &Select{
             Distinct: $2,
             SelectColumnList: $3, 
@@ -365,6 +513,16 @@ col_alias:
   }
 ;
 
+from_clause_opt:
+  {
+    $$ = nil
+  }
+| from_clause
+  {
+    $$ = $1
+  }
+;
+
 from_clause:
   FROM table_expr
   {
@@ -382,7 +540,7 @@ table_expr:
     $1.IsTarget = true
     $$ = &AliasedTableExpr{Expr: $1, As: $2}
   }
-| '(' select_stmt ')' as_table_opt
+| '(' read_stmt ')' as_table_opt
   {
     $$ = &AliasedTableExpr{Expr: &Subquery{Select: $2}, As: $4}
   }
@@ -466,6 +624,10 @@ join_op:
   {
     $$ =  &JoinOperator{Op: JoinStr}
   }
+| NATURAL JOIN
+  {
+    $$ =  &JoinOperator{Op: JoinStr, Natural: true}
+  }
 | natural_opt LEFT outer_opt JOIN
   {
     $$ =  &JoinOperator{Op: LeftJoinStr, Natural: $1, Outer: $3}
@@ -611,14 +773,19 @@ limit_opt:
   }
 | LIMIT expr
   {
+    yylex.(*Lexer).validateLimitExpr($2)
     $$ = &Limit{Limit: $2}
   }
 | LIMIT expr ',' expr
   {
-    $$ = &Limit{Offset: $2, Limit: $4}
+    yylex.(*Lexer).validateLimitExpr($2)
+    yylex.(*Lexer).validateLimitExpr($4)
+    $$ = &Limit{Offset: $2, Limit: $4, CommaSyntax: true}
   }
 | LIMIT expr OFFSET expr
   {
+    yylex.(*Lexer).validateLimitExpr($2)
+    yylex.(*Lexer).validateLimitExpr($4)
     $$ = &Limit{Offset: $4, Limit: $2}
   }
 ;
@@ -689,6 +856,7 @@ expr:
   }
 | expr cmp_op expr %prec IS
   {  
+    yylex.(*Lexer).validateLikePattern($2, $3)
     $$ = &CmpExpr{Left: $1, Operator: $2, Right: $3} 
   }
 | expr cmp_inequality_op expr %prec INEQUALITY
@@ -697,15 +865,19 @@ expr:
   }
 | expr like_op expr %prec LIKE
   {
+    yylex.(*Lexer).validateLikePattern($2, $3)
     $$ = &CmpExpr{Left: $1, Operator: $2, Right: $3}
   }
 | expr like_op expr ESCAPE expr %prec LIKE
   {
+    yylex.(*Lexer).validateLikePattern($2, $3)
+    yylex.(*Lexer).validateEscape($5)
     $$ = &CmpExpr{Left: $1, Operator: $2, Right: $3, Escape: $5}
   }
 | '-'  expr %prec UNARY
   {
-    if value, ok := $2.(*Value); ok && value.Type == IntValue {
+    // a negative literal isn't folded again, so the minus signs don't render as a -- comment
+    if value, ok := $2.(*Value); ok && value.Type == IntValue && !bytes.HasPrefix(value.Value, []byte("-")) {
       $$ = &Value{Type: IntValue, Value: append([]byte("-"), value.Value...)}
     } else {
       $$ = &UnaryExpr{Operator: UMinusStr, Expr: $2}
@@ -735,6 +907,10 @@ expr:
   {  
     $$ = &IsExpr{Left: $1, Right: &NotExpr{Expr: $4}}
   }
+| NOT expr
+  {
+    $$ = &NotExpr{Expr: $2}
+  }
 | expr ISNULL
   {  
     $$ = &IsNullExpr{Expr : $1}
@@ -795,13 +971,15 @@ literal_value:
 | STRING
   {
     str := $1[1:len($1)-1]
-    if len(str) > MaxTextLength {
-      yylex.(*Lexer).AddError(&ErrTextTooLong{Length: len(str), MaxAllowed: MaxTextLength})
+    // the length is the number of bytes of the value, so an escaped quote counts once
+    if length := len(str) - bytes.Count(str, []byte("''")); length > MaxTextLength {
+      yylex.(*Lexer).AddError(&ErrTextTooLong{Length: length, MaxAllowed: MaxTextLength})
     }
     $$ = &Value{Type: StrValue, Value: str}
   }
 | BLOBVAL
   {
+    yylex.(*Lexer).validateBlobLiteral($1)
     if len($1) > MaxBlobLength {
       yylex.(*Lexer).AddError(&ErrBlobTooBig{Length: len($1), MaxAllowed: MaxBlobLength})
     }
@@ -919,6 +1097,11 @@ convert_type:
   NONE { $$ = NoneStr}
 | TEXT { $$ = TextStr}
 | INTEGER { $$ = IntegerStr}
+| IDENTIFIER
+  {
+    yylex.(*Lexer).AddError(&ErrInvalidCastType{Type: string($1)})
+    $$ = ConvertType(bytes.ToLower($1))
+  }
 ;
 
 col_tuple:
@@ -934,10 +1117,14 @@ col_tuple:
   {
     $$ = $2
   }
+| param
+  {
+    $$ = $1
+  }
 ;
 
 subquery:
-  '(' select_stmt ')'
+  '(' read_stmt ')'
   {
     $$ = &Subquery{Select: $2}
   }
@@ -948,10 +1135,6 @@ exists_subquery:
   {
     $$ = &ExistsExpr{Subquery: $2}
   }
-| NOT EXISTS subquery
-  {
-    $$ = &NotExpr{Expr: &ExistsExpr{Subquery: $3}}
-  }
 ;
 
 function_call_keyword:
@@ -970,7 +1153,7 @@ function_call_keyword:
 ;
 
 function_call_generic:
-  identifier '(' distinct_function_opt expr_list_opt ')' filter_opt
+  identifier '(' distinct_function_opt expr_list_opt ')' filter_opt over_opt
   {
     lowered := strings.ToLower(string($1))
     isCustom, ok := AllowedFunctions[lowered];
@@ -986,12 +1169,28 @@ function_call_generic:
       if $6 != nil {
         yylex.(*Lexer).AddError(errors.New("custom function cannot have FILTER"))
       }
+
+      if $7 != nil {
+        yylex.(*Lexer).AddError(errors.New("custom function cannot have OVER"))
+      }
       $$ = &CustomFuncExpr{Name: Identifier(lowered), Args: $4}
     } else {
-      $$ = &FuncExpr{Name: Identifier(lowered), Distinct: $3, Args: $4, Filter: $6}
+      if $3 && len($4) == 0 {
+        yylex.(*Lexer).AddError(errors.New("function with DISTINCT must have at least one argument"))
+      }
+      if err := validateFunctionArity(lowered, $4); err != nil {
+        yylex.(*Lexer).AddError(err)
+      }
+      if ok && $6 != nil && !isAggregateFunction(lowered, $4) {
+        yylex.(*Lexer).AddError(&ErrFilterOnNonAggregate{FunctionName: lowered})
+      }
+      if ok && $7 != nil && !isAggregateFunction(lowered, $4) {
+        yylex.(*Lexer).AddError(&ErrOverOnNonAggregate{FunctionName: lowered})
+      }
+      $$ = &FuncExpr{Name: Identifier(lowered), Distinct: $3, Args: $4, Filter: $6, Over: $7}
     }
   }
-| identifier '(' '*' ')' filter_opt
+| identifier '(' '*' ')' filter_opt over_opt
   {
     lowered := strings.ToLower(string($1))
     isCustom, ok := AllowedFunctions[lowered];
@@ -1002,7 +1201,16 @@ function_call_generic:
     if isCustom {
       yylex.(*Lexer).AddError(errors.New("custom function cannot be used with *"))
     } else {
-      $$ = &FuncExpr{Name: Identifier(lowered), Distinct: false, Args: nil, Filter: $5}
+      if err := validateFunctionArity(lowered, nil); err != nil {
+        yylex.(*Lexer).AddError(err)
+      }
+      if ok && $5 != nil && !isAggregateFunction(lowered, nil) {
+        yylex.(*Lexer).AddError(&ErrFilterOnNonAggregate{FunctionName: lowered})
+      }
+      if ok && $6 != nil && !isAggregateFunction(lowered, nil) {
+        yylex.(*Lexer).AddError(&ErrOverOnNonAggregate{FunctionName: lowered})
+      }
+      $$ = &FuncExpr{Name: Identifier(lowered), Distinct: false, Args: nil, Filter: $5, Over: $6}
     }
   }
 ;
@@ -1048,6 +1256,123 @@ filter_opt:
   }
 ;
 
+over_opt:
+  {
+    $$ = nil
+  }
+| OVER '(' partition_by_opt order_by_opt frame_spec_opt ')'
+  {
+    $$ = &WindowDef{PartitionBy: $3, OrderBy: $4, Frame: $5}
+  }
+;
+
+partition_by_opt:
+  {
+    $$ = nil
+  }
+| PARTITION BY expr_list
+  {
+    $$ = $3
+  }
+;
+
+frame_spec_opt:
+  {
+    $$ = nil
+  }
+| frame_unit frame_single_bound frame_exclude_opt
+  {
+    $$ = &FrameSpec{Unit: $1, Start: $2, Exclude: $3}
+  }
+| frame_unit BETWEEN frame_start_bound AND frame_end_bound frame_exclude_opt
+  {
+    $$ = &FrameSpec{Unit: $1, Start: $3, End: $5, Exclude: $6}
+  }
+;
+
+frame_unit:
+  ROWS
+  {
+    $$ = FrameRowsStr
+  }
+| RANGE
+  {
+    $$ = FrameRangeStr
+  }
+| GROUPS
+  {
+    $$ = FrameGroupsStr
+  }
+;
+
+// The bounds that are allowed follow SQLite's frame-spec syntax.
+frame_single_bound:
+  UNBOUNDED PRECEDING
+  {
+    $$ = &FrameBound{Type: FrameUnboundedPrecedingStr}
+  }
+| expr PRECEDING
+  {
+    $$ = &FrameBound{Type: FramePrecedingStr, Expr: $1}
+  }
+| CURRENT ROW
+  {
+    $$ = &FrameBound{Type: FrameCurrentRowStr}
+  }
+;
+
+frame_start_bound:
+  frame_single_bound
+  {
+    $$ = $1
+  }
+| expr FOLLOWING
+  {
+    $$ = &FrameBound{Type: FrameFollowingStr, Expr: $1}
+  }
+;
+
+frame_end_bound:
+  expr PRECEDING
+  {
+    $$ = &FrameBound{Type: FramePrecedingStr, Expr: $1}
+  }
+| CURRENT ROW
+  {
+    $$ = &FrameBound{Type: FrameCurrentRowStr}
+  }
+| expr FOLLOWING
+  {
+    $$ = &FrameBound{Type: FrameFollowingStr, Expr: $1}
+  }
+| UNBOUNDED FOLLOWING
+  {
+    $$ = &FrameBound{Type: FrameUnboundedFollowingStr}
+  }
+;
+
+frame_exclude_opt:
+  {
+    $$ = ""
+  }
+| EXCLUDE NO OTHERS
+  {
+    $$ = FrameExcludeNoOthersStr
+  }
+| EXCLUDE CURRENT ROW
+  {
+    $$ = FrameExcludeCurrentRowStr
+  }
+| EXCLUDE GROUP
+  {
+    $$ = FrameExcludeGroupStr
+  }
+| EXCLUDE TIES
+  {
+    $$ = FrameExcludeTiesStr
+  }
+;
+
 expr_opt:
   {
     $$ = nil
@@ -1091,6 +1416,8 @@ create_table_stmt:
     if len($5) > MaxAllowedColumns {
       yylex.(*Lexer).AddError(&ErrTooManyColumns{ColumnCount: len($5), MaxAllowed: MaxAllowedColumns})
     }
+    yylex.(*Lexer).validateUniqueColumnNames($5)
+    yylex.(*Lexer).validateUniqueConstraintNames($5, $6)
 
     // We have to replace a primary key table constraint with an equivalent column constraint primary key,
     // so we can add the autoincrement flag, as part of the rules of the Tableland Protocol.
@@ -1115,6 +1442,21 @@ create_table_stmt:
     }
     $3.IsTarget = true
     $$ = &CreateTable{Table: $3, ColumnsDef: $5, Constraints: $6}
+    yylex.(*Lexer).validateNoCustomFunctions($$)
+  }
+;
+
+create_view_stmt:
+  CREATE VIEW table_name column_name_list_opt AS read_stmt
+  {
+    if containsExcludedOutsideUpsert($6) {
+      yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+    }
+    yylex.(*Lexer).validateJoins($6)
+
+    $3.IsTarget = true
+    $$ = &CreateView{View: $3, Columns: $4, Select: $6}
+    yylex.(*Lexer).validateNoCustomFunctions($$)
   }
 ;
 
@@ -1136,12 +1478,12 @@ column_def:
       yylex.(*Lexer).AddError(&ErrRowIDNotAllowed{})
     }
 
-    if $2 == TypeIntegerStr {
-      for _, constraint := range $3 {
-        if primaryKey, ok := constraint.(*ColumnConstraintPrimaryKey); ok {
-          if primaryKey.Order != PrimaryKeyOrderDesc {
-            primaryKey.AutoIncrement = true
-          }
+    for _, constraint := range $3 {
+      if primaryKey, ok := constraint.(*ColumnConstraintPrimaryKey); ok {
+        if $2 == TypeIntegerStr && primaryKey.Order != PrimaryKeyOrderDesc {
+          primaryKey.AutoIncrement = true
+        } else if primaryKey.AutoIncrement {
+          yylex.(*Lexer).AddError(&ErrAutoIncrementNotAllowed{})
         }
       }
     }
@@ -1192,6 +1534,14 @@ column_constraint:
   {
     $$ = &ColumnConstraintPrimaryKey{Name: $1, Order: $4}
   }
+| constraint_name PRIMARY KEY primary_key_order IDENTIFIER
+  {
+    // AUTOINCREMENT is not allowed as an identifier, so it is lexed as one.
+    if !strings.EqualFold(string($5), "autoincrement") {
+      yylex.Error("syntax error")
+    }
+    $$ = &ColumnConstraintPrimaryKey{Name: $1, Order: $4, AutoIncrement: true}
+  }
 | constraint_name NOT NULL
   {
     $$ = &ColumnConstraintNotNull{Name: $1}
@@ -1216,6 +1566,15 @@ column_constraint:
   {
     $$ = &ColumnConstraintDefault{Name: $1, Expr: $3}
   }
+| constraint_name DEFAULT function_call_generic
+  {
+    // SQLite requires a function call to be parenthesized, but a call to a custom function is accepted here,
+    // so it's rejected by validateNoCustomFunctions with a specific error instead of a syntax error.
+    if _, ok := $3.(*CustomFuncExpr); !ok {
+      yylex.(*Lexer).AddError(errors.New("a DEFAULT function call must be parenthesized"))
+    }
+    $$ = &ColumnConstraintDefault{Name: $1, Expr: $3}
+  }
 | constraint_name GENERATED ALWAYS AS '(' expr ')' is_stored
   {
     $$ = &ColumnConstraintGenerated{Name: $1, Expr: $6, GeneratedAlways: true, IsStored: $8}
@@ -1367,7 +1726,7 @@ collate_opt:
 ;
 
 insert_stmt:
-  INSERT INTO table_name column_name_list_opt VALUES insert_rows upsert_clause_opt
+  INSERT INTO table_name column_name_list_opt VALUES insert_value_rows upsert_clause_opt
   {
     for i := 0; i < len($4); i++ {
       if isRowID($4[i].Name) {
@@ -1375,7 +1734,15 @@ insert_stmt:
       }
     }
 
-    for _, row := range $6 {
+    // without a column list, every row must have as many values as the first one
+    columnsCount := len($4)
+    if columnsCount == 0 && len($6) > 0 {
+      columnsCount = len($6[0])
+    }
+    for i, row := range $6 {
+      if len(row) != columnsCount {
+        yylex.(*Lexer).AddError(&ErrInsertArityMismatch{RowIndex: i, ColumnsCount: columnsCount, ValuesCount: len(row)})
+      }
       for _, expr := range row {
 				if containsSubquery(expr) {
           yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "insert"})
@@ -1410,9 +1777,9 @@ insert_stmt:
     }
 
     if sel, ok := $5.(*Select); ok {
-      if sel.OrderBy == nil {
-        sel.OrderBy = OrderBy{&OrderingTerm{Expr: &Column{Name: Identifier("rowid")}, Direction: AscStr, Nulls: NullsNil}}
-      } else {
+      // The rowid ordering term is not added again if it is already there (e.g. when parsing a deparsed statement).
+      // A SELECT without FROM has no rowid to order by.
+      if !yylex.(*Lexer).opts.DisableAutoOrderByRowid && sel.From != nil && !endsWithRowIDOrderingTerm(sel.OrderBy) {
         sel.OrderBy = append(sel.OrderBy, &OrderingTerm{Expr: &Column{Name: Identifier("rowid")}, Direction: AscStr, Nulls: NullsNil})
       }
 
@@ -1445,6 +1812,39 @@ insert_rows:
   }
 ;
 
+insert_value_rows:
+  '(' insert_value_list ')'
+  {
+    $$ = []Exprs{$2}
+  }
+| insert_value_rows ',' '(' insert_value_list ')'
+  {
+    $$ = append($1, $4)
+  }
+;
+
+insert_value_list:
+  insert_value
+  {
+    $$ = Exprs{$1}
+  }
+| insert_value_list ',' insert_value
+  {
+    $$ = append($1, $3)
+  }
+;
+
+insert_value:
+  expr
+| DEFAULT
+  {
+    if !yylex.(*Lexer).opts.AllowDefaultKeyword {
+      yylex.(*Lexer).AddError(&ErrDefaultKeywordNotAllowed{})
+    }
+    $$ = &DefaultExpr{}
+  }
+;
+
 upsert_clause_opt:
   {
     $$ = nil
@@ -1518,6 +1918,7 @@ delete_stmt:
     if $4 != nil && containsSubquery($4) {
       yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "delete"})
     }
+    yylex.(*Lexer).validateWhereOnDestructive("delete", $4)
     $3.IsTarget = true
     $$ = &Delete{Table: $3, Where: $4}
   }
@@ -1529,6 +1930,7 @@ update_stmt:
     if $5 != nil && containsSubquery($5) {
       yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "where"})
     }
+    yylex.(*Lexer).validateWhereOnDestructive("update", $5)
     $2.IsTarget = true
     $$ = &Update{Table: $2, Exprs: $4, Where: $5}
   }
@@ -1688,6 +2090,13 @@ alter_table_stmt:
       }
     }
 
+    yylex.(*Lexer).validateNoCustomFunctions($6)
+
+    // a NOT NULL column without a DEFAULT has a NULL default value
+    if hasNotNull && !hasDefault {
+      yylex.(*Lexer).AddError(&ErrNotNullConstraintDefaultNotNull{})
+    }
+
     if hasNotNull && hasDefault && defaultConstraint != nil {
       if _, ok := defaultConstraint.Expr.(*NullValue); ok {
         yylex.(*Lexer).AddError(&ErrNotNullConstraintDefaultNotNull{})
@@ -1715,6 +2124,90 @@ alter_table_stmt:
   }
 ;
 
+admin_stmt:
+  maintenance_stmt
+  {
+    if !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+      yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+    }
+    $$ = $1
+  }
+;
+
+maintenance_stmt:
+  VACUUM
+  {
+    $$ = &Vacuum{}
+  }
+| VACUUM identifier
+  {
+    $$ = &Vacuum{Schema: $2}
+  }
+| ANALYZE
+  {
+    $$ = &Analyze{}
+  }
+| ANALYZE table_name
+  {
+    $2.IsTarget = true
+    $$ = &Analyze{Table: $2}
+  }
+| REINDEX
+  {
+    $$ = &Reindex{}
+  }
+| REINDEX table_name
+  {
+    $2.IsTarget = true
+    $$ = &Reindex{Table: $2}
+  }
+;
+
+pragma_stmt:
+  PRAGMA identifier
+  {
+    pragma := &Pragma{Name: $2}
+    if !pragma.IsReadOnly() && !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+      yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+    }
+    $$ = pragma
+  }
+| PRAGMA identifier '=' pragma_value
+  {
+    $$ = &Pragma{Name: $2, Value: $4}
+    if !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+      yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+    }
+  }
+| PRAGMA identifier '(' pragma_value ')'
+  {
+    pragma := &Pragma{Name: $2, Arg: $4}
+    if !pragma.IsReadOnly() && !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+      yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+    }
+    $$ = pragma
+  }
+;
+
+pragma_value:
+  signed_number
+  {
+    $$ = $1
+  }
+| numeric_literal
+  {
+    $$ = $1
+  }
+| STRING
+  {
+    $$ = &Value{Type: StrValue, Value: $1[1:len($1)-1]}
+  }
+| identifier
+  {
+    $$ = &Column{Name: $1}
+  }
+;
+
 column_opt:
  {}
 | COLUMN
@@ -1,6 +1,8 @@
 package sqlparser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -33,6 +35,18 @@ func Walk(visit Visit, nodes ...Node) error {
 	return nil
 }
 
+// WalkExprs calls fn on every expression.
+// If fn returns true, the underlying expressions are not visited. If it returns an error, walking
+// is interrupted, and the error is returned.
+func WalkExprs(node Node, fn func(Expr) (bool, error)) error {
+	return Walk(func(node Node) (bool, error) {
+		if expr, ok := node.(Expr); ok {
+			return fn(expr)
+		}
+		return false, nil
+	}, node)
+}
+
 // GetUniqueTableReferences returns a slice of tables' names referenced by the node.
 func GetUniqueTableReferences(node Node) []string {
 	if node == nil {
@@ -174,6 +188,415 @@ func ValidateCreateTargetTable(table *Table) (*ValidatedCreateTable, error) {
 	return &ValidatedCreateTable{name: table.String(), prefix: prefix, chainID: chainID}, nil
 }
 
+var renamedTableNameRegEx = regexp.MustCompile("^([A-Za-z]+[A-Za-z0-9_.]*)*$")
+
+// UnquoteTableName returns the table name without its enclosing quotes, backticks or brackets,
+// and the opening and closing characters that were removed. They are empty if the name isn't quoted.
+func UnquoteTableName(name string) (unquoted, open, close string) {
+	closingChar := map[byte]byte{
+		'"': '"',
+		'`': '`',
+		'[': ']',
+	}
+
+	if len(name) < 2 {
+		return name, "", ""
+	}
+	if end, ok := closingChar[name[0]]; ok && name[len(name)-1] == end {
+		return name[1 : len(name)-1], name[:1], name[len(name)-1:]
+	}
+	return name, "", ""
+}
+
+// RenameTables renames the tables referenced by the node in place. nameMapper is called with the name
+// of each table without its quotes and returns the new name, or false if the table isn't renamed.
+// The renamed tables keep their original quoting.
+// It returns an error if a new name isn't a valid table name.
+func RenameTables(node Node, nameMapper func(string) (string, bool)) error {
+	return Walk(func(node Node) (bool, error) {
+		table, ok := node.(*Table)
+		if !ok || table == nil {
+			return false, nil
+		}
+		name, open, close := UnquoteTableName(string(table.Name))
+		renamed, ok := nameMapper(name)
+		if !ok {
+			return false, nil
+		}
+		renamed, renamedOpen, renamedClose := UnquoteTableName(renamed)
+		if !renamedTableNameRegEx.MatchString(renamed) {
+			return true, &ErrTableNameWrongFormat{Name: renamed}
+		}
+		if open == "" {
+			open, close = renamedOpen, renamedClose
+		}
+		table.Name = Identifier(open + renamed + close)
+		return false, nil
+	}, node)
+}
+
+// TargetTableName validates the target table of the i-th statement and returns the parts of its name.
+// The tokenID is zero for CREATE TABLE and CREATE VIEW statements, because the table or view doesn't have one
+// before it's created. It returns an error for statements without a target table, like SELECT.
+func (node *AST) TargetTableName(i int) (prefix string, chainID int64, tokenID int64, err error) {
+	if i < 0 || i >= len(node.Statements) {
+		return "", 0, 0, fmt.Errorf("statement index %d out of range (has %d statements)", i, len(node.Statements))
+	}
+
+	var created *Table
+	switch stmt := node.Statements[i].(type) {
+	case *CreateTable:
+		created = stmt.Table
+	case *CreateView:
+		created = stmt.View
+	}
+	if created != nil {
+		validTable, err := ValidateCreateTargetTable(created)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		return validTable.Prefix(), validTable.ChainID(), 0, nil
+	}
+
+	table := targetTable(node.Statements[i])
+	if table == nil {
+		return "", 0, 0, fmt.Errorf("statement has no target table")
+	}
+	validTable, err := ValidateTargetTable(table)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return validTable.Prefix(), validTable.ChainID(), validTable.TokenID(), nil
+}
+
+// Selects returns the SELECT statements of the AST, in order. Compound selects are not included.
+func (node *AST) Selects() []*Select {
+	selects := []*Select{}
+	for _, stmt := range node.Statements {
+		if sel, ok := stmt.(*Select); ok {
+			selects = append(selects, sel)
+		}
+	}
+	return selects
+}
+
+// Writes returns the write statements of the AST, i.e. INSERT, UPDATE, DELETE and ALTER TABLE, in order.
+func (node *AST) Writes() []WriteStatement {
+	writes := []WriteStatement{}
+	for _, stmt := range node.Statements {
+		if write, ok := stmt.(WriteStatement); ok {
+			writes = append(writes, write)
+		}
+	}
+	return writes
+}
+
+// Creates returns the CREATE TABLE statements of the AST, in order.
+func (node *AST) Creates() []*CreateTable {
+	creates := []*CreateTable{}
+	for _, stmt := range node.Statements {
+		if create, ok := stmt.(*CreateTable); ok {
+			creates = append(creates, create)
+		}
+	}
+	return creates
+}
+
+// RoleAddressRegEx is the default role format, an Ethereum address.
+var RoleAddressRegEx = regexp.MustCompile("^0x[a-fA-F0-9]{40}$")
+
+// ValidateRoles validates that the statement has at most maxRoles roles and that each role matches format.
+// A nil format defaults to RoleAddressRegEx.
+func ValidateRoles(stmt GrantOrRevokeStatement, format *regexp.Regexp, maxRoles int) error {
+	if format == nil {
+		format = RoleAddressRegEx
+	}
+
+	roles := stmt.GetRoles()
+	if len(roles) > maxRoles {
+		return &ErrTooManyRoles{RolesCount: len(roles), MaxAllowed: maxRoles}
+	}
+
+	for _, role := range roles {
+		if !format.MatchString(role) {
+			return &ErrInvalidRole{Role: role}
+		}
+	}
+
+	return nil
+}
+
+// ReferencedChainIDs returns the unique chain ids referenced by block_num calls in the node,
+// in the order they appear. It can be used to fetch the block numbers before resolving a read statement.
+func ReferencedChainIDs(node Node) ([]int64, error) {
+	chainIDs := []int64{}
+	if node == nil {
+		return chainIDs, nil
+	}
+
+	seen := map[int64]struct{}{}
+	if err := Walk(func(node Node) (bool, error) {
+		funcExpr, ok := node.(*CustomFuncExpr)
+		if !ok || funcExpr == nil || funcExpr.Name != "block_num" || len(funcExpr.Args) == 0 {
+			return false, nil
+		}
+
+		chainID, err := blockNumChainID(funcExpr.Args[0])
+		if err != nil {
+			return true, err
+		}
+
+		if _, ok := seen[chainID]; !ok {
+			seen[chainID] = struct{}{}
+			chainIDs = append(chainIDs, chainID)
+		}
+		return false, nil
+	}, node); err != nil {
+		return nil, fmt.Errorf("walk subtree: %s", err)
+	}
+
+	return chainIDs, nil
+}
+
+// HasCustomFunctions checks recursively if the node contains a Tableland custom function call.
+// It can be used to skip resolving statements that do not need it.
+func HasCustomFunctions(node Node) bool {
+	return containsNode(node, func(node Node) bool {
+		_, ok := node.(*CustomFuncExpr)
+		return ok
+	})
+}
+
+// HasParams checks recursively if the node contains a param placeholder.
+func HasParams(node Node) bool {
+	return containsNode(node, func(node Node) bool {
+		_, ok := node.(*Param)
+		return ok
+	})
+}
+
+// Subqueries returns all subqueries in the node, including nested ones, in the order they appear.
+// It can be used to apply checks to every subquery of a statement.
+func Subqueries(node Node) []*Subquery {
+	subqueries := []*Subquery{}
+	if node == nil {
+		return subqueries
+	}
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if subquery, ok := node.(*Subquery); ok {
+			subqueries = append(subqueries, subquery)
+		}
+		return false, nil
+	}, node)
+
+	return subqueries
+}
+
+// Literals returns all literal values in the node, including the ones in constraints like DEFAULT and CHECK,
+// in the order they appear. The node is not modified. TRUE, FALSE and NULL are not values, so they are not included.
+func Literals(node Node) []*Value {
+	literals := []*Value{}
+	if node == nil {
+		return literals
+	}
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if value, ok := node.(*Value); ok && value != nil {
+			literals = append(literals, value)
+		}
+		return false, nil
+	}, node)
+
+	return literals
+}
+
+// ShapeHash returns the hash of the node's shape, its string representation with every literal value replaced
+// by a ? placeholder, so statements that only differ in their literals, like a=1 and a='x', have the same hash.
+// It's meant to be used as a key for caching query plans. A literal and a param in the same position are
+// the same shape, while IN lists of different lengths and the TRUE, FALSE and NULL keywords are not normalized.
+// Integer ORDER BY and GROUP BY terms are column ordinals, so they are kept. The node is not modified.
+func ShapeHash(node Node) string {
+	var shape string
+	if node != nil {
+		node = cloneNode(node)
+		ordinals := ordinalTerms(node)
+		for _, value := range Literals(node) {
+			// column ordinals are part of the shape, because they select the column to order or group by
+			if _, ok := ordinals[value]; ok {
+				continue
+			}
+			// integer values are rendered as they are
+			value.Type = IntValue
+			value.Value = []byte("?")
+		}
+		shape = node.String()
+	}
+
+	hash := sha256.Sum256([]byte(shape))
+	return hex.EncodeToString(hash[:])
+}
+
+// ordinalTerms returns the integer literals that are ORDER BY or GROUP BY terms, i.e. column ordinals.
+func ordinalTerms(node Node) map[*Value]struct{} {
+	ordinals := map[*Value]struct{}{}
+	addOrdinal := func(expr Expr) {
+		for {
+			paren, ok := expr.(*ParenExpr)
+			if !ok || paren == nil {
+				break
+			}
+			expr = paren.Expr
+		}
+		if value, ok := expr.(*Value); ok && value != nil && (value.Type == IntValue || value.Type == HexNumValue) {
+			ordinals[value] = struct{}{}
+		}
+	}
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case GroupBy:
+			for _, expr := range node {
+				addOrdinal(expr)
+			}
+		case *OrderingTerm:
+			if node != nil {
+				addOrdinal(node.Expr)
+			}
+		}
+		return false, nil
+	}, node)
+
+	return ordinals
+}
+
+// IsCorrelated checks if the subquery, which must be part of outer, references columns of the tables in outer's scope,
+// e.g. exists (select 1 from t2 where t2.a = t.a) is correlated in select * from t where exists (...).
+// Without a schema, unqualified columns are assumed to reference the subquery's own tables when it has any.
+// References that can't be resolved, like columns of unknown tables, are ignored.
+func IsCorrelated(sub *Subquery, outer Node) bool {
+	if sub == nil || outer == nil {
+		return false
+	}
+
+	columns := map[*Column]struct{}{}
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if column, ok := node.(*Column); ok && column != nil {
+			columns[column] = struct{}{}
+		}
+		return false, nil
+	}, sub)
+
+	var outerScope *scope
+	correlated := false
+	r := &columnResolver{
+		ignoreAmbiguous: true,
+		onSubquery: func(subquery *Subquery, parent *scope) {
+			if subquery == sub {
+				outerScope = parent
+			}
+		},
+		onColumn: func(column *Column, table *scopeTable) error {
+			if _, ok := columns[column]; ok && table != nil && outerScope.contains(table) {
+				correlated = true
+			}
+			return nil
+		},
+	}
+	_ = r.node(outer, nil)
+
+	return correlated
+}
+
+// Conjuncts returns the predicates of a WHERE or HAVING clause that are combined with AND,
+// e.g. a=1 AND (b=2 AND c=3) returns [a=1, b=2, c=3]. OR expressions are not flattened.
+func Conjuncts(where *Where) []Expr {
+	if where == nil || where.Expr == nil {
+		return []Expr{}
+	}
+	return conjuncts(where.Expr, []Expr{})
+}
+
+func conjuncts(expr Expr, predicates []Expr) []Expr {
+	switch expr := expr.(type) {
+	case *AndExpr:
+		return conjuncts(expr.Right, conjuncts(expr.Left, predicates))
+	case *ParenExpr:
+		if _, ok := expr.Expr.(*AndExpr); ok {
+			return conjuncts(expr.Expr, predicates)
+		}
+	}
+	return append(predicates, expr)
+}
+
+// Conflict checks if both statements write to the same target table, so they must be executed serially.
+// Statements that don't target a table, like SELECT, never conflict.
+func Conflict(a, b Statement) bool {
+	tableA, tableB := targetTable(a), targetTable(b)
+	if tableA == nil || tableB == nil {
+		return false
+	}
+	return normalizeIdentifier(tableA.Name) == normalizeIdentifier(tableB.Name)
+}
+
+// ValidateBatchReferences checks that the statements of a batch only target tables created by a preceding
+// CREATE TABLE statement of the same batch, e.g. that a GRANT follows the CREATE of its table. Table names are compared
+// the same way SQLite does. It's meant for batches that are submitted in a single transaction.
+func ValidateBatchReferences(ast *AST) error {
+	created := map[string]struct{}{}
+	for i, stmt := range ast.Statements {
+		if create, ok := stmt.(*CreateTable); ok {
+			created[normalizeIdentifier(create.Table.Name)] = struct{}{}
+			continue
+		}
+
+		table := targetTable(stmt)
+		if table == nil {
+			continue
+		}
+		if _, ok := created[normalizeIdentifier(table.Name)]; !ok {
+			return &ErrUnknownTableInBatch{Name: table.String(), StatementIndex: i}
+		}
+	}
+	return nil
+}
+
+// targetTable returns the target table of a statement, or nil if the statement doesn't target a table.
+func targetTable(stmt Statement) *Table {
+	targeter, ok := stmt.(interface{ GetTable() *Table })
+	if !ok {
+		return nil
+	}
+	table := targeter.GetTable()
+	if table == nil || !table.IsTarget {
+		return nil
+	}
+	return table
+}
+
+// containsNode checks recursively if the node contains a node that matches.
+func containsNode(node Node, match func(Node) bool) bool {
+	if node == nil {
+		return false
+	}
+	var contains bool
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if match(node) {
+			contains = true
+			return true, nil
+		}
+		return false, nil
+	}, node)
+
+	return contains
+}
+
 // containsSubquery checks recursively if the node contains a subquery.
 func containsSubquery(node Node) bool {
 	if node == nil {
@@ -193,6 +616,79 @@ func containsSubquery(node Node) bool {
 	return containsSubquery
 }
 
+// isInListParam checks if the node is an IN operator whose list is a param, e.g. a IN ?.
+func isInListParam(node Node) (*Param, bool) {
+	cmpExpr, ok := node.(*CmpExpr)
+	if !ok || cmpExpr == nil || (cmpExpr.Operator != InStr && cmpExpr.Operator != NotInStr) {
+		return nil, false
+	}
+	param, ok := cmpExpr.Right.(*Param)
+	return param, ok
+}
+
+// containsInListParam checks recursively if the node contains an IN operator whose list is a param.
+func containsInListParam(node Node) bool {
+	if node == nil {
+		return false
+	}
+	var contains bool
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if _, ok := isInListParam(node); ok {
+			contains = true
+			return true, nil
+		}
+		return false, nil
+	}, node)
+
+	return contains
+}
+
+// isExcluded checks if the name refers to the upsert excluded pseudo-table.
+func isExcluded(name Identifier) bool {
+	return strings.EqualFold(string(name), "excluded")
+}
+
+// containsExcludedOutsideUpsert checks recursively if the node references the excluded pseudo-table
+// outside of an upsert DO UPDATE clause. References to a real table or an alias named excluded are allowed.
+func containsExcludedOutsideUpsert(node Node) bool {
+	if node == nil {
+		return false
+	}
+	var contains, declared bool
+
+	var visit Visit
+	visit = func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *OnConflictClause:
+			// the DO UPDATE clause is the only place where excluded can be referenced
+			if node != nil && node.Target != nil {
+				_ = Walk(visit, node.Target.Columns, node.Target.Where)
+			}
+			return true, nil
+		case *AliasedTableExpr:
+			if node != nil && isExcluded(node.As) {
+				declared = true
+			}
+		case *Table:
+			if node != nil && node.IsTarget && isExcluded(node.Name) {
+				declared = true
+			}
+		case *Column:
+			if node != nil && node.TableRef != nil && isExcluded(node.TableRef.Name) {
+				contains = true
+			}
+		}
+		return false, nil
+	}
+
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(visit, node)
+
+	return contains && !declared
+}
+
 // ValidatedTable is a Table that was validated by ValidateTargetTable.
 type ValidatedTable struct {
 	name    string
@@ -2,6 +2,7 @@ package sqlparser
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,6 +33,37 @@ func TestGetUniqueTableReferences(t *testing.T) {
 		require.NoError(t, err)
 		require.ElementsMatch(t, []string{"t"}, GetUniqueTableReferences(ast))
 	})
+
+	t.Run("upsert excluded", func(t *testing.T) {
+		t.Parallel()
+
+		sql := "insert into t (a, b) values (1, 2) on conflict (a) do update set b = excluded.b where excluded.b > t.b;"
+		ast, err := Parse(sql)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"t"}, GetUniqueTableReferences(ast))
+	})
+
+	t.Run("table named excluded", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string][]string{
+			"delete from excluded":                 {"excluded"},
+			"insert into excluded values (1)":      {"excluded"},
+			"select * from excluded":               {"excluded"},
+			"select excluded.a from t as excluded": {"t"},
+		}
+		for sql, tables := range tests {
+			ast, err := Parse(sql)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tables, GetUniqueTableReferences(ast))
+		}
+
+		// a real table named excluded is still validated
+		ast, err := Parse("delete from excluded")
+		require.NoError(t, err)
+		_, err = ValidateTargetTables(ast)
+		require.Error(t, err)
+	})
 }
 
 func TestValidateTargetTable(t *testing.T) {
@@ -155,3 +187,1081 @@ func TestWalk(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestWalkExprs(t *testing.T) {
+	t.Parallel()
+
+	ast, err := Parse("select a from t where a = 1 and b > abs(c) order by a")
+	require.NoError(t, err)
+	where := ast.Statements[0].(*Select).Where
+
+	t.Run("count expressions in where", func(t *testing.T) {
+		t.Parallel()
+
+		var count int
+		err := WalkExprs(where, func(expr Expr) (bool, error) {
+			count++
+			return false, nil
+		})
+		require.NoError(t, err)
+
+		// and, =, a, 1, >, b, abs(c), (c), c
+		require.Equal(t, 9, count)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		var columns []string
+		err := WalkExprs(ast, func(expr Expr) (bool, error) {
+			if _, ok := expr.(*FuncExpr); ok {
+				return true, nil
+			}
+			if column, ok := expr.(*Column); ok {
+				columns = append(columns, column.String())
+			}
+			return false, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "a", "b", "a"}, columns)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		err := WalkExprs(ast, func(expr Expr) (bool, error) {
+			if _, ok := expr.(*Value); ok {
+				return true, fmt.Errorf("found a value")
+			}
+			return false, nil
+		})
+		require.EqualError(t, err, "found a value")
+	})
+}
+
+func TestHasCustomFunctionsAndParams(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name      string
+		stmt      string
+		hasCustom bool
+		hasParams bool
+	}
+
+	tests := []testCase{
+		{name: "insert plain", stmt: "insert into t (a) values (1);", hasCustom: false, hasParams: false},
+		{name: "insert block_num", stmt: "insert into t (a) values (block_num());", hasCustom: true, hasParams: false},
+		{name: "insert txn_hash", stmt: "insert into t (a, b) values (1, txn_hash());", hasCustom: true, hasParams: false},
+		{name: "update plain", stmt: "update t set a = 1 where b = 2;", hasCustom: false, hasParams: false},
+		{name: "update block_num in where", stmt: "update t set a = 1 where b = block_num();", hasCustom: true, hasParams: false},
+		{name: "delete with param", stmt: "delete from t where a = ?;", hasCustom: false, hasParams: true},
+		{name: "select block_num and param", stmt: "select block_num(1) from t where a = ?;", hasCustom: true, hasParams: true},
+		{name: "select plain", stmt: "select a from t;", hasCustom: false, hasParams: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.hasCustom, HasCustomFunctions(ast))
+				require.Equal(t, tc.hasParams, HasParams(ast))
+			}
+		}(tc))
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+		require.False(t, HasCustomFunctions(nil))
+		require.False(t, HasParams(nil))
+	})
+}
+
+func TestValidateRoles(t *testing.T) {
+	t.Parallel()
+
+	address := "0xd43c59d5694ec111eb9e986c233200b14249558d"
+
+	t.Run("grant valid address", func(t *testing.T) {
+		t.Parallel()
+		ast, err := Parse(fmt.Sprintf("grant insert on t to '%s';", address))
+		require.NoError(t, err)
+		require.NoError(t, ValidateRoles(ast.Statements[0].(GrantOrRevokeStatement), nil, MaxAllowedRoles))
+	})
+
+	t.Run("revoke malformed role", func(t *testing.T) {
+		t.Parallel()
+		ast, err := Parse(fmt.Sprintf("revoke insert on t from '%s', '0xabc';", address))
+		require.NoError(t, err)
+
+		err = ValidateRoles(ast.Statements[0].(GrantOrRevokeStatement), nil, MaxAllowedRoles)
+		require.Error(t, err)
+		e := &ErrInvalidRole{}
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "0xabc", e.Role)
+	})
+
+	t.Run("too many roles", func(t *testing.T) {
+		t.Parallel()
+		ast, err := Parse(fmt.Sprintf("grant insert on t to '%s', '%s';", address, address))
+		require.NoError(t, err)
+
+		err = ValidateRoles(ast.Statements[0].(GrantOrRevokeStatement), nil, 1)
+		require.Error(t, err)
+		e := &ErrTooManyRoles{}
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, 2, e.RolesCount)
+		require.Equal(t, 1, e.MaxAllowed)
+	})
+
+	t.Run("custom format", func(t *testing.T) {
+		t.Parallel()
+		ast, err := Parse("grant insert on t to 'a', 'b';")
+		require.NoError(t, err)
+
+		stmt := ast.Statements[0].(GrantOrRevokeStatement)
+		require.Error(t, ValidateRoles(stmt, nil, MaxAllowedRoles))
+		require.NoError(t, ValidateRoles(stmt, regexp.MustCompile("^[a-z]$"), MaxAllowedRoles))
+	})
+}
+
+func TestReferencedChainIDs(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		chainIDs []int64
+		mustFail bool
+	}
+
+	tests := []testCase{
+		{
+			name:     "no block_num",
+			stmt:     "select a from t",
+			chainIDs: []int64{},
+		},
+		{
+			name:     "multiple block_num",
+			stmt:     "select block_num(1), block_num(5) from t where a > block_num(1) and b < block_num(1337)",
+			chainIDs: []int64{1, 5, 1337},
+		},
+		{
+			name:     "block_num in subquery",
+			stmt:     "select a from t where a in (select block_num(80001) from t2)",
+			chainIDs: []int64{80001},
+		},
+		{
+			name:     "block_num without argument",
+			stmt:     "update t set a = block_num()",
+			chainIDs: []int64{},
+		},
+		{
+			name:     "block_num with string argument",
+			stmt:     "select block_num('1') from t",
+			mustFail: true,
+		},
+		{
+			name:     "block_num with column argument",
+			stmt:     "select block_num(a) from t",
+			mustFail: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				chainIDs, err := ReferencedChainIDs(ast)
+				if tc.mustFail {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.chainIDs, chainIDs)
+			}
+		}(tc))
+	}
+}
+
+func BenchmarkWalk(b *testing.B) {
+	for _, query := range benchmarkQueries() {
+		ast, err := Parse(query.stmt)
+		require.NoError(b, err)
+
+		b.Run(query.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = Walk(func(node Node) (bool, error) {
+					return false, nil
+				}, ast)
+			}
+		})
+	}
+}
+
+func TestConjuncts(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		predicates []string
+	}
+
+	tests := []testCase{
+		{
+			name:       "no where",
+			stmt:       "select a from t",
+			predicates: []string{},
+		},
+		{
+			name:       "single predicate",
+			stmt:       "select a from t where a = 1",
+			predicates: []string{"a=1"},
+		},
+		{
+			name:       "and chain",
+			stmt:       "select a from t where a = 1 and b > 2 and c is null",
+			predicates: []string{"a=1", "b>2", "c is null"},
+		},
+		{
+			name:       "parenthesized and",
+			stmt:       "select a from t where (a = 1 and (b > 2 and c < 3)) and d",
+			predicates: []string{"a=1", "b>2", "c<3", "d"},
+		},
+		{
+			name:       "or is not flattened",
+			stmt:       "select a from t where a = 1 and (b = 2 or c = 3) and (d = 4 or e = 5 and f = 6)",
+			predicates: []string{"a=1", "(b=2 or c=3)", "(d=4 or e=5 and f=6)"},
+		},
+		{
+			name:       "top level or",
+			stmt:       "select a from t where a = 1 and b = 2 or c = 3",
+			predicates: []string{"a=1 and b=2 or c=3"},
+		},
+		{
+			name:       "between",
+			stmt:       "select a from t where a between 1 and 2 and b",
+			predicates: []string{"a between 1 and 2", "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				predicates := []string{}
+				for _, predicate := range Conjuncts(ast.Statements[0].(*Select).Where) {
+					predicates = append(predicates, predicate.String())
+				}
+				require.Equal(t, tc.predicates, predicates)
+			}
+		}(tc))
+	}
+
+	t.Run("having", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t group by a having count(*) > 1 and max(b) < 10")
+		require.NoError(t, err)
+		require.Equal(t, []Expr{
+			&CmpExpr{
+				Operator: GreaterThanStr,
+				Left:     &FuncExpr{Name: "count", Args: nil},
+				Right:    &Value{Type: IntValue, Value: []byte("1")},
+			},
+			&CmpExpr{
+				Operator: LessThanStr,
+				Left:     &FuncExpr{Name: "max", Args: Exprs{&Column{Name: "b"}}},
+				Right:    &Value{Type: IntValue, Value: []byte("10")},
+			},
+		}, Conjuncts(ast.Statements[0].(*Select).Having))
+	})
+}
+
+func TestSubqueries(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		subqueries []string
+	}
+
+	tests := []testCase{
+		{
+			name:       "no subqueries",
+			stmt:       "select a from t where a = 1",
+			subqueries: []string{},
+		},
+		{
+			name:       "subquery in where",
+			stmt:       "select a from t where a in (select a from t2)",
+			subqueries: []string{"(select a from t2)"},
+		},
+		{
+			name:       "subquery in from",
+			stmt:       "select a from (select a from t2) s",
+			subqueries: []string{"(select a from t2)"},
+		},
+		{
+			name: "nested subqueries in where and from",
+			stmt: "select a from (select a from t2 where b in (select b from t3)) s where exists (select 1 from t4 where c = (select max(c)from t5))",
+			subqueries: []string{
+				"(select a from t2 where b in(select b from t3))",
+				"(select b from t3)",
+				"(select 1 from t4 where c=(select max(c)from t5))",
+				"(select max(c)from t5)",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				subqueries := []string{}
+				for _, subquery := range Subqueries(ast) {
+					subqueries = append(subqueries, subquery.String())
+				}
+				require.Equal(t, tc.subqueries, subqueries)
+			}
+		}(tc))
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, Subqueries(nil))
+	})
+}
+
+func TestIsCorrelated(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		correlated []bool
+	}
+
+	tests := []testCase{
+		{
+			name:       "correlated",
+			stmt:       "select a from t as o where exists (select 1 from t2 where t2.a = o.a)",
+			correlated: []bool{true},
+		},
+		{
+			name:       "uncorrelated",
+			stmt:       "select a from t as o where a in (select a from t2 where t2.b = 1)",
+			correlated: []bool{false},
+		},
+		{
+			name:       "unqualified column without from",
+			stmt:       "select a, (select a+1) from t",
+			correlated: []bool{true},
+		},
+		{
+			name:       "shadowed alias",
+			stmt:       "select a from t where exists (select 1 from t2 as t where t.a = 1)",
+			correlated: []bool{false},
+		},
+		{
+			name:       "join in subquery",
+			stmt:       "select a from t where exists (select 1 from t2 join t3 on t2.a = t3.a where b = 1)",
+			correlated: []bool{false},
+		},
+		{
+			name:       "nested correlation",
+			stmt:       "select a from t where exists (select 1 from t2 where t2.b in (select b from t3 where t3.a = t.a))",
+			correlated: []bool{true, true},
+		},
+		{
+			name:       "correlated to enclosing subquery",
+			stmt:       "select a from t where exists (select 1 from t2 where t2.b in (select b from t3 where t3.a = t2.a))",
+			correlated: []bool{false, true},
+		},
+		{
+			name:       "derived table",
+			stmt:       "select a from t, (select a from t2) s where t.a = s.a",
+			correlated: []bool{false},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				correlated := []bool{}
+				for _, subquery := range Subqueries(ast) {
+					correlated = append(correlated, IsCorrelated(subquery, ast))
+				}
+				require.Equal(t, tc.correlated, correlated)
+			}
+		}(tc))
+	}
+
+	t.Run("subquery not in outer", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t where exists (select 1 from t2 where t2.a = t.a)")
+		require.NoError(t, err)
+		other, err := Parse("select a from t")
+		require.NoError(t, err)
+
+		require.False(t, IsCorrelated(Subqueries(ast)[0], other))
+		require.False(t, IsCorrelated(nil, ast))
+	})
+}
+
+func TestLiterals(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		literals []*Value
+	}
+
+	tests := []testCase{
+		{
+			name: "complex insert",
+			stmt: "insert into t (a, b, c, d) values (1, 'x', 0x1F, x'ab'), (-2, null, true, 'it''s') " +
+				"on conflict (a) where a > 10 do update set b = 'y' || excluded.b where c < 3",
+			literals: []*Value{
+				{Type: IntValue, Value: []byte("1")},
+				{Type: StrValue, Value: []byte("x")},
+				{Type: HexNumValue, Value: []byte("0x1F")},
+				{Type: BlobValue, Value: []byte("ab")},
+				{Type: IntValue, Value: []byte("-2")},
+				{Type: StrValue, Value: []byte("it''s")},
+				{Type: IntValue, Value: []byte("10")},
+				{Type: StrValue, Value: []byte("y")},
+				{Type: IntValue, Value: []byte("3")},
+			},
+		},
+		{
+			name: "constraints",
+			stmt: "create table t (a int default 7 check (a > 0), b text default ('z'), constraint c check (b != 'w'))",
+			literals: []*Value{
+				{Type: IntValue, Value: []byte("7")},
+				{Type: IntValue, Value: []byte("0")},
+				{Type: StrValue, Value: []byte("z")},
+				{Type: StrValue, Value: []byte("w")},
+			},
+		},
+		{
+			name: "select",
+			stmt: "select a, 'b' from t where c in (select 1 from t2) limit 5",
+			literals: []*Value{
+				{Type: StrValue, Value: []byte("b")},
+				{Type: IntValue, Value: []byte("1")},
+				{Type: IntValue, Value: []byte("5")},
+			},
+		},
+		{
+			name: "order by and limit",
+			stmt: "select a from t where b = 1 order by c = 2 limit 3 offset 4",
+			literals: []*Value{
+				{Type: IntValue, Value: []byte("1")},
+				{Type: IntValue, Value: []byte("2")},
+				{Type: IntValue, Value: []byte("3")},
+				{Type: IntValue, Value: []byte("4")},
+			},
+		},
+		{
+			name: "comma limit",
+			stmt: "select a from t limit 4, 3",
+			literals: []*Value{
+				{Type: IntValue, Value: []byte("4")},
+				{Type: IntValue, Value: []byte("3")},
+			},
+		},
+		{
+			name:     "no literals",
+			stmt:     "delete from t where a = b",
+			literals: []*Value{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				require.Equal(t, tc.literals, Literals(ast))
+				require.Equal(t, original, ast.String())
+			}
+		}(tc))
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, Literals(nil))
+	})
+}
+
+func TestShapeHash(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name      string
+		stmt      string
+		other     string
+		sameShape bool
+	}
+
+	tests := []testCase{
+		{
+			name:      "where literal",
+			stmt:      "select a from t where a = 1",
+			other:     "select a from t where a = 2",
+			sameShape: true,
+		},
+		{
+			name:      "literal types",
+			stmt:      "insert into t (a, b, c) values ('x', 0x10, x'ab') on conflict (a) do update set b = -1",
+			other:     "insert into t (a, b, c) values (3, 'y', 42) on conflict (a) do update set b = 'z'",
+			sameShape: true,
+		},
+		{
+			name:      "limit and function arguments",
+			stmt:      "select substr(a, 1, 2) from t where b like 'x%' limit 10",
+			other:     "select substr(a, 3, 4) from t where b like 'y%' limit 20",
+			sameShape: true,
+		},
+		{
+			name:      "param and literal",
+			stmt:      "update t set a = ? where b = 1",
+			other:     "update t set a = 'x' where b = ?",
+			sameShape: true,
+		},
+		{
+			name:  "order by ordinal",
+			stmt:  "select a, b from t order by 1",
+			other: "select a, b from t order by 2",
+		},
+		{
+			name:  "group by ordinal",
+			stmt:  "select a, b from t group by 1",
+			other: "select a, b from t group by 2",
+		},
+		{
+			name:      "order by expression",
+			stmt:      "select a, b from t order by a + 1, (2)",
+			other:     "select a, b from t order by a + 5, (2)",
+			sameShape: true,
+		},
+		{
+			name:  "different column",
+			stmt:  "select a from t where a = 1",
+			other: "select a from t where b = 1",
+		},
+		{
+			name:  "different operator",
+			stmt:  "select a from t where a = 1",
+			other: "select a from t where a > 1",
+		},
+		{
+			name:  "different in list length",
+			stmt:  "select a from t where a in (1, 2)",
+			other: "select a from t where a in (1, 2, 3)",
+		},
+		{
+			name:  "null is not a literal value",
+			stmt:  "select a from t where a = 1",
+			other: "select a from t where a = null",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				other, err := Parse(tc.other)
+				require.NoError(t, err)
+				original := ast.String()
+
+				require.NotEqual(t, ast.String(), other.String())
+				if tc.sameShape {
+					require.Equal(t, ShapeHash(ast), ShapeHash(other))
+				} else {
+					require.NotEqual(t, ShapeHash(ast), ShapeHash(other))
+				}
+
+				// the hash is stable and the node is not modified
+				require.Equal(t, ShapeHash(ast), ShapeHash(ast))
+				require.Equal(t, original, ast.String())
+			}
+		}(tc))
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Len(t, ShapeHash(nil), 64)
+	})
+}
+
+func TestConflict(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		a        string
+		b        string
+		conflict bool
+	}
+
+	tests := []testCase{
+		{
+			name:     "inserts to the same table",
+			a:        "insert into t (a) values (1)",
+			b:        "insert into t (a) values (2)",
+			conflict: true,
+		},
+		{
+			name:     "inserts to different tables",
+			a:        "insert into t (a) values (1)",
+			b:        "insert into t2 (a) values (1)",
+			conflict: false,
+		},
+		{
+			name:     "update and delete on the same table",
+			a:        "update t set a = 1",
+			b:        "delete from t where a = 2",
+			conflict: true,
+		},
+		{
+			name:     "quoted table name",
+			a:        "insert into \"t\" (a) values (1)",
+			b:        "delete from t",
+			conflict: true,
+		},
+		{
+			name:     "grant and insert on the same table",
+			a:        "grant insert on t to 'a'",
+			b:        "insert into t (a) values (1)",
+			conflict: true,
+		},
+		{
+			name:     "referenced table is not a target",
+			a:        "insert into t (a) select a from t2",
+			b:        "delete from t2",
+			conflict: false,
+		},
+		{
+			name:     "select does not conflict",
+			a:        "select a from t",
+			b:        "insert into t (a) values (1)",
+			conflict: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				astA, err := Parse(tc.a)
+				require.NoError(t, err)
+				astB, err := Parse(tc.b)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.conflict, Conflict(astA.Statements[0], astB.Statements[0]))
+				require.Equal(t, tc.conflict, Conflict(astB.Statements[0], astA.Statements[0]))
+			}
+		}(tc))
+	}
+}
+
+func TestRenameQuotedTables(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		stmt    string
+		renamed string
+	}
+
+	tests := []testCase{
+		{
+			name:    "brackets",
+			stmt:    "select [t].[a] from [t] where [t].b = 1",
+			renamed: "select [t_1337_1].[a] from [t_1337_1] where [t_1337_1].b=1",
+		},
+		{
+			name:    "backticks",
+			stmt:    "insert into `t` (a) select `t2`.a from `t2`",
+			renamed: "insert into `t_1337_1`(a)select `t2_1337_2`.a from `t2_1337_2` order by rowid asc",
+		},
+		{
+			name:    "double quotes",
+			stmt:    "update \"t\" set a = 1 where \"t\".a > 1",
+			renamed: "update \"t_1337_1\" set a=1 where \"t_1337_1\".a>1",
+		},
+		{
+			name:    "mixed",
+			stmt:    "select [t].a from [t] where a in (select a from `t2` join \"t\" on `t2`.a = \"t\".a)",
+			renamed: "select [t_1337_1].a from [t_1337_1] where a in(select a from `t2_1337_2` join \"t_1337_1\" on `t2_1337_2`.a=\"t_1337_1\".a)",
+		},
+	}
+
+	names := map[string]string{"t": "t_1337_1", "t2": "t2_1337_2"}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				require.NoError(t, RenameTables(ast, func(name string) (string, bool) {
+					renamed, ok := names[name]
+					return renamed, ok
+				}))
+				require.Equal(t, tc.renamed, ast.String())
+
+				// the renamed tables are still valid target tables
+				validTables, err := ValidateTargetTables(ast)
+				require.NoError(t, err)
+				require.NotEmpty(t, validTables)
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, tc.renamed, reparsed.String())
+			}
+		}(tc))
+	}
+
+	t.Run("unquoted and unmapped", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select * from t join foo on t.a = foo.a")
+		require.NoError(t, err)
+
+		var mapped []string
+		require.NoError(t, RenameTables(ast, func(name string) (string, bool) {
+			mapped = append(mapped, name)
+			renamed, ok := names[name]
+			return renamed, ok
+		}))
+		require.Equal(t, "select * from t_1337_1 join foo on t_1337_1.a=foo.a", ast.String())
+		require.ElementsMatch(t, []string{"t", "foo", "t", "foo"}, mapped)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select * from [t]")
+		require.NoError(t, err)
+
+		err = RenameTables(ast, func(name string) (string, bool) {
+			return "t 1337", true
+		})
+		require.ErrorAs(t, err, new(*ErrTableNameWrongFormat))
+		require.Equal(t, "select * from [t]", ast.String())
+	})
+
+	t.Run("unquote", func(t *testing.T) {
+		t.Parallel()
+
+		for name, expected := range map[string][3]string{
+			"t":   {"t", "", ""},
+			"[t]": {"t", "[", "]"},
+			"`t`": {"t", "`", "`"},
+			`"t"`: {"t", `"`, `"`},
+			"[t`": {"[t`", "", ""},
+			"[":   {"[", "", ""},
+		} {
+			unquoted, open, close := UnquoteTableName(name)
+			require.Equal(t, expected, [3]string{unquoted, open, close}, name)
+		}
+	})
+}
+
+func TestTargetTableName(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		prefix      string
+		chainID     int64
+		tokenID     int64
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:    "create",
+			stmt:    "create table foo_1337 (a int)",
+			prefix:  "foo",
+			chainID: 1337,
+		},
+		{
+			name:    "create view",
+			stmt:    "create view bar_1337 as select a from foo_1337_1",
+			prefix:  "bar",
+			chainID: 1337,
+		},
+		{
+			name:    "insert",
+			stmt:    "insert into foo_1337_1 values (1)",
+			prefix:  "foo",
+			chainID: 1337,
+			tokenID: 1,
+		},
+		{
+			name:    "update with quoted name",
+			stmt:    "update [my_foo_5_10] set a = 1",
+			prefix:  "my_foo",
+			chainID: 5,
+			tokenID: 10,
+		},
+		{
+			name:    "delete",
+			stmt:    "delete from _80001_2",
+			prefix:  "",
+			chainID: 80001,
+			tokenID: 2,
+		},
+		{
+			name:    "grant",
+			stmt:    "grant insert on foo_1_7 to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			prefix:  "foo",
+			chainID: 1,
+			tokenID: 7,
+		},
+		{
+			name:    "alter table",
+			stmt:    "alter table foo_1337_3 add column b text",
+			prefix:  "foo",
+			chainID: 1337,
+			tokenID: 3,
+		},
+		{
+			name:        "write with wrong format",
+			stmt:        "insert into foo values (1)",
+			expectedErr: &ErrTableNameWrongFormat{Name: "foo"},
+		},
+		{
+			name:        "create without chain id",
+			stmt:        "create table foo (a int)",
+			expectedErr: &ErrTableNameWrongFormat{Name: "foo"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				prefix, chainID, tokenID, err := ast.TargetTableName(0)
+				if tc.expectedErr != nil {
+					require.Equal(t, tc.expectedErr, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.prefix, prefix)
+				require.Equal(t, tc.chainID, chainID)
+				require.Equal(t, tc.tokenID, tokenID)
+			}
+		}(tc))
+	}
+
+	t.Run("statement index", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("insert into foo_1337_1 values (1); delete from bar_5_2")
+		require.NoError(t, err)
+
+		prefix, chainID, tokenID, err := ast.TargetTableName(1)
+		require.NoError(t, err)
+		require.Equal(t, "bar", prefix)
+		require.Equal(t, int64(5), chainID)
+		require.Equal(t, int64(2), tokenID)
+
+		_, _, _, err = ast.TargetTableName(2)
+		require.EqualError(t, err, "statement index 2 out of range (has 2 statements)")
+	})
+
+	t.Run("read statement", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from foo_1337_1")
+		require.NoError(t, err)
+
+		_, _, _, err = ast.TargetTableName(0)
+		require.EqualError(t, err, "statement has no target table")
+	})
+}
+func TestTypedStatements(t *testing.T) {
+	t.Parallel()
+
+	// statements of different kinds can't be parsed together, so the AST is built from separate inputs
+	mixed := &AST{}
+	for _, stmt := range []string{
+		"select a from t",
+		"insert into t (a) values (1); update t set a = 2 where a = 1",
+		"create table t2 (a int)",
+		"select a from t union select a from t2",
+		"delete from t where a = 2",
+		"alter table t add column b int",
+		"grant insert on t to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+		"select b from t",
+		"create table t3 (a int)",
+	} {
+		ast, err := Parse(stmt)
+		require.NoError(t, err)
+		mixed.Statements = append(mixed.Statements, ast.Statements...)
+	}
+
+	selects := []string{}
+	for _, sel := range mixed.Selects() {
+		selects = append(selects, sel.String())
+	}
+	require.Equal(t, []string{"select a from t", "select b from t"}, selects)
+
+	writes := []string{}
+	for _, write := range mixed.Writes() {
+		writes = append(writes, write.String())
+	}
+	require.Equal(t, []string{
+		"insert into t(a)values(1)",
+		"update t set a=2 where a=1",
+		"delete from t where a=2",
+		"alter table t add b int",
+	}, writes)
+
+	creates := []string{}
+	for _, create := range mixed.Creates() {
+		creates = append(creates, create.String())
+	}
+	require.Equal(t, []string{"create table t2(a int)", "create table t3(a int)"}, creates)
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("")
+		require.NoError(t, err)
+		require.Empty(t, ast.Selects())
+		require.Empty(t, ast.Writes())
+		require.Empty(t, ast.Creates())
+	})
+}
+
+func TestValidateBatchReferences(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmts       []string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "grant after create",
+			stmts: []string{
+				"create table t (a int)",
+				"grant insert on t to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			},
+		},
+		{
+			name: "writes and revoke after create",
+			stmts: []string{
+				"create table t (a int)",
+				"create table t2 (a int)",
+				"insert into t (a) values (1); update T set a = 2",
+				"delete from \"t2\"",
+				"revoke insert on [t] from '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			},
+		},
+		{
+			name: "reads are not checked",
+			stmts: []string{
+				"create table t (a int)",
+				"select a from t2",
+			},
+		},
+		{
+			name: "grant on a table that is not created",
+			stmts: []string{
+				"create table t (a int)",
+				"grant insert on t2 to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			},
+			expectedErr: &ErrUnknownTableInBatch{Name: "t2", StatementIndex: 1},
+		},
+		{
+			name: "grant before create",
+			stmts: []string{
+				"grant insert on t to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+				"create table t (a int)",
+			},
+			expectedErr: &ErrUnknownTableInBatch{Name: "t", StatementIndex: 0},
+		},
+		{
+			name: "write to a table that is not created",
+			stmts: []string{
+				"create table t (a int)",
+				"insert into t (a) values (1); delete from t2",
+			},
+			expectedErr: &ErrUnknownTableInBatch{Name: "t2", StatementIndex: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				// statements of different kinds can't be parsed together, so the batch is built from separate inputs
+				batch := &AST{}
+				for _, stmt := range tc.stmts {
+					ast, err := Parse(stmt)
+					require.NoError(t, err)
+					batch.Statements = append(batch.Statements, ast.Statements...)
+				}
+
+				err := ValidateBatchReferences(batch)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrUnknownTableInBatch))
+				require.EqualError(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
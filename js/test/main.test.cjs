@@ -87,6 +87,14 @@ describe("sqlparser", function () {
       );
     });
 
+    test("when a statement has comments", async function () {
+      const { type, statements } = await globalThis.sqlparser.normalize(
+        "SELECT a /* x */ FROM t -- trailing comment"
+      );
+      strictEqual(type, "read");
+      deepStrictEqual(statements, ["select a from t"]);
+    });
+
     test("when there are multiple write statements", async function () {
       const { type, statements } = await globalThis.sqlparser.normalize(`
       insert into blah_5_ values (1, 'three', 'something');
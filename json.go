@@ -0,0 +1,592 @@
+package sqlparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ASTJSONVersion is the version of the JSON schema produced by MarshalAST.
+// It only changes when the schema changes in a backwards incompatible way.
+const ASTJSONVersion = 1
+
+// MarshalAST returns an indented JSON representation of the node.
+//
+// The JSON schema is independent of the Go struct definitions, so internal refactors
+// do not affect consumers. Every node is encoded as an object with a "nodeType" key
+// (e.g. "select", "column", "binaryExpr") followed by its camelCase attributes.
+// Optional attributes that are absent are encoded as null, and lists are always arrays.
+// The root AST is encoded as {"nodeType": "ast", "version": ASTJSONVersion, "statements": [...]}.
+func MarshalAST(node Node) ([]byte, error) {
+	enc := &astEncoder{}
+	v := enc.node(node)
+	if enc.err != nil {
+		return nil, enc.err
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+type jsonObject map[string]interface{}
+
+type astEncoder struct {
+	err error
+}
+
+func (e *astEncoder) exprs(exprs []Expr) []interface{} {
+	list := make([]interface{}, 0, len(exprs))
+	for _, n := range exprs {
+		list = append(list, e.node(n))
+	}
+	return list
+}
+
+func (e *astEncoder) identifier(id Identifier) interface{} {
+	if id.IsEmpty() {
+		return nil
+	}
+	return id.String()
+}
+
+func (e *astEncoder) node(node Node) interface{} {
+	if e.err != nil || node == nil {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case *AST:
+		if node == nil {
+			return nil
+		}
+		stmts := make([]interface{}, 0, len(node.Statements))
+		for _, stmt := range node.Statements {
+			stmts = append(stmts, e.node(stmt))
+		}
+		return jsonObject{"nodeType": "ast", "version": ASTJSONVersion, "statements": stmts}
+	case *Select:
+		if node == nil {
+			return nil
+		}
+		cols := make([]interface{}, 0, len(node.SelectColumnList))
+		for _, col := range node.SelectColumnList {
+			cols = append(cols, e.node(col))
+		}
+		return jsonObject{
+			"nodeType": "select",
+			"distinct": node.Distinct == DistinctStr,
+			"all":      node.Distinct == AllStr,
+			"columns":  cols,
+			"from":     e.node(node.From),
+			"where":    e.node(node.Where),
+			"groupBy":  e.exprs(node.GroupBy),
+			"having":   e.node(node.Having),
+			"orderBy":  e.node(node.OrderBy),
+			"limit":    e.node(node.Limit),
+		}
+	case *CompoundSelect:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "compoundSelect", "operator": node.Type, "left": e.node(node.Left), "right": e.node(node.Right)}
+	case *Values:
+		if node == nil {
+			return nil
+		}
+		rows := make([]interface{}, 0, len(node.Rows))
+		for _, row := range node.Rows {
+			rows = append(rows, e.exprs(row))
+		}
+		return jsonObject{"nodeType": "values", "rows": rows}
+	case *StarSelectColumn:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "starColumn", "table": e.node(node.TableRef)}
+	case *AliasedSelectColumn:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "aliasedColumn", "expr": e.node(node.Expr), "alias": e.identifier(node.As)}
+	case *AliasedTableExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "aliasedTableExpr", "expr": e.node(node.Expr), "alias": e.identifier(node.As)}
+	case *Subquery:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "subquery", "select": e.node(node.Select)}
+	case *ParenTableExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "parenTableExpr", "expr": e.node(node.TableExpr)}
+	case *JoinTableExpr:
+		if node == nil {
+			return nil
+		}
+		var using interface{}
+		if node.Using != nil {
+			using = e.node(node.Using)
+		}
+		return jsonObject{
+			"nodeType": "joinTableExpr",
+			"left":     e.node(node.LeftExpr),
+			"operator": e.node(node.JoinOperator),
+			"right":    e.node(node.RightExpr),
+			"on":       e.node(node.On),
+			"using":    using,
+		}
+	case *JoinOperator:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "joinOperator", "operator": node.Op, "natural": node.Natural, "outer": node.Outer}
+	case *Where:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": node.Type, "expr": e.node(node.Expr)}
+	case GroupBy:
+		return e.exprs(node)
+	case OrderBy:
+		terms := make([]interface{}, 0, len(node))
+		for _, term := range node {
+			terms = append(terms, e.node(term))
+		}
+		return terms
+	case *OrderingTerm:
+		if node == nil {
+			return nil
+		}
+		var nulls interface{}
+		switch node.Nulls {
+		case NullsFirst:
+			nulls = "first"
+		case NullsLast:
+			nulls = "last"
+		}
+		return jsonObject{"nodeType": "orderingTerm", "expr": e.node(node.Expr), "direction": node.Direction, "nulls": nulls}
+	case *Limit:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "limit", "limit": e.node(node.Limit), "offset": e.node(node.Offset)}
+	case *NullValue:
+		return jsonObject{"nodeType": "null"}
+	case BoolValue:
+		return jsonObject{"nodeType": "bool", "value": bool(node)}
+	case *Value:
+		if node == nil {
+			return nil
+		}
+		var typ string
+		switch node.Type {
+		case StrValue:
+			typ = "string"
+		case IntValue:
+			typ = "integer"
+		case FloatValue:
+			typ = "float"
+		case HexNumValue:
+			typ = "hex"
+		case BlobValue:
+			typ = "blob"
+		}
+		return jsonObject{"nodeType": "value", "type": typ, "value": string(node.Value)}
+	case *UnaryExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "unaryExpr", "operator": node.Operator, "expr": e.node(node.Expr)}
+	case *BinaryExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "binaryExpr", "operator": node.Operator, "left": e.node(node.Left), "right": e.node(node.Right)}
+	case *CmpExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType": "cmpExpr",
+			"operator": node.Operator,
+			"left":     e.node(node.Left),
+			"right":    e.node(node.Right),
+			"escape":   e.node(node.Escape),
+		}
+	case *AndExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "andExpr", "left": e.node(node.Left), "right": e.node(node.Right)}
+	case *OrExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "orExpr", "left": e.node(node.Left), "right": e.node(node.Right)}
+	case *NotExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "notExpr", "expr": e.node(node.Expr)}
+	case *IsExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "isExpr", "left": e.node(node.Left), "right": e.node(node.Right)}
+	case *IsNullExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "isNullExpr", "expr": e.node(node.Expr)}
+	case *NotNullExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "notNullExpr", "expr": e.node(node.Expr)}
+	case *CollateExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "collateExpr", "expr": e.node(node.Expr), "collation": node.CollationName.String()}
+	case *ConvertExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "castExpr", "expr": e.node(node.Expr), "type": string(node.Type)}
+	case *BetweenExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType": "betweenExpr",
+			"operator": node.Operator,
+			"left":     e.node(node.Left),
+			"from":     e.node(node.From),
+			"to":       e.node(node.To),
+		}
+	case *CaseExpr:
+		if node == nil {
+			return nil
+		}
+		whens := make([]interface{}, 0, len(node.Whens))
+		for _, when := range node.Whens {
+			whens = append(whens, jsonObject{"nodeType": "when", "condition": e.node(when.Condition), "value": e.node(when.Value)})
+		}
+		return jsonObject{"nodeType": "caseExpr", "expr": e.node(node.Expr), "whens": whens, "else": e.node(node.Else)}
+	case *Table:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "table", "name": node.Name.String(), "isTarget": node.IsTarget}
+	case *Column:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "column", "name": node.Name.String(), "table": e.node(node.TableRef)}
+	case ColumnList:
+		list := make([]interface{}, 0, len(node))
+		for _, col := range node {
+			list = append(list, e.node(col))
+		}
+		return list
+	case *IndexedColumn:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType":  "indexedColumn",
+			"column":    e.node(node.Column),
+			"collation": e.identifier(node.CollationName),
+			"order":     node.Order,
+		}
+	case IndexedColumnList:
+		list := make([]interface{}, 0, len(node))
+		for _, col := range node {
+			list = append(list, e.node(col))
+		}
+		return list
+	case Exprs:
+		return jsonObject{"nodeType": "exprList", "exprs": e.exprs(node)}
+	case *ExistsExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "existsExpr", "subquery": e.node(node.Subquery)}
+	case *FuncExpr:
+		if node == nil {
+			return nil
+		}
+		var args interface{}
+		if node.Args != nil {
+			args = e.exprs(node.Args)
+		}
+		return jsonObject{
+			"nodeType": "funcExpr",
+			"name":     node.Name.String(),
+			"distinct": node.Distinct,
+			"star":     node.Args == nil,
+			"args":     args,
+			"filter":   e.node(node.Filter),
+			"over":     e.node(node.Over),
+		}
+	case *WindowDef:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType":    "windowDef",
+			"partitionBy": e.exprs(node.PartitionBy),
+			"orderBy":     e.node(node.OrderBy),
+			"frame":       e.node(node.Frame),
+		}
+	case *FrameSpec:
+		if node == nil {
+			return nil
+		}
+		var exclude interface{}
+		if node.Exclude != "" {
+			exclude = node.Exclude
+		}
+		return jsonObject{
+			"nodeType": "frameSpec",
+			"unit":     node.Unit,
+			"start":    e.node(node.Start),
+			"end":      e.node(node.End),
+			"exclude":  exclude,
+		}
+	case *FrameBound:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "frameBound", "type": node.Type, "expr": e.node(node.Expr)}
+	case *CustomFuncExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "customFuncExpr", "name": node.Name.String(), "args": e.exprs(node.Args)}
+	case *ParenExpr:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "parenExpr", "expr": e.node(node.Expr)}
+	case Identifier:
+		return jsonObject{"nodeType": "identifier", "name": node.String()}
+	case *Param:
+		return jsonObject{"nodeType": "param"}
+	case *DefaultExpr:
+		return jsonObject{"nodeType": "default"}
+	case *CreateTable:
+		if node == nil {
+			return nil
+		}
+		cols := make([]interface{}, 0, len(node.ColumnsDef))
+		for _, col := range node.ColumnsDef {
+			cols = append(cols, e.node(col))
+		}
+		constraints := make([]interface{}, 0, len(node.Constraints))
+		for _, constraint := range node.Constraints {
+			constraints = append(constraints, e.node(constraint))
+		}
+		return jsonObject{
+			"nodeType":    "createTable",
+			"table":       e.node(node.Table),
+			"columns":     cols,
+			"constraints": constraints,
+			"strict":      node.StrictMode,
+		}
+	case *CreateView:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType": "createView",
+			"view":     e.node(node.View),
+			"columns":  e.node(node.Columns),
+			"select":   e.node(node.Select),
+		}
+	case *ColumnDef:
+		if node == nil {
+			return nil
+		}
+		constraints := make([]interface{}, 0, len(node.Constraints))
+		for _, constraint := range node.Constraints {
+			constraints = append(constraints, e.node(constraint))
+		}
+		return jsonObject{"nodeType": "columnDef", "column": e.node(node.Column), "type": node.Type, "constraints": constraints}
+	case *ColumnConstraintPrimaryKey:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType":      "primaryKeyColumnConstraint",
+			"name":          e.identifier(node.Name),
+			"order":         node.Order,
+			"autoIncrement": node.AutoIncrement,
+		}
+	case *ColumnConstraintNotNull:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "notNullColumnConstraint", "name": e.identifier(node.Name)}
+	case *ColumnConstraintUnique:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "uniqueColumnConstraint", "name": e.identifier(node.Name)}
+	case *ColumnConstraintCheck:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "checkColumnConstraint", "name": e.identifier(node.Name), "expr": e.node(node.Expr)}
+	case *ColumnConstraintDefault:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType":    "defaultColumnConstraint",
+			"name":        e.identifier(node.Name),
+			"expr":        e.node(node.Expr),
+			"parenthesis": node.Parenthesis,
+		}
+	case *ColumnConstraintGenerated:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{
+			"nodeType":        "generatedColumnConstraint",
+			"name":            e.identifier(node.Name),
+			"expr":            e.node(node.Expr),
+			"generatedAlways": node.GeneratedAlways,
+			"stored":          node.IsStored,
+		}
+	case *TableConstraintPrimaryKey:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "primaryKeyTableConstraint", "name": e.identifier(node.Name), "columns": e.node(node.Columns)}
+	case *TableConstraintUnique:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "uniqueTableConstraint", "name": e.identifier(node.Name), "columns": e.node(node.Columns)}
+	case *TableConstraintCheck:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "checkTableConstraint", "name": e.identifier(node.Name), "expr": e.node(node.Expr)}
+	case *Insert:
+		if node == nil {
+			return nil
+		}
+		rows := make([]interface{}, 0, len(node.Rows))
+		for _, row := range node.Rows {
+			rows = append(rows, e.exprs(row))
+		}
+		return jsonObject{
+			"nodeType":      "insert",
+			"table":         e.node(node.Table),
+			"columns":       e.node(node.Columns),
+			"rows":          rows,
+			"defaultValues": node.DefaultValues,
+			"select":        e.node(node.Select),
+			"upsert":        e.node(node.Upsert),
+		}
+	case Upsert:
+		clauses := make([]interface{}, 0, len(node))
+		for _, clause := range node {
+			clauses = append(clauses, e.node(clause))
+		}
+		return clauses
+	case *OnConflictClause:
+		if node == nil {
+			return nil
+		}
+		var target, doUpdate interface{}
+		if node.Target != nil {
+			target = jsonObject{"nodeType": "conflictTarget", "columns": e.node(node.Target.Columns), "where": e.node(node.Target.Where)}
+		}
+		if node.DoUpdate != nil {
+			doUpdate = jsonObject{"nodeType": "conflictUpdate", "exprs": e.node(node.DoUpdate.Exprs), "where": e.node(node.DoUpdate.Where)}
+		}
+		return jsonObject{"nodeType": "onConflictClause", "target": target, "doUpdate": doUpdate}
+	case *Delete:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "delete", "table": e.node(node.Table), "where": e.node(node.Where)}
+	case *Update:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "update", "table": e.node(node.Table), "exprs": e.node(node.Exprs), "where": e.node(node.Where)}
+	case UpdateExprs:
+		exprs := make([]interface{}, 0, len(node))
+		for _, expr := range node {
+			exprs = append(exprs, jsonObject{"nodeType": "updateExpr", "column": e.node(expr.Column), "expr": e.node(expr.Expr)})
+		}
+		return exprs
+	case *Grant:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "grant", "privileges": e.node(node.Privileges), "table": e.node(node.Table), "roles": node.Roles}
+	case *Revoke:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "revoke", "privileges": e.node(node.Privileges), "table": e.node(node.Table), "roles": node.Roles}
+	case Privileges:
+		privileges := make([]string, 0, len(node))
+		for priv := range node {
+			privileges = append(privileges, priv)
+		}
+		sort.Strings(privileges)
+		return privileges
+	case *AlterTable:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "alterTable", "table": e.node(node.Table), "clause": e.node(node.AlterTableClause)}
+	case *AlterTableRename:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "alterTableRename", "oldColumn": e.node(node.OldColumn), "newColumn": e.node(node.NewColumn)}
+	case *AlterTableDrop:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "alterTableDrop", "column": e.node(node.Column)}
+	case *AlterTableAdd:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "alterTableAdd", "columnDef": e.node(node.ColumnDef)}
+	case *Vacuum:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "vacuum", "schema": e.identifier(node.Schema)}
+	case *Analyze:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "analyze", "table": e.node(node.Table)}
+	case *Reindex:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "reindex", "table": e.node(node.Table)}
+	case *Pragma:
+		if node == nil {
+			return nil
+		}
+		return jsonObject{"nodeType": "pragma", "name": node.Name.String(), "value": e.node(node.Value), "arg": e.node(node.Arg)}
+	}
+
+	e.err = fmt.Errorf("marshal ast: unsupported node type %T", node)
+	return nil
+}
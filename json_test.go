@@ -0,0 +1,67 @@
+package sqlparser
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestMarshalAST(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name   string
+		stmt   string
+		golden string
+	}
+
+	tests := []testCase{
+		{
+			name:   "select",
+			stmt:   "select distinct t.a, count(*) as n from t join t2 on t.a = t2.a where t.b > 1 and t.c like 'x%' group by t.a having n >= 2 order by n desc nulls last limit 10 offset 5;", // nolint
+			golden: "select.golden.json",
+		},
+		{
+			name:   "insert upsert",
+			stmt:   "insert into t (a, b) values (1, 'a') on conflict (a) do update set b = excluded.b where excluded.b != '';",
+			golden: "insert.golden.json",
+		},
+		{
+			name:   "create view",
+			stmt:   "create view v (x, y) as select a, count(*) from t group by a;",
+			golden: "create_view.golden.json",
+		},
+		{
+			name:   "window",
+			stmt:   "select a, sum(b) over (partition by a order by b rows between 1 preceding and current row exclude ties) from t;",
+			golden: "window.golden.json",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				b, err := MarshalAST(ast)
+				require.NoError(t, err)
+
+				path := filepath.Join("testdata", tc.golden)
+				if *updateGolden {
+					require.NoError(t, os.WriteFile(path, append(b, '\n'), 0o600))
+				}
+
+				expected, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(b))
+			}
+		}(tc))
+	}
+}
@@ -2,6 +2,9 @@ package sqlparser
 
 import (
 	"bytes"
+	"context"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -98,9 +101,40 @@ var keywords = map[string]int{
 	"DROP":       DROP,
 }
 
+// statementKeywords are the keywords that start the admin statements. They are not reserved words in SQLite,
+// so they're only recognized at the start of a statement, and can still be used as identifiers anywhere else,
+// e.g. select analyze from t.
+var statementKeywords = map[string]int{
+	"VACUUM":  VACUUM,
+	"ANALYZE": ANALYZE,
+	"REINDEX": REINDEX,
+	"PRAGMA":  PRAGMA,
+}
+
+// windowKeywords are the keywords of a window definition. They are not keywords anywhere else,
+// so they're only recognized inside the parens of an OVER clause.
+var windowKeywords = map[string]int{
+	"PARTITION": PARTITION,
+	"ROWS":      ROWS,
+	"RANGE":     RANGE,
+	"GROUPS":    GROUPS,
+	"UNBOUNDED": UNBOUNDED,
+	"PRECEDING": PRECEDING,
+	"FOLLOWING": FOLLOWING,
+	"CURRENT":   CURRENT,
+	"ROW":       ROW,
+	"EXCLUDE":   EXCLUDE,
+	"NO":        NO,
+	"OTHERS":    OTHERS,
+	"TIES":      TIES,
+}
+
 // EOF is the end of input.
 const EOF = 0
 
+// ctxCheckInterval is the number of tokens between context checks.
+const ctxCheckInterval = 1024
+
 // Lexer is responsible for token generation.
 type Lexer struct {
 	input        []byte
@@ -121,14 +155,43 @@ type Lexer struct {
 	// If BETWEEN was seen, we emit a different token for AND.
 	hasSeenBetween bool
 
+	// This is the depth of parens inside the window definition of an OVER clause, or zero outside of it.
+	// Window keywords are only recognized at depth one.
+	windowDepth int
+
+	// This tracks, for each open paren, if it's the paren of a function call's arguments or FILTER clause.
+	// callParenClosed is set when the last ')' closed one of them, which is where an OVER clause may start.
+	callParens      []bool
+	callParenClosed bool
+
 	// This is used to make the NOT keyword unambigous.
 	// When the NOT token is seen right after the IS token, we emit the ISNOT token (instead of of the NOT).
 	lastToken int
 
 	ast *AST
 
+	opts ParseOptions
+
+	// ctx is checked every ctxCheckInterval tokens when not nil.
+	// If it's done, the lexer emits an ERROR token to abort parsing.
+	ctx    context.Context
+	ctxErr error
+	tokens int
+
+	// unsupportedErr is set when the lexer finds a statement that is recognized but not supported,
+	// e.g. CREATE TRIGGER. The lexer emits an ERROR token to abort parsing.
+	unsupportedErr error
+
 	// This is used to check if CREATE stmt has more than one primary key
 	createStmtHasPrimaryKey bool
+
+	// tokenStart is the position of the last token returned by Lex.
+	tokenStart int
+
+	// sourceSpans tracks the byte range of each statement when the Lossless option is set.
+	// The last span is still open while inStatement is true.
+	sourceSpans []sourceSpan
+	inStatement bool
 }
 
 // AddError keeps track of errors per statement for syntatically valid statements.
@@ -136,6 +199,349 @@ func (l *Lexer) AddError(err error) {
 	l.errors[l.statementIdx] = multierror.Append(l.errors[l.statementIdx], err)
 }
 
+// validateLikePattern checks that a LIKE or GLOB string literal pattern is not longer than allowed.
+func (l *Lexer) validateLikePattern(operator string, pattern Expr) {
+	maxAllowed := l.opts.MaxLikePatternLength
+	if maxAllowed == 0 {
+		maxAllowed = MaxLikePatternLength
+	}
+
+	if length, ok := likePatternLength(operator, pattern); ok && length > maxAllowed {
+		l.AddError(&ErrLikePatternTooLong{Length: length, MaxAllowed: maxAllowed})
+	}
+}
+
+// validateIdentifiers checks that the parsed statements don't reference more distinct identifiers than allowed
+// by the MaxIdentifiers option. It runs after parsing, because the statements are only known then.
+func (l *Lexer) validateIdentifiers() {
+	if l.opts.MaxIdentifiers <= 0 {
+		return
+	}
+
+	for i, stmt := range l.ast.Statements {
+		if count := countIdentifiers(stmt); count > l.opts.MaxIdentifiers {
+			l.errors[i] = multierror.Append(l.errors[i], &ErrTooManyIdentifiers{
+				IdentifiersCount: count,
+				MaxAllowed:       l.opts.MaxIdentifiers,
+			})
+		}
+	}
+}
+
+// countIdentifiers returns the number of distinct table names plus the number of distinct column names in the node.
+// Aliases are not counted.
+func countIdentifiers(node Node) int {
+	tables, columns := map[string]struct{}{}, map[string]struct{}{}
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *Table:
+			if node != nil {
+				tables[normalizeIdentifier(node.Name)] = struct{}{}
+			}
+		case *Column:
+			if node != nil {
+				columns[normalizeIdentifier(node.Name)] = struct{}{}
+			}
+		}
+		return false, nil
+	}, node)
+
+	return len(tables) + len(columns)
+}
+
+// validateLimitOnSelect checks that a top-level read statement has a LIMIT clause, if required by the
+// RequireLimitOnSelect option. The LIMIT of a compound select is the one of its last select.
+// A VALUES statement doesn't need one, because it returns a fixed number of rows.
+func (l *Lexer) validateLimitOnSelect(stmt ReadStatement) {
+	if !l.opts.RequireLimitOnSelect {
+		return
+	}
+
+	if compound, ok := stmt.(*CompoundSelect); ok {
+		switch right := compound.Right.(type) {
+		case *Values:
+			l.AddError(&ErrMissingLimitAfterValues{})
+		case *Select:
+			if right.Limit == nil {
+				l.AddError(&ErrMissingLimit{})
+			}
+		}
+		return
+	}
+	if sel, ok := stmt.(*Select); ok && sel.Limit == nil {
+		l.AddError(&ErrMissingLimit{})
+	}
+}
+
+// validateWhereOnDestructive checks that an UPDATE or DELETE has a WHERE clause,
+// if required by the RequireWhereOnDestructive option.
+func (l *Lexer) validateWhereOnDestructive(statementKind string, where *Where) {
+	if l.opts.RequireWhereOnDestructive && where == nil {
+		l.AddError(&ErrMissingWhere{StatementKind: statementKind})
+	}
+}
+
+// validateJoins checks that a read statement doesn't have more joins than allowed by the MaxJoins option.
+// Joins in subqueries are also counted.
+func (l *Lexer) validateJoins(stmt ReadStatement) {
+	if l.opts.MaxJoins <= 0 {
+		return
+	}
+
+	joins := 0
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if _, ok := node.(*JoinTableExpr); ok {
+			joins++
+		}
+		return false, nil
+	}, stmt)
+
+	if joins > l.opts.MaxJoins {
+		l.AddError(&ErrTooManyJoins{JoinsCount: joins, MaxAllowed: l.opts.MaxJoins})
+	}
+}
+
+// validateBlobLiteral checks that the content of a BLOB literal is an even number of hex digits, as SQLite requires.
+func (l *Lexer) validateBlobLiteral(blob []byte) {
+	if len(blob)%2 == 0 {
+		valid := true
+		for _, ch := range blob {
+			valid = valid && isHex(ch)
+		}
+		if valid {
+			return
+		}
+	}
+	l.AddError(&ErrInvalidBlobLiteral{Literal: string(blob)})
+}
+
+// validateEscape checks that the ESCAPE expression of a LIKE is a single character string literal, as SQLite requires.
+func (l *Lexer) validateEscape(escape Expr) {
+	if value, ok := escape.(*Value); ok && value.Type == StrValue {
+		if utf8.RuneCount(bytes.ReplaceAll(value.Value, []byte("''"), []byte("'"))) == 1 {
+			return
+		}
+	}
+	l.AddError(&ErrInvalidEscape{Expr: escape.String()})
+}
+
+// validateOrderByOrdinals checks that integer ordering terms refer to an existing result column.
+func (l *Lexer) validateOrderByOrdinals(columns SelectColumnList, orderBy OrderBy) {
+	for _, term := range orderBy {
+		if ordinal, ok := ordinalOutOfRange(columns, term.Expr); ok {
+			l.AddError(&ErrOrderByOrdinalOutOfRange{Ordinal: ordinal, ColumnsCount: len(columns)})
+		}
+	}
+}
+
+// validateGroupByOrdinals checks that integer grouping terms refer to an existing result column.
+func (l *Lexer) validateGroupByOrdinals(columns SelectColumnList, groupBy Exprs) {
+	for _, expr := range groupBy {
+		if ordinal, ok := ordinalOutOfRange(columns, expr); ok {
+			l.AddError(&ErrGroupByOrdinalOutOfRange{Ordinal: ordinal, ColumnsCount: len(columns)})
+		}
+	}
+}
+
+// ordinalOutOfRange returns the ordinal and true if expr is an integer literal that doesn't refer to a result column.
+// The number of result columns is unknown when a star is selected, so that case is not checked.
+func ordinalOutOfRange(columns SelectColumnList, expr Expr) (string, bool) {
+	value, ok := expr.(*Value)
+	if !ok || value.Type != IntValue {
+		return "", false
+	}
+
+	for _, column := range columns {
+		if _, ok := column.(*StarSelectColumn); ok {
+			return "", false
+		}
+	}
+
+	ordinal, err := strconv.ParseInt(string(value.Value), 10, 64)
+	if err != nil || ordinal < 1 || ordinal > int64(len(columns)) {
+		return string(value.Value), true
+	}
+	return "", false
+}
+
+// validateNoCustomFunctions checks that a table definition doesn't call custom functions,
+// because their values would depend on the transaction that created or altered the table.
+func (l *Lexer) validateNoCustomFunctions(node Node) {
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if fn, ok := node.(*CustomFuncExpr); ok {
+			l.AddError(&ErrCustomFuncInDDL{FunctionName: string(fn.Name)})
+		}
+		return false, nil
+	}, node)
+}
+
+// validateUniqueColumnNames checks that a table definition doesn't have two columns with the same name.
+// Names are compared case-insensitively and without quotes, the same way SQLite does.
+func (l *Lexer) validateUniqueColumnNames(columns []*ColumnDef) {
+	names := make(map[string]struct{}, len(columns))
+	for _, columnDef := range columns {
+		name := normalizeIdentifier(columnDef.Column.Name)
+		if _, ok := names[name]; ok {
+			l.AddError(&ErrDuplicateColumnName{Name: columnDef.Column.Name.String()})
+			continue
+		}
+		names[name] = struct{}{}
+	}
+}
+
+// validateUniqueConstraintNames checks that a table definition doesn't have two named constraints
+// with the same name, considering both column and table constraints.
+func (l *Lexer) validateUniqueConstraintNames(columns []*ColumnDef, constraints []TableConstraint) {
+	var constraintNames []Identifier
+	for _, columnDef := range columns {
+		for _, constraint := range columnDef.Constraints {
+			switch constraint := constraint.(type) {
+			case *ColumnConstraintPrimaryKey:
+				constraintNames = append(constraintNames, constraint.Name)
+			case *ColumnConstraintNotNull:
+				constraintNames = append(constraintNames, constraint.Name)
+			case *ColumnConstraintUnique:
+				constraintNames = append(constraintNames, constraint.Name)
+			case *ColumnConstraintCheck:
+				constraintNames = append(constraintNames, constraint.Name)
+			case *ColumnConstraintDefault:
+				constraintNames = append(constraintNames, constraint.Name)
+			case *ColumnConstraintGenerated:
+				constraintNames = append(constraintNames, constraint.Name)
+			}
+		}
+	}
+	for _, constraint := range constraints {
+		switch constraint := constraint.(type) {
+		case *TableConstraintPrimaryKey:
+			constraintNames = append(constraintNames, constraint.Name)
+		case *TableConstraintUnique:
+			constraintNames = append(constraintNames, constraint.Name)
+		case *TableConstraintCheck:
+			constraintNames = append(constraintNames, constraint.Name)
+		}
+	}
+
+	names := make(map[string]struct{}, len(constraintNames))
+	for _, constraintName := range constraintNames {
+		if constraintName.IsEmpty() {
+			continue
+		}
+		name := normalizeIdentifier(constraintName)
+		if _, ok := names[name]; ok {
+			l.AddError(&ErrDuplicateConstraintName{Name: constraintName.String()})
+			continue
+		}
+		names[name] = struct{}{}
+	}
+}
+
+// validateLimitExpr checks that a LIMIT or OFFSET expression is an integer literal or a param,
+// because non-constant limits could make the result differ between replicas.
+func (l *Lexer) validateLimitExpr(expr Expr) {
+	switch expr := expr.(type) {
+	case *Param:
+		return
+	case *Value:
+		if expr.Type == IntValue || expr.Type == HexNumValue {
+			return
+		}
+	}
+	l.AddError(&ErrInvalidLimitExpr{Expr: expr.String()})
+}
+
+// trackSource extends the source span of the current statement with the token that starts at start.
+// Statements are delimited by semicolons, so a semicolon closes the current span.
+func (l *Lexer) trackSource(token int, start int) {
+	switch token {
+	case EOF, ERROR, ';':
+		l.inStatement = false
+	default:
+		if !l.inStatement {
+			l.sourceSpans = append(l.sourceSpans, sourceSpan{start: start})
+			l.inStatement = true
+		}
+		end := l.position
+		if end > len(l.input) {
+			end = len(l.input)
+		}
+		l.sourceSpans[len(l.sourceSpans)-1].end = end
+	}
+}
+
+// Token is a lexical token of a statement.
+type Token struct {
+	// Kind is the name of the token, e.g. SELECT, IDENTIFIER, STRING, or the character of a punctuation token, e.g. (.
+	Kind string
+
+	// Value is the text of the token as it appears in the statement.
+	Value string
+
+	// Position is the byte offset of the token in the statement.
+	Position int
+}
+
+// tokenKindAliases maps the tokens that the lexer emits to disambiguate the grammar to their keyword.
+var tokenKindAliases = map[string]string{
+	"ANDOP": "AND",
+	"ISNOT": "NOT",
+}
+
+// Tokenize returns the tokens of the input, without parsing it.
+// It returns a syntax error if the input has an invalid token, e.g. an unterminated string,
+// and ErrTriggersNotSupported if it has a CREATE TRIGGER statement.
+func Tokenize(sql string) ([]Token, error) {
+	lexer := &Lexer{}
+	lexer.input = []byte(sql)
+	lexer.readByte()
+
+	tokens := []Token{}
+	var lval yySymType
+	for {
+		char, token := yylex1(lexer, &lval)
+		if char == EOF {
+			return tokens, nil
+		}
+		if char == ERROR {
+			if lexer.unsupportedErr != nil {
+				return nil, lexer.unsupportedErr
+			}
+			lexer.Error("syntax error")
+			return nil, lexer.syntaxError
+		}
+
+		kind := yyTokname(token)
+		if alias, ok := tokenKindAliases[kind]; ok {
+			kind = alias
+		}
+		if len(kind) == 3 && kind[0] == '\'' && kind[2] == '\'' {
+			kind = kind[1:2]
+		}
+
+		end := lexer.position
+		if end > len(sql) {
+			end = len(sql)
+		}
+		tokens = append(tokens, Token{Kind: kind, Value: sql[lexer.tokenStart:end], Position: lexer.tokenStart})
+	}
+}
+
+// isSingleToken checks if s is a single valid token.
+func isSingleToken(s string) bool {
+	lexer := &Lexer{}
+	lexer.input = []byte(s)
+	lexer.readByte()
+
+	var lval yySymType
+	if token := lexer.Lex(&lval); token == EOF || token == ERROR {
+		return false
+	}
+	return lexer.Lex(&lval) == EOF
+}
+
 // Error is used for syntatically not valid statements.
 func (l *Lexer) Error(e string) {
 	l.syntaxError = &ErrSyntaxError{YaccError: e, Position: l.position, Literal: string(l.literal)}
@@ -143,11 +549,27 @@ func (l *Lexer) Error(e string) {
 
 // Lex returns a token to be used in the parser.
 func (l *Lexer) Lex(lval *yySymType) (token int) {
+	start := l.position
 	defer func() {
 		l.lastToken = token
+		if l.opts.Lossless {
+			l.trackSource(token, start)
+		}
 	}()
 
+	if l.ctx != nil {
+		l.tokens++
+		if l.tokens%ctxCheckInterval == 0 {
+			if err := l.ctx.Err(); err != nil {
+				l.ctxErr = err
+				return ERROR
+			}
+		}
+	}
+
 	l.skipWhitespace()
+	start = l.position
+	l.tokenStart = start
 
 	if l.ch == 0 {
 		return EOF
@@ -197,6 +619,37 @@ func (l *Lexer) Lex(lval *yySymType) (token int) {
 		}
 
 		l.literal = literal
+
+		// OVER is not a keyword, so it's only recognized right after the parens of a function call, or its FILTER
+		// clause, when it's followed by a window definition. Otherwise, it's an identifier, e.g. count(*) over.
+		if l.lastToken == ')' && l.callParenClosed && string(literalUpper) == "OVER" && l.peekNonWhitespace() == '(' {
+			lval.bytes = literal
+			return OVER
+		}
+		if l.windowDepth == 1 {
+			if token, ok := windowKeywords[string(literalUpper)]; ok {
+				lval.bytes = literal
+				return token
+			}
+		}
+
+		if l.lastToken == EOF || l.lastToken == ';' {
+			if token, ok := statementKeywords[string(literalUpper)]; ok {
+				lval.bytes = literal
+				return token
+			}
+		}
+
+		// TRIGGER and VIEW are not keywords, so they're only recognized right after CREATE
+		if l.lastToken == CREATE && string(literalUpper) == "TRIGGER" {
+			l.unsupportedErr = &ErrTriggersNotSupported{}
+			return ERROR
+		}
+		if l.lastToken == CREATE && string(literalUpper) == "VIEW" {
+			lval.bytes = literal
+			return VIEW
+		}
+
 		lval.bytes = literal
 		return IDENTIFIER
 	}
@@ -294,6 +747,13 @@ func (l *Lexer) Lex(lval *yySymType) (token int) {
 		return int('|')
 	}
 
+	if l.ch == '"' && l.opts.DoubleQuoteIsString {
+		token, literal := l.readDoubleQuotedString()
+		l.literal = literal
+		lval.bytes = literal
+		return token
+	}
+
 	switch ch := l.ch; ch {
 	case '"', '`', '[':
 		closingChar := map[byte]byte{
@@ -315,6 +775,23 @@ func (l *Lexer) Lex(lval *yySymType) (token int) {
 		return IDENTIFIER
 	}
 
+	switch ch := l.ch; ch {
+	case '(':
+		if l.lastToken == OVER || l.windowDepth > 0 {
+			l.windowDepth++
+		}
+		l.callParens = append(l.callParens, l.lastToken == IDENTIFIER || l.lastToken == FILTER)
+	case ')':
+		if l.windowDepth > 0 {
+			l.windowDepth--
+		}
+		l.callParenClosed = false
+		if n := len(l.callParens); n > 0 {
+			l.callParenClosed = l.callParens[n-1]
+			l.callParens = l.callParens[:n-1]
+		}
+	}
+
 	switch ch := l.ch; ch {
 	case '(', ')', ',', '&', '+', '*', '/', '%', '~', ';', '?':
 		l.literal = []byte{ch}
@@ -383,11 +860,13 @@ func (l *Lexer) readExpoent(buf *bytes.Buffer) {
 	l.readDigits(10, buf)
 }
 
+// readBlob reads the content of a x'...' literal up to its closing quote.
+// The content is validated by the parser, so invalid literals are reported with a meaningful error.
 func (l *Lexer) readBlob() (int, []byte) {
 	var buf bytes.Buffer
 	l.readByte()
 	l.readByte()
-	for isHex(l.ch) {
+	for l.ch != '\'' && l.ch != EOF {
 		buf.WriteByte(l.ch)
 		l.readByte()
 	}
@@ -444,6 +923,36 @@ func (l *Lexer) readString() (int, []byte) {
 	return STRING, literal.Bytes()
 }
 
+// readDoubleQuotedString reads a double-quoted string and returns it as a single-quoted string literal.
+func (l *Lexer) readDoubleQuotedString() (int, []byte) {
+	var literal bytes.Buffer
+	literal.WriteByte('\'')
+	l.readByte()
+
+	for {
+		if l.ch == EOF {
+			return ERROR, literal.Bytes()
+		}
+		ch := l.ch
+		l.readByte()
+
+		if ch == '"' {
+			if l.ch != '"' {
+				break
+			}
+			l.readByte()
+		}
+
+		if ch == '\'' {
+			literal.WriteByte(ch)
+		}
+		literal.WriteByte(ch)
+	}
+	literal.WriteByte('\'')
+
+	return STRING, literal.Bytes()
+}
+
 func (l *Lexer) readByte() {
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
@@ -455,6 +964,17 @@ func (l *Lexer) readByte() {
 	l.readPosition++
 }
 
+// peekNonWhitespace returns the next byte that is not whitespace or part of a comment, without consuming it.
+func (l *Lexer) peekNonWhitespace() byte {
+	ch, position, readPosition := l.ch, l.position, l.readPosition
+	defer func() {
+		l.ch, l.position, l.readPosition = ch, position, readPosition
+	}()
+
+	l.skipWhitespace()
+	return l.ch
+}
+
 func (l *Lexer) peekByte() byte {
 	if l.readPosition >= len(l.input) {
 		return 0
@@ -539,9 +1059,30 @@ func (l *Lexer) readComparison() (int, []byte) {
 	return ERROR, []byte{l.ch}
 }
 
+// skipWhitespace skips whitespace and comments. As in SQLite, a -- comment ends at a new line
+// and an unterminated /* comment ends at the end of the input.
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		l.readByte()
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
+			l.readByte()
+		case l.ch == '-' && l.peekByte() == '-':
+			for l.ch != 0 && l.ch != '\n' {
+				l.readByte()
+			}
+		case l.ch == '/' && l.peekByte() == '*':
+			l.readByte()
+			l.readByte()
+			for l.ch != 0 && !(l.ch == '*' && l.peekByte() == '/') {
+				l.readByte()
+			}
+			if l.ch != 0 {
+				l.readByte()
+				l.readByte()
+			}
+		default:
+			return
+		}
 	}
 }
 
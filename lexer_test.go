@@ -2,6 +2,8 @@ package sqlparser
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestLexer(t *testing.T) {
@@ -65,3 +67,96 @@ func TestLexer(t *testing.T) {
 		token, i = lexer.Lex(lval), i+1
 	}
 }
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name   string
+		sql    string
+		tokens []Token
+	}
+
+	tests := []testCase{
+		{
+			name: "select",
+			sql:  "SELECT a FROM t",
+			tokens: []Token{
+				{Kind: "SELECT", Value: "SELECT", Position: 0},
+				{Kind: "IDENTIFIER", Value: "a", Position: 7},
+				{Kind: "FROM", Value: "FROM", Position: 9},
+				{Kind: "IDENTIFIER", Value: "t", Position: 14},
+			},
+		},
+		{
+			name: "literals and punctuation",
+			sql:  "insert into t values ('it''s', 1.5, x'0A', ?);",
+			tokens: []Token{
+				{Kind: "INSERT", Value: "insert", Position: 0},
+				{Kind: "INTO", Value: "into", Position: 7},
+				{Kind: "IDENTIFIER", Value: "t", Position: 12},
+				{Kind: "VALUES", Value: "values", Position: 14},
+				{Kind: "(", Value: "(", Position: 21},
+				{Kind: "STRING", Value: "'it''s'", Position: 22},
+				{Kind: ",", Value: ",", Position: 29},
+				{Kind: "FLOAT", Value: "1.5", Position: 31},
+				{Kind: ",", Value: ",", Position: 34},
+				{Kind: "BLOBVAL", Value: "x'0A'", Position: 36},
+				{Kind: ",", Value: ",", Position: 41},
+				{Kind: "?", Value: "?", Position: 43},
+				{Kind: ")", Value: ")", Position: 44},
+				{Kind: ";", Value: ";", Position: 45},
+			},
+		},
+		{
+			name: "operators",
+			sql:  "a>=1 AND b IS NOT \"c\" || d->>'$'",
+			tokens: []Token{
+				{Kind: "IDENTIFIER", Value: "a", Position: 0},
+				{Kind: "GE", Value: ">=", Position: 1},
+				{Kind: "INTEGRAL", Value: "1", Position: 3},
+				{Kind: "AND", Value: "AND", Position: 5},
+				{Kind: "IDENTIFIER", Value: "b", Position: 9},
+				{Kind: "IS", Value: "IS", Position: 11},
+				{Kind: "NOT", Value: "NOT", Position: 14},
+				{Kind: "IDENTIFIER", Value: "\"c\"", Position: 18},
+				{Kind: "CONCAT", Value: "||", Position: 22},
+				{Kind: "IDENTIFIER", Value: "d", Position: 25},
+				{Kind: "JSON_UNQUOTE_EXTRACT_OP", Value: "->>", Position: 26},
+				{Kind: "STRING", Value: "'$'", Position: 29},
+			},
+		},
+		{
+			name:   "empty",
+			sql:    "  ",
+			tokens: []Token{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				tokens, err := Tokenize(tc.sql)
+				require.NoError(t, err)
+				require.Equal(t, tc.tokens, tokens)
+			}
+		}(tc))
+	}
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Tokenize("select 'unterminated from t")
+		require.ErrorAs(t, err, new(*ErrSyntaxError))
+	})
+
+	t.Run("trigger", func(t *testing.T) {
+		t.Parallel()
+
+		tokens, err := Tokenize("create trigger tr after insert on t begin select 1; end")
+		require.Nil(t, tokens)
+		require.Equal(t, &ErrTriggersNotSupported{}, err)
+	})
+}
@@ -1,6 +1,9 @@
 package sqlparser
 
-import "sync"
+import (
+	"context"
+	"sync"
+)
 
 // parserPool is a pool for parser objects.
 var parserPool = sync.Pool{
@@ -20,25 +23,153 @@ func yyParsePooled(yylex yyLexer) int {
 	return parser.Parse(yylex)
 }
 
+// ParseOptions configures the parser.
+// The zero value enforces the Tableland rules.
+type ParseOptions struct {
+	// AllowMaintenanceStatements allows the VACUUM, ANALYZE and REINDEX statements and the PRAGMA statements
+	// that are not read-only, which are only meant for admin tooling.
+	// VACUUM, ANALYZE, REINDEX and PRAGMA are only keywords at the start of a statement, so they can still
+	// be used as identifiers, as they could before these statements were supported.
+	AllowMaintenanceStatements bool
+
+	// SingleStatement rejects inputs with more than one statement.
+	SingleStatement bool
+
+	// MaxStatements is the limit for the number of statements in the input.
+	// If zero, the number of statements is not limited.
+	MaxStatements int
+
+	// DoubleQuoteIsString makes double-quoted tokens string literals instead of identifiers,
+	// the same way MySQL does. Identifiers can still be quoted with backticks or brackets.
+	DoubleQuoteIsString bool
+
+	// DisableAutoOrderByRowid disables adding an ORDER BY rowid term to the SELECT of an INSERT ... SELECT,
+	// which is added by default so the inserted rows order is deterministic.
+	DisableAutoOrderByRowid bool
+
+	// MaxLikePatternLength is the limit for the length of a LIKE or GLOB string literal pattern.
+	// If zero, MaxLikePatternLength is used.
+	MaxLikePatternLength int
+
+	// MaxJoins is the limit for the number of joins in a read statement, including the joins in its subqueries.
+	// If zero, the number of joins is not limited.
+	MaxJoins int
+
+	// MaxIdentifiers is the limit for the number of distinct table and column names referenced by a statement,
+	// to bound the cost of analyzing it. If zero, the number of identifiers is not limited.
+	MaxIdentifiers int
+
+	// RequireLimitOnSelect rejects read statements without a LIMIT clause, to enforce pagination.
+	// Only the top-level statement needs a LIMIT, subqueries don't.
+	RequireLimitOnSelect bool
+
+	// RequireWhereOnDestructive rejects UPDATE and DELETE statements without a WHERE clause,
+	// which would change every row of the table.
+	RequireWhereOnDestructive bool
+
+	// AllowDefaultKeyword allows the DEFAULT keyword as a value in the rows of an INSERT, e.g. VALUES (1, DEFAULT).
+	// SQLite doesn't support it, so a statement that uses it must be rewritten before it's executed.
+	AllowDefaultKeyword bool
+
+	// Lossless records the original text of the input and of each statement, so it can be reproduced exactly,
+	// whitespace, comments and casing included, with AST.Source, AST.StatementSource and AST.NodeSource.
+	// It's statement-level only: there's no source for the nodes inside a statement, like its clauses
+	// or expressions, and no trivia is attached to them.
+	Lossless bool
+}
+
 // Parse parses an statement into an AST.
 func Parse(statement string) (*AST, error) {
+	return ParseWithOptions(statement, ParseOptions{})
+}
+
+// ParseWithOptions parses an statement into an AST using the provided options.
+func ParseWithOptions(statement string, opts ParseOptions) (*AST, error) {
+	return parse(context.Background(), statement, opts)
+}
+
+// ParseContext parses an statement into an AST. Parsing is aborted with ctx.Err() if ctx is done before it finishes.
+func ParseContext(ctx context.Context, statement string) (*AST, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return parse(ctx, statement, ParseOptions{})
+}
+
+// SplitStatements splits an input with multiple statements into the text of each statement, without parsing them.
+// Statements are split by the semicolons found by the lexer, so semicolons inside string literals and
+// quoted identifiers are handled. The statements keep their original text, without the surrounding whitespace.
+// It returns a syntax error if the input can't be tokenized, e.g. when it has an unterminated string,
+// and ErrTriggersNotSupported if it has a CREATE TRIGGER statement.
+func SplitStatements(sql string) ([]string, error) {
+	lexer := &Lexer{opts: ParseOptions{Lossless: true}}
+	lexer.input = []byte(sql)
+	lexer.readByte()
+
+	var lval yySymType
+	for {
+		token := lexer.Lex(&lval)
+		if token == EOF {
+			break
+		}
+		if token == ERROR {
+			if lexer.unsupportedErr != nil {
+				return nil, lexer.unsupportedErr
+			}
+			lexer.Error("syntax error")
+			return nil, lexer.syntaxError
+		}
+	}
+
+	statements := make([]string, len(lexer.sourceSpans))
+	for i, span := range lexer.sourceSpans {
+		statements[i] = sql[span.start:span.end]
+	}
+	return statements, nil
+}
+
+func parse(ctx context.Context, statement string, opts ParseOptions) (*AST, error) {
 	// yyErrorVerbose = true
 	// yyDebug = 4
 
 	if len(statement) == 0 {
+		if opts.Lossless {
+			return &AST{sourceSpans: []sourceSpan{}}, nil
+		}
 		return &AST{}, nil
 	}
 
-	lexer := &Lexer{}
+	lexer := &Lexer{opts: opts, ctx: ctx}
 	lexer.errors = make(map[int]error)
 	lexer.input = []byte(statement)
 	lexer.readByte()
 
 	yyParsePooled(lexer)
+	if lexer.ctxErr != nil {
+		return nil, lexer.ctxErr
+	}
+	if lexer.unsupportedErr != nil {
+		return nil, lexer.unsupportedErr
+	}
 	if lexer.syntaxError != nil {
 		return nil, lexer.syntaxError
 	}
 
+	if opts.Lossless {
+		lexer.ast.source = statement
+		lexer.ast.sourceSpans = lexer.sourceSpans
+	}
+
+	if opts.SingleStatement && len(lexer.ast.Statements) > 1 {
+		return nil, &ErrMultipleStatements{StatementsCount: len(lexer.ast.Statements)}
+	}
+
+	if opts.MaxStatements > 0 && len(lexer.ast.Statements) > opts.MaxStatements {
+		return nil, &ErrTooManyStatements{StatementsCount: len(lexer.ast.Statements), MaxAllowed: opts.MaxStatements}
+	}
+
+	lexer.validateIdentifiers()
+
 	if len(lexer.errors) != 0 {
 		lexer.ast.Errors = lexer.errors
 		return lexer.ast, lexer.errors[0]
@@ -0,0 +1,102 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package sqlparser
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchFTS5(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		rows     int
+	}
+
+	tests := []testCase{
+		{
+			name:     "column match",
+			stmt:     "SELECT title FROM docs WHERE body MATCH 'parser'",
+			deparsed: "select title from docs where body match 'parser'",
+			rows:     2,
+		},
+		{
+			name:     "table match",
+			stmt:     "SELECT title FROM docs WHERE docs MATCH 'sqlite AND grammar'",
+			deparsed: "select title from docs where docs match 'sqlite AND grammar'",
+			rows:     1,
+		},
+		{
+			name:     "column filter and phrase",
+			stmt:     "SELECT title FROM docs WHERE docs MATCH 'title : \"go parser\"' ORDER BY rank",
+			deparsed: "select title from docs where docs match 'title : \"go parser\"' order by rank asc",
+			rows:     1,
+		},
+		{
+			name:     "prefix and param",
+			stmt:     "SELECT count(*) FROM docs WHERE body MATCH ? AND title != 'x'",
+			deparsed: "select count(*)from docs where body match ? and title!='x'",
+			rows:     1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec(`
+					CREATE VIRTUAL TABLE docs USING fts5(title, body);
+					INSERT INTO docs VALUES
+						('go parser', 'a parser for the tableland sqlite grammar'),
+						('sqlite', 'an embedded database'),
+						('yacc', 'a parser generator');
+				`)
+				require.NoError(t, err)
+
+				args := []interface{}{}
+				if HasParams(ast) {
+					args = append(args, "pars*")
+				}
+
+				// the original and the deparsed statements have the same results
+				original := queryRows(t, db, tc.stmt, args...)
+				require.Len(t, original, tc.rows)
+				require.Equal(t, original, queryRows(t, db, ast.String(), args...))
+			}
+		}(tc))
+	}
+
+	t.Run("regular table", func(t *testing.T) {
+		t.Parallel()
+
+		// MATCH parses on any table, but SQLite only executes it on full-text tables
+		ast, err := Parse("SELECT a FROM t WHERE a MATCH 'x'")
+		require.NoError(t, err)
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+
+		_, err = db.Exec("CREATE TABLE t (a text); INSERT INTO t VALUES ('x');")
+		require.NoError(t, err)
+		var a string
+		require.Error(t, db.QueryRow(ast.String()).Scan(&a))
+	})
+}
@@ -1,6 +1,7 @@
 package sqlparser
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
@@ -986,6 +988,57 @@ func TestSelectStatement(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "cast-nested-expression",
+			stmt:     "SELECT CAST ((a+b) AS integer) FROM t",
+			deparsed: "select cast((a+b)as integer)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: []SelectColumn{
+							&AliasedSelectColumn{
+								Expr: &ConvertExpr{
+									Expr: &ParenExpr{
+										Expr: &BinaryExpr{
+											Operator: PlusStr,
+											Left:     &Column{Name: "a"},
+											Right:    &Column{Name: "b"},
+										},
+									},
+									Type: IntegerStr,
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "select-without-from",
+			stmt:     "SELECT 1, 'a' WHERE 1 > 0",
+			deparsed: "select 1,'a' where 1>0",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: []SelectColumn{
+							&AliasedSelectColumn{Expr: &Value{Type: IntValue, Value: []byte("1")}},
+							&AliasedSelectColumn{Expr: &Value{Type: StrValue, Value: []byte("a")}},
+						},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: GreaterThanStr,
+								Left:     &Value{Type: IntValue, Value: []byte("1")},
+								Right:    &Value{Type: IntValue, Value: []byte("0")},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:     "collate",
 			stmt:     "SELECT c1 = c2 COLLATE rtrim FROM t",
@@ -1288,6 +1341,52 @@ func TestSelectStatement(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "json-extract-string-path",
+			stmt:     "SELECT c1 -> '$.a' FROM t",
+			deparsed: "select c1->'$.a' from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: []SelectColumn{
+							&AliasedSelectColumn{
+								Expr: &BinaryExpr{
+									Operator: JSONExtractOp,
+									Left:     &Column{Name: "c1"},
+									Right:    &Value{Type: StrValue, Value: []byte("$.a")},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "json-unquote-extract-integer-index",
+			stmt:     "SELECT c1 ->> 0 FROM t",
+			deparsed: "select c1->>0 from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: []SelectColumn{
+							&AliasedSelectColumn{
+								Expr: &BinaryExpr{
+									Operator: JSONUnquoteExtractOp,
+									Left:     &Column{Name: "c1"},
+									Right:    &Value{Type: IntValue, Value: []byte("0")},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:     "bitnot",
 			stmt:     "SELECT ~c FROM t",
@@ -1776,6 +1875,100 @@ func TestSelectStatement(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "orderby-nulls-last-without-direction",
+			stmt:     "SELECT a FROM t ORDER BY a NULLS LAST",
+			deparsed: "select a from t order by a asc nulls last",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &Column{Name: "a"},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+
+						OrderBy: OrderBy{
+							&OrderingTerm{Expr: &Column{Name: "a"}, Direction: AscStr, Nulls: NullsLast},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "orderby-desc-nulls-first",
+			stmt:     "SELECT a FROM t ORDER BY a DESC NULLS FIRST",
+			deparsed: "select a from t order by a desc nulls first",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &Column{Name: "a"},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+
+						OrderBy: OrderBy{
+							&OrderingTerm{Expr: &Column{Name: "a"}, Direction: DescStr, Nulls: NullsFirst},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "orderby-desc-nulls-last",
+			stmt:     "SELECT a FROM t ORDER BY a DESC NULLS LAST, b ASC NULLS FIRST",
+			deparsed: "select a from t order by a desc nulls last,b asc nulls first",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &Column{Name: "a"},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+
+						OrderBy: OrderBy{
+							&OrderingTerm{Expr: &Column{Name: "a"}, Direction: DescStr, Nulls: NullsLast},
+							&OrderingTerm{Expr: &Column{Name: "b"}, Direction: AscStr, Nulls: NullsFirst},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "orderby-without-direction",
+			stmt:     "SELECT a FROM t ORDER BY a, b",
+			deparsed: "select a from t order by a asc,b asc",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &Column{Name: "a"},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+
+						OrderBy: OrderBy{
+							&OrderingTerm{Expr: &Column{Name: "a"}, Direction: AscStr, Nulls: NullsNil},
+							&OrderingTerm{Expr: &Column{Name: "b"}, Direction: AscStr, Nulls: NullsNil},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:     "limit",
 			stmt:     "SELECT * FROM t LIMIT 1",
@@ -1822,7 +2015,7 @@ func TestSelectStatement(t *testing.T) {
 		{
 			name:     "limit-offet-alternative",
 			stmt:     "SELECT * FROM t LIMIT 1, 2",
-			deparsed: "select * from t limit 2 offset 1",
+			deparsed: "select * from t limit 1,2",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&Select{
@@ -1834,8 +2027,9 @@ func TestSelectStatement(t *testing.T) {
 						},
 
 						Limit: &Limit{
-							Limit:  &Value{Type: IntValue, Value: []byte("2")},
-							Offset: &Value{Type: IntValue, Value: []byte("1")},
+							Limit:       &Value{Type: IntValue, Value: []byte("2")},
+							Offset:      &Value{Type: IntValue, Value: []byte("1")},
+							CommaSyntax: true,
 						},
 					},
 				},
@@ -2675,130 +2869,269 @@ func TestSelectStatement(t *testing.T) {
 			},
 		},
 		{
-			name:     "function call",
-			stmt:     "SELECT count(c1) FROM t",
-			deparsed: "select count(c1)from t",
+			name:     "in single value",
+			stmt:     "SELECT a FROM t WHERE a IN (1)",
+			deparsed: "select a from t where a in(1)",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&Select{
 						SelectColumnList: SelectColumnList{
 							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: "count",
-									Args: Exprs{
-										&Column{Name: "c1"},
-									},
-								},
+								Expr: &Column{Name: "a"},
 							},
 						},
 						From: &AliasedTableExpr{
 							Expr: &Table{Name: "t", IsTarget: true},
 						},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: InStr,
+								Left:     &Column{Name: "a"},
+								Right: Exprs{
+									&Value{Type: IntValue, Value: []byte("1")},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 		{
-			name:     "function call filter",
-			stmt:     "SELECT max(a) FILTER(WHERE a > 2) FROM t",
-			deparsed: "select max(a)filter(where a>2)from t",
+			name:     "in parenthesized single value",
+			stmt:     "SELECT a FROM t WHERE a IN ((1))",
+			deparsed: "select a from t where a in((1))",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&Select{
 						SelectColumnList: SelectColumnList{
 							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: "max",
-									Args: Exprs{
-										&Column{Name: "a"},
-									},
-									Filter: &Where{
-										Type: WhereStr,
-										Expr: &CmpExpr{
-											Operator: GreaterThanStr,
-											Left:     &Column{Name: "a"},
-											Right:    &Value{Type: IntValue, Value: []byte("2")},
-										},
-									},
-								},
+								Expr: &Column{Name: "a"},
 							},
 						},
 						From: &AliasedTableExpr{
 							Expr: &Table{Name: "t", IsTarget: true},
 						},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: InStr,
+								Left:     &Column{Name: "a"},
+								Right: Exprs{
+									&ParenExpr{Expr: &Value{Type: IntValue, Value: []byte("1")}},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 		{
-			name:     "function call",
-			stmt:     "SELECT count(c1) FROM t",
-			deparsed: "select count(c1)from t",
+			name:     "in parenthesized subselect",
+			stmt:     "SELECT a FROM t WHERE a IN ((SELECT a FROM t2))",
+			deparsed: "select a from t where a in((select a from t2))",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&Select{
 						SelectColumnList: SelectColumnList{
 							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: "count",
-									Args: Exprs{
-										&Column{Name: "c1"},
-									},
-								},
+								Expr: &Column{Name: "a"},
 							},
 						},
 						From: &AliasedTableExpr{
 							Expr: &Table{Name: "t", IsTarget: true},
 						},
-					},
-				},
-			},
-		},
-		{
-			name:     "function call upper",
-			stmt:     "SELECT COUNT(c1) FROM t",
-			deparsed: "select count(c1)from t",
-			expectedAST: &AST{
-				Statements: []Statement{
-					&Select{
-						SelectColumnList: SelectColumnList{
-							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: "count",
-									Args: Exprs{
-										&Column{Name: "c1"},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: InStr,
+								Left:     &Column{Name: "a"},
+								Right: Exprs{
+									&Subquery{
+										Select: &Select{
+											SelectColumnList: SelectColumnList{
+												&AliasedSelectColumn{
+													Expr: &Column{Name: "a"},
+												},
+											},
+											From: &AliasedTableExpr{
+												Expr: &Table{Name: "t2", IsTarget: true},
+											},
+										},
 									},
 								},
 							},
 						},
-						From: &AliasedTableExpr{
-							Expr: &Table{Name: "t", IsTarget: true},
-						},
 					},
 				},
 			},
 		},
 		{
-			name:     "function call star",
-			stmt:     "SELECT count(*) FROM t",
-			deparsed: "select count(*)from t",
+			name:     "in subselect and value",
+			stmt:     "SELECT a FROM t WHERE a IN ((SELECT a FROM t2), 1)",
+			deparsed: "select a from t where a in((select a from t2),1)",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&Select{
 						SelectColumnList: SelectColumnList{
 							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: "count",
-									Args: nil,
-								},
+								Expr: &Column{Name: "a"},
 							},
 						},
 						From: &AliasedTableExpr{
 							Expr: &Table{Name: "t", IsTarget: true},
 						},
-					},
-				},
-			},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: InStr,
+								Left:     &Column{Name: "a"},
+								Right: Exprs{
+									&Subquery{
+										Select: &Select{
+											SelectColumnList: SelectColumnList{
+												&AliasedSelectColumn{
+													Expr: &Column{Name: "a"},
+												},
+											},
+											From: &AliasedTableExpr{
+												Expr: &Table{Name: "t2", IsTarget: true},
+											},
+										},
+									},
+									&Value{Type: IntValue, Value: []byte("1")},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "function call",
+			stmt:     "SELECT count(c1) FROM t",
+			deparsed: "select count(c1)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: "count",
+									Args: Exprs{
+										&Column{Name: "c1"},
+									},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "function call filter",
+			stmt:     "SELECT max(a) FILTER(WHERE a > 2) FROM t",
+			deparsed: "select max(a)filter(where a>2)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: "max",
+									Args: Exprs{
+										&Column{Name: "a"},
+									},
+									Filter: &Where{
+										Type: WhereStr,
+										Expr: &CmpExpr{
+											Operator: GreaterThanStr,
+											Left:     &Column{Name: "a"},
+											Right:    &Value{Type: IntValue, Value: []byte("2")},
+										},
+									},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "function call",
+			stmt:     "SELECT count(c1) FROM t",
+			deparsed: "select count(c1)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: "count",
+									Args: Exprs{
+										&Column{Name: "c1"},
+									},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "function call upper",
+			stmt:     "SELECT COUNT(c1) FROM t",
+			deparsed: "select count(c1)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: "count",
+									Args: Exprs{
+										&Column{Name: "c1"},
+									},
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "function call star",
+			stmt:     "SELECT count(*) FROM t",
+			deparsed: "select count(*)from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: "count",
+									Args: nil,
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
 		},
 		{
 			name:     "function call star upper",
@@ -2992,18 +3325,7 @@ func TestSelectStatement(t *testing.T) {
 			expectedAST: &AST{
 				Statements: []Statement{
 					&CompoundSelect{
-						Left: &Select{
-							SelectColumnList: SelectColumnList{
-								&AliasedSelectColumn{
-									Expr: &Column{Name: "a"},
-								},
-							},
-							From: &AliasedTableExpr{
-								Expr: &Table{Name: "t", IsTarget: true},
-							},
-						},
-						Type: CompoundUnionStr,
-						Right: &CompoundSelect{
+						Left: &CompoundSelect{
 							Left: &Select{
 								SelectColumnList: SelectColumnList{
 									&AliasedSelectColumn{
@@ -3011,7 +3333,7 @@ func TestSelectStatement(t *testing.T) {
 									},
 								},
 								From: &AliasedTableExpr{
-									Expr: &Table{Name: "t2", IsTarget: true},
+									Expr: &Table{Name: "t", IsTarget: true},
 								},
 							},
 							Type: CompoundUnionStr,
@@ -3022,9 +3344,20 @@ func TestSelectStatement(t *testing.T) {
 									},
 								},
 								From: &AliasedTableExpr{
-									Expr: &Table{Name: "t3", IsTarget: true},
+									Expr: &Table{Name: "t2", IsTarget: true},
+								},
+							},
+						},
+						Type: CompoundUnionStr,
+						Right: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{
+									Expr: &Column{Name: "a"},
 								},
 							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t3", IsTarget: true},
+							},
 						},
 					},
 				},
@@ -3032,9 +3365,24 @@ func TestSelectStatement(t *testing.T) {
 		},
 
 		{
-			name:     "select union all",
-			stmt:     "SELECT a FROM t UNION ALL SELECT a FROM t2",
-			deparsed: "select a from t union all select a from t2",
+			name:     "values",
+			stmt:     "VALUES (1, 'a'), (2, 'b')",
+			deparsed: "values(1,'a'),(2,'b')",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Values{
+						Rows: []Exprs{
+							{&Value{Type: IntValue, Value: []byte("1")}, &Value{Type: StrValue, Value: []byte("a")}},
+							{&Value{Type: IntValue, Value: []byte("2")}, &Value{Type: StrValue, Value: []byte("b")}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "select union values",
+			stmt:     "SELECT a FROM t UNION VALUES (1)",
+			deparsed: "select a from t union values(1)",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&CompoundSelect{
@@ -3048,6 +3396,27 @@ func TestSelectStatement(t *testing.T) {
 								Expr: &Table{Name: "t", IsTarget: true},
 							},
 						},
+						Type: CompoundUnionStr,
+						Right: &Values{
+							Rows: []Exprs{{&Value{Type: IntValue, Value: []byte("1")}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "values union all select",
+			stmt:     "VALUES (1), (2) UNION ALL SELECT a FROM t",
+			deparsed: "values(1),(2)union all select a from t",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&CompoundSelect{
+						Left: &Values{
+							Rows: []Exprs{
+								{&Value{Type: IntValue, Value: []byte("1")}},
+								{&Value{Type: IntValue, Value: []byte("2")}},
+							},
+						},
 						Type: CompoundUnionAllStr,
 						Right: &Select{
 							SelectColumnList: SelectColumnList{
@@ -3056,7 +3425,7 @@ func TestSelectStatement(t *testing.T) {
 								},
 							},
 							From: &AliasedTableExpr{
-								Expr: &Table{Name: "t2", IsTarget: true},
+								Expr: &Table{Name: "t", IsTarget: true},
 							},
 						},
 					},
@@ -3064,23 +3433,22 @@ func TestSelectStatement(t *testing.T) {
 			},
 		},
 		{
-			name:     "select except",
-			stmt:     "SELECT a FROM t EXCEPT SELECT a FROM t2",
-			deparsed: "select a from t except select a from t2",
+			name:     "values except values intersect select",
+			stmt:     "VALUES (1) EXCEPT VALUES (2) INTERSECT SELECT a FROM t",
+			deparsed: "values(1)except values(2)intersect select a from t",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&CompoundSelect{
-						Left: &Select{
-							SelectColumnList: SelectColumnList{
-								&AliasedSelectColumn{
-									Expr: &Column{Name: "a"},
-								},
+						Left: &CompoundSelect{
+							Left: &Values{
+								Rows: []Exprs{{&Value{Type: IntValue, Value: []byte("1")}}},
 							},
-							From: &AliasedTableExpr{
-								Expr: &Table{Name: "t", IsTarget: true},
+							Type: CompoundExceptStr,
+							Right: &Values{
+								Rows: []Exprs{{&Value{Type: IntValue, Value: []byte("2")}}},
 							},
 						},
-						Type: CompoundExceptStr,
+						Type: CompoundIntersectStr,
 						Right: &Select{
 							SelectColumnList: SelectColumnList{
 								&AliasedSelectColumn{
@@ -3088,7 +3456,7 @@ func TestSelectStatement(t *testing.T) {
 								},
 							},
 							From: &AliasedTableExpr{
-								Expr: &Table{Name: "t2", IsTarget: true},
+								Expr: &Table{Name: "t", IsTarget: true},
 							},
 						},
 					},
@@ -3096,9 +3464,43 @@ func TestSelectStatement(t *testing.T) {
 			},
 		},
 		{
-			name:     "select intersect",
-			stmt:     "SELECT a FROM t INTERSECT SELECT a FROM t2 ORDER BY a",
-			deparsed: "select a from t intersect select a from t2 order by a asc",
+			name:     "values subquery",
+			stmt:     "SELECT a FROM t WHERE a IN (VALUES (1), (2))",
+			deparsed: "select a from t where a in(values(1),(2))",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &Column{Name: "a"},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: InStr,
+								Left:     &Column{Name: "a"},
+								Right: &Subquery{
+									Select: &Values{
+										Rows: []Exprs{
+											{&Value{Type: IntValue, Value: []byte("1")}},
+											{&Value{Type: IntValue, Value: []byte("2")}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "select union all",
+			stmt:     "SELECT a FROM t UNION ALL SELECT a FROM t2",
+			deparsed: "select a from t union all select a from t2",
 			expectedAST: &AST{
 				Statements: []Statement{
 					&CompoundSelect{
@@ -3112,7 +3514,7 @@ func TestSelectStatement(t *testing.T) {
 								Expr: &Table{Name: "t", IsTarget: true},
 							},
 						},
-						Type: CompoundIntersectStr,
+						Type: CompoundUnionAllStr,
 						Right: &Select{
 							SelectColumnList: SelectColumnList{
 								&AliasedSelectColumn{
@@ -3122,21 +3524,85 @@ func TestSelectStatement(t *testing.T) {
 							From: &AliasedTableExpr{
 								Expr: &Table{Name: "t2", IsTarget: true},
 							},
-							OrderBy: []*OrderingTerm{
-								{
-									Expr:      &Column{Name: "a"},
-									Direction: AscStr,
-								},
-							},
 						},
 					},
 				},
 			},
 		},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+		{
+			name:     "select except",
+			stmt:     "SELECT a FROM t EXCEPT SELECT a FROM t2",
+			deparsed: "select a from t except select a from t2",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&CompoundSelect{
+						Left: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{
+									Expr: &Column{Name: "a"},
+								},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t", IsTarget: true},
+							},
+						},
+						Type: CompoundExceptStr,
+						Right: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{
+									Expr: &Column{Name: "a"},
+								},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t2", IsTarget: true},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "select intersect",
+			stmt:     "SELECT a FROM t INTERSECT SELECT a FROM t2 ORDER BY a",
+			deparsed: "select a from t intersect select a from t2 order by a asc",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&CompoundSelect{
+						Left: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{
+									Expr: &Column{Name: "a"},
+								},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t", IsTarget: true},
+							},
+						},
+						Type: CompoundIntersectStr,
+						Right: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{
+									Expr: &Column{Name: "a"},
+								},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t2", IsTarget: true},
+							},
+							OrderBy: []*OrderingTerm{
+								{
+									Expr:      &Column{Name: "a"},
+									Direction: AscStr,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
 				ast, err := Parse(tc.stmt)
@@ -3168,79 +3634,2695 @@ func TestSelectStatement(t *testing.T) {
 					require.ErrorAs(t, ast.Errors[0], &tc.expectedErr)
 				}
 			}
-		}(tc))
+		}(tc))
+	}
+}
+
+func TestOrderByNullsNormalization(t *testing.T) {
+	t.Parallel()
+
+	stmts := []string{
+		"SELECT a FROM t ORDER BY a NULLS FIRST",
+		"SELECT a FROM t ORDER BY a NULLS LAST",
+		"SELECT a FROM t ORDER BY a DESC NULLS FIRST",
+		"SELECT a FROM t ORDER BY a ASC NULLS LAST",
+		"SELECT a FROM t ORDER BY a",
+	}
+
+	for _, stmt := range stmts {
+		t.Run(stmt, func(stmt string) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(stmt)
+				require.NoError(t, err)
+
+				// the normalized output is stable
+				normalized, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, normalized)
+				require.Equal(t, ast.String(), normalized.String())
+			}
+		}(stmt))
+	}
+}
+
+func TestFuncExprString(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		expr     *FuncExpr
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "star",
+			expr:     &FuncExpr{Name: "count"},
+			deparsed: "count(*)",
+		},
+		{
+			name:     "distinct star",
+			expr:     &FuncExpr{Name: "count", Distinct: true},
+			deparsed: "count(*)",
+		},
+		{
+			name:     "no args",
+			expr:     &FuncExpr{Name: "random", Args: Exprs{}},
+			deparsed: "random()",
+		},
+		{
+			name:     "distinct no args",
+			expr:     &FuncExpr{Name: "count", Distinct: true, Args: Exprs{}},
+			deparsed: "count()",
+		},
+		{
+			name:     "distinct args",
+			expr:     &FuncExpr{Name: "count", Distinct: true, Args: Exprs{&Column{Name: "a"}}},
+			deparsed: "count(distinct a)",
+		},
+		{
+			name: "filter",
+			expr: &FuncExpr{
+				Name:   "count",
+				Args:   Exprs{&Column{Name: "a"}, &Column{Name: "b"}},
+				Filter: &Where{Type: WhereStr, Expr: &Column{Name: "c"}},
+			},
+			deparsed: "count(a,b)filter(where c)",
+		},
+		{
+			name:     "filter without expr",
+			expr:     &FuncExpr{Name: "count", Filter: &Where{Type: WhereStr}},
+			deparsed: "count(*)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				require.Equal(t, tc.deparsed, tc.expr.String())
+			}
+		}(tc))
+	}
+
+	t.Run("distinct without args", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse("SELECT count(distinct) FROM t")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DISTINCT")
+	})
+}
+
+func TestCaseExprRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "searched comparisons",
+			stmt:     "SELECT CASE WHEN a > 2 THEN 'x' WHEN a < -1 THEN -1 WHEN a <> b THEN b ELSE -a END FROM t",
+			deparsed: "select case when a>2 then 'x' when a<-1 then -1 when a!=b then b else -a end from t",
+		},
+		{
+			name:     "simple with comparison results",
+			stmt:     "SELECT CASE a WHEN -1 THEN 1 WHEN +2 THEN a <= b WHEN (3) THEN a >= b ELSE a != b END FROM t",
+			deparsed: "select case a when -1 then 1 when +2 then a<=b when(3)then a>=b else a!=b end from t",
+		},
+		{
+			name:     "between and is",
+			stmt:     "SELECT CASE WHEN a BETWEEN 1 AND 2 THEN a = b WHEN a IS NOT NULL THEN a IS NOT b END FROM t",
+			deparsed: "select case when a between 1 and 2 then a=b when a is not null then a is not b end from t",
+		},
+		{
+			name:     "nested",
+			stmt:     "SELECT CASE WHEN a LIKE '1%' THEN CASE b WHEN 1 THEN 2 END ELSE a || b END FROM t",
+			deparsed: "select case when a like '1%' then case b when 1 then 2 end else a||b end from t",
+		},
+		{
+			name:     "in and subquery",
+			stmt:     "SELECT CASE WHEN a NOT IN (1, 2) THEN (a) WHEN EXISTS (SELECT 1 FROM t) THEN x'aa' END FROM t",
+			deparsed: "select case when a not in(1,2)then(a) when exists(select 1 from t)then X'aa' end from t",
+		},
+		{
+			name:     "compared",
+			stmt:     "SELECT CASE WHEN a > 2 THEN b > -1 END > 0 FROM t WHERE CASE a WHEN 1 THEN 1 END = 1",
+			deparsed: "select case when a>2 then b>-1 end>0 from t where case a when 1 then 1 end=1",
+		},
+		{
+			name:     "negated and subtracted",
+			stmt:     "SELECT -CASE WHEN a THEN 1 END, a - CASE WHEN a THEN -1 END, CASE WHEN a THEN 1 END - -a FROM t",
+			deparsed: "select -case when a then 1 end,a-case when a then -1 end,case when a then 1 end- -a from t",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int, b int); INSERT INTO t VALUES (1, 2), (3, 1), (-2, 2), (NULL, 0);")
+				require.NoError(t, err)
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+}
+
+func TestNegativeOperandNotComment(t *testing.T) {
+	t.Parallel()
+
+	// the deparsed minus signs must not start a -- comment that hides the rest of the statement
+	stmt := "delete from t where b = - -1 and c = 'x\n' and d = ' or 1=1 --'"
+	ast, err := Parse(stmt)
+	require.NoError(t, err)
+	require.Equal(t, "delete from t where b=- -1 and c='x\n' and d=' or 1=1 --'", ast.String())
+
+	deleted := func(query string) int64 {
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+
+		_, err = db.Exec("CREATE TABLE t (b int, c text, d text); INSERT INTO t VALUES (1, 'y', 'z'), (2, 'y', 'z');")
+		require.NoError(t, err)
+		result, err := db.Exec(query)
+		require.NoError(t, err)
+		rows, err := result.RowsAffected()
+		require.NoError(t, err)
+		return rows
+	}
+	require.Equal(t, int64(0), deleted(stmt))
+	require.Equal(t, int64(0), deleted(ast.String()))
+}
+
+func TestUnaryLiterals(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		expr     Expr
+		value    interface{}
+	}
+
+	tests := []testCase{
+		{
+			name:     "negative hex",
+			stmt:     "SELECT -0xAF FROM t",
+			deparsed: "select -0xAF from t",
+			expr:     &UnaryExpr{Operator: UMinusStr, Expr: &Value{Type: HexNumValue, Value: []byte("0xAF")}},
+			value:    int64(-175),
+		},
+		{
+			name:     "double negative hex",
+			stmt:     "SELECT - -0xAF FROM t",
+			deparsed: "select - -0xAF from t",
+			expr: &UnaryExpr{
+				Operator: UMinusStr,
+				Expr:     &UnaryExpr{Operator: UMinusStr, Expr: &Value{Type: HexNumValue, Value: []byte("0xAF")}},
+			},
+			value: int64(175),
+		},
+		{
+			name:     "double negative int",
+			stmt:     "SELECT - -1 FROM t",
+			deparsed: "select - -1 from t",
+			expr:     &UnaryExpr{Operator: UMinusStr, Expr: &Value{Type: IntValue, Value: []byte("-1")}},
+			value:    int64(1),
+		},
+		{
+			name:     "triple negative int",
+			stmt:     "SELECT - - -0 FROM t",
+			deparsed: "select - - -0 from t",
+			expr: &UnaryExpr{
+				Operator: UMinusStr,
+				Expr:     &UnaryExpr{Operator: UMinusStr, Expr: &Value{Type: IntValue, Value: []byte("-0")}},
+			},
+			value: int64(0),
+		},
+		{
+			name:     "bitwise not hex",
+			stmt:     "SELECT ~0x1 FROM t",
+			deparsed: "select ~0x1 from t",
+			expr:     &UnaryExpr{Operator: TildaStr, Expr: &Value{Type: HexNumValue, Value: []byte("0x1")}},
+			value:    int64(-2),
+		},
+		{
+			name:     "negative blob",
+			stmt:     "SELECT -x'00' FROM t",
+			deparsed: "select -X'00' from t",
+			expr:     &UnaryExpr{Operator: UMinusStr, Expr: &Value{Type: BlobValue, Value: []byte("00")}},
+			value:    int64(0),
+		},
+		{
+			name:     "positive blob",
+			stmt:     "SELECT +x'01' FROM t",
+			deparsed: "select +X'01' from t",
+			expr:     &UnaryExpr{Operator: UPlusStr, Expr: &Value{Type: BlobValue, Value: []byte("01")}},
+			value:    []byte{0x01},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+				require.Equal(t, tc.expr, ast.Statements[0].(*Select).SelectColumnList[0].(*AliasedSelectColumn).Expr)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int); INSERT INTO t VALUES (1);")
+				require.NoError(t, err)
+				require.Equal(t, [][]interface{}{{tc.value}}, queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+}
+
+func TestConcatRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "comparison operands",
+			stmt:     "SELECT (a > b) || c, c || (a > b) FROM t",
+			deparsed: "select(a>b)||c,c||(a>b)from t",
+		},
+		{
+			name:     "concatenation compared",
+			stmt:     "SELECT (a || b) > c, a || b > c, (a = b) || (b <> c) || 'x' FROM t",
+			deparsed: "select(a||b)>c,a||b>c,(a=b)||(b!=c)||'x' from t",
+		},
+		{
+			name:     "nested parentheses",
+			stmt:     "SELECT a || (b < c) || (a >= (b || c)) FROM t WHERE (a || b) = (c || 'x')",
+			deparsed: "select a||(b<c)||(a>=(b||c))from t where (a||b)=(c||'x')",
+		},
+		{
+			name:     "unary and predicates",
+			stmt:     "SELECT a || -b, a || (a NOT IN (1)) || (a IS NOT NULL) FROM t",
+			deparsed: "select a||-b,a||(a not in(1))||(a is not null)from t",
+		},
+		{
+			name:     "functions",
+			stmt:     "SELECT upper(a || 'x') || lower(b) <= trim(c) || (a != b) FROM t",
+			deparsed: "select upper(a||'x')||lower(b)<=trim(c)||(a!=b)from t",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a text, b text, c text); INSERT INTO t VALUES ('a', 'b', 'c'), ('b', 'a', '1x'), ('1', '1', NULL);")
+				require.NoError(t, err)
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+}
+
+func TestMaxLikePatternLength(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		maxLength   int
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:      "like within limit",
+			stmt:      "select a from t where a like 'abcde'",
+			maxLength: 5,
+		},
+		{
+			name:        "like too long",
+			stmt:        "select a from t where a like 'abcdef'",
+			maxLength:   5,
+			expectedErr: &ErrLikePatternTooLong{Length: 6, MaxAllowed: 5},
+		},
+		{
+			name:        "not like too long",
+			stmt:        "select a from t where a not like 'abcdef'",
+			maxLength:   5,
+			expectedErr: &ErrLikePatternTooLong{Length: 6, MaxAllowed: 5},
+		},
+		{
+			name:        "like with escape too long",
+			stmt:        "select a from t where a like 'abc!%def' escape '!'",
+			maxLength:   5,
+			expectedErr: &ErrLikePatternTooLong{Length: 8, MaxAllowed: 5},
+		},
+		{
+			name:        "glob too long",
+			stmt:        "select a from t where a glob 'abcdef*'",
+			maxLength:   5,
+			expectedErr: &ErrLikePatternTooLong{Length: 7, MaxAllowed: 5},
+		},
+		{
+			name:        "not glob too long",
+			stmt:        "delete from t where a not glob 'abcdef*'",
+			maxLength:   5,
+			expectedErr: &ErrLikePatternTooLong{Length: 7, MaxAllowed: 5},
+		},
+		{
+			name:      "escaped quotes count once",
+			stmt:      "select a from t where a like 'ab''cd'",
+			maxLength: 5,
+		},
+		{
+			name:      "column pattern is not checked",
+			stmt:      "select a from t where a like b",
+			maxLength: 5,
+		},
+		{
+			name: "default limit",
+			stmt: fmt.Sprintf("select a from t where a like '%s'", strings.Repeat("a", MaxTextLength)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				_, err := ParseWithOptions(tc.stmt, ParseOptions{MaxLikePatternLength: tc.maxLength})
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrLikePatternTooLong))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
+
+func TestLimitExpr(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "integer",
+			stmt:     "select a from t limit 10 offset 2",
+			deparsed: "select a from t limit 10 offset 2",
+		},
+		{
+			name:     "negative integer",
+			stmt:     "select a from t limit -1",
+			deparsed: "select a from t limit -1",
+		},
+		{
+			name:     "hex integer",
+			stmt:     "select a from t limit 0x10",
+			deparsed: "select a from t limit 0x10",
+		},
+		{
+			name:     "params",
+			stmt:     "select a from t limit ? offset ?",
+			deparsed: "select a from t limit ? offset ?",
+		},
+		{
+			name:        "column",
+			stmt:        "select a from t limit a",
+			expectedErr: &ErrInvalidLimitExpr{Expr: "a"},
+		},
+		{
+			name:        "expression",
+			stmt:        "select a from t limit 1+1",
+			expectedErr: &ErrInvalidLimitExpr{Expr: "1+1"},
+		},
+		{
+			name:        "string",
+			stmt:        "select a from t limit '1'",
+			expectedErr: &ErrInvalidLimitExpr{Expr: "'1'"},
+		},
+		{
+			name:        "offset column",
+			stmt:        "select a from t limit 10 offset a",
+			expectedErr: &ErrInvalidLimitExpr{Expr: "a"},
+		},
+		{
+			name:        "comma offset subquery",
+			stmt:        "select a from t limit (select max(a) from t), 10",
+			expectedErr: &ErrInvalidLimitExpr{Expr: "(select max(a)from t)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.Equal(t, tc.deparsed, ast.String())
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrInvalidLimitExpr))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
+
+func TestCastExpr(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "nested expression",
+			stmt:     "SELECT CAST((a+b) AS INTEGER) FROM t",
+			deparsed: "select cast((a+b)as integer)from t",
+		},
+		{
+			name:     "binary expression",
+			stmt:     "SELECT CAST(a * b + 1 AS TEXT) FROM t",
+			deparsed: "select cast(a*b+1 as text)from t",
+		},
+		{
+			name:     "function call",
+			stmt:     "SELECT CAST(abs(a - b) || 'x' AS NONE) FROM t",
+			deparsed: "select cast(abs(a-b)||'x' as none)from t",
+		},
+		{
+			name:     "nested cast",
+			stmt:     "SELECT CAST(CAST(a AS TEXT) AS INTEGER) FROM t WHERE CAST(b AS TEXT) = '2'",
+			deparsed: "select cast(cast(a as text)as integer)from t where cast(b as text)='2'",
+		},
+		{
+			name:     "case",
+			stmt:     "SELECT CAST(CASE WHEN a > 1 THEN b ELSE 0 END AS TEXT) FROM t",
+			deparsed: "select cast(case when a>1 then b else 0 end as text)from t",
+		},
+		{
+			name:        "unsupported type",
+			stmt:        "SELECT CAST(a AS INT8) FROM t",
+			expectedErr: &ErrInvalidCastType{Type: "INT8"},
+		},
+		{
+			name:        "unsupported type in where",
+			stmt:        "SELECT a FROM t WHERE CAST(a AS varchar) = '1'",
+			expectedErr: &ErrInvalidCastType{Type: "varchar"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr != nil {
+					require.ErrorAs(t, err, new(*ErrInvalidCastType))
+					require.ErrorContains(t, err, tc.expectedErr.Error())
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				// the deparsed statement round-trips
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int, b int); INSERT INTO t VALUES (1, 2), (3, 4);")
+				require.NoError(t, err)
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, tc.deparsed))
+			}
+		}(tc))
+	}
+}
+
+func TestLikeEscape(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "backslash",
+			stmt:     "select a from t where a like '%a\\%%' escape '\\'",
+			deparsed: "select a from t where a like '%a\\%%' escape '\\'",
+		},
+		{
+			name:     "escaped quote",
+			stmt:     "select a from t where a like '%a''%%' escape ''''",
+			deparsed: "select a from t where a like '%a''%%' escape ''''",
+		},
+		{
+			name:     "multibyte character",
+			stmt:     "select a from t where a not like '%aé%%' escape 'é'",
+			deparsed: "select a from t where a not like '%aé%%' escape 'é'",
+		},
+		{
+			name:        "two characters",
+			stmt:        "select a from t where a like '%a' escape 'ab'",
+			expectedErr: &ErrInvalidEscape{Expr: "'ab'"},
+		},
+		{
+			name:        "empty string",
+			stmt:        "select a from t where a like '%a' escape ''",
+			expectedErr: &ErrInvalidEscape{Expr: "''"},
+		},
+		{
+			name:        "column",
+			stmt:        "select a from t where a like '%a' escape b",
+			expectedErr: &ErrInvalidEscape{Expr: "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr != nil {
+					require.ErrorAs(t, err, new(*ErrInvalidEscape))
+					require.ErrorContains(t, err, tc.expectedErr.Error())
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a text, b text); INSERT INTO t VALUES ('xa%', '');")
+				require.NoError(t, err)
+				queryRows(t, db, ast.String())
+			}
+		}(tc))
+	}
+}
+
+func TestParenTableExprDeparse(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "single table",
+			stmt:     "select a.id from (a)",
+			deparsed: "select a.id from(a)",
+		},
+		{
+			name:     "join at top level",
+			stmt:     "select a.id from (a join b)",
+			deparsed: "select a.id from a join b",
+		},
+		{
+			name:     "join on the left side",
+			stmt:     "select a.id, b.id, c.id from (a join b on a.id = b.id) left join c on a.id = c.id",
+			deparsed: "select a.id,b.id,c.id from a join b on a.id=b.id left join c on a.id=c.id",
+		},
+		{
+			name:     "join on the right side",
+			stmt:     "select a.id, b.id, c.id from a left join (b join c on b.id = c.id) on a.id = b.id",
+			deparsed: "select a.id,b.id,c.id from a left join(b join c on b.id=c.id)on a.id=b.id",
+		},
+		{
+			name:     "nested joins on the right side",
+			stmt:     "select a.id, b.id, c.id from a left join (b left join (c join a as a2 using (id)) on b.id = c.id) using (id)",
+			deparsed: "select a.id,b.id,c.id from a left join(b left join(c join a as a2 using(id))on b.id=c.id)using(id)",
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec("create table a (id int); create table b (id int); create table c (id int); insert into a values (1), (2); insert into b values (1), (2); insert into c values (1)")
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, ast.String()))
+			}
+		}(it))
+	}
+}
+
+func TestCompoundSelectAssociativity(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name  string
+		stmt  string
+		shape string
+	}
+
+	// shape renders the nesting of a compound select with explicit parens
+	var shape func(stmt ReadStatement) string
+	shape = func(stmt ReadStatement) string {
+		if compound, ok := stmt.(*CompoundSelect); ok {
+			return "(" + shape(compound.Left) + " " + compound.Type + " " + shape(compound.Right) + ")"
+		}
+		return stmt.(*Select).From.String()
+	}
+
+	// nested evaluates the compound select with its nesting made explicit with subqueries
+	var nested func(stmt ReadStatement) string
+	nested = func(stmt ReadStatement) string {
+		if compound, ok := stmt.(*CompoundSelect); ok {
+			return "select * from (" + nested(compound.Left) + ") " + compound.Type + " select * from (" + nested(compound.Right) + ")"
+		}
+		return stmt.String()
+	}
+
+	tests := []testCase{
+		{
+			name:  "three unions",
+			stmt:  "select a from t1 union select a from t2 union select a from t3",
+			shape: "((t1 union t2) union t3)",
+		},
+		{
+			name:  "three excepts",
+			stmt:  "select a from t1 except select a from t2 except select a from t3",
+			shape: "((t1 except t2) except t3)",
+		},
+		{
+			name:  "except then union",
+			stmt:  "select a from t1 except select a from t2 union select a from t3",
+			shape: "((t1 except t2) union t3)",
+		},
+		{
+			name:  "union all then intersect",
+			stmt:  "select a from t1 union all select a from t2 intersect select a from t3",
+			shape: "((t1 union all t2) intersect t3)",
+		},
+		{
+			name:  "four operands",
+			stmt:  "select a from t1 union select a from t2 except select a from t3 intersect select a from t1",
+			shape: "(((t1 union t2) except t3) intersect t1)",
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec(`create table t1 (a int); create table t2 (a int); create table t3 (a int);
+		insert into t1 values (1), (2), (3); insert into t2 values (2), (4); insert into t3 values (3), (4), (5)`)
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.shape, shape(ast.Statements[0].(ReadStatement)))
+
+				// the nesting must produce the same rows as SQLite's evaluation of the statement
+				require.Equal(t,
+					queryRows(t, db, tc.stmt+" order by 1"),
+					queryRows(t, db, "select * from ("+nested(ast.Statements[0].(ReadStatement))+") order by 1"),
+				)
+			}
+		}(it))
+	}
+
+	t.Run("order by and limit belong to the last select", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t1 except select a from t2 except select a from t3 order by a desc limit 1")
+		require.NoError(t, err)
+
+		compound := ast.Statements[0].(*CompoundSelect)
+		require.IsType(t, &CompoundSelect{}, compound.Left)
+		last := compound.Right.(*Select)
+		require.Equal(t, "order by a desc", last.OrderBy.String())
+		require.Equal(t, "limit 1", last.Limit.String())
+		require.Equal(t, [][]interface{}{{int64(1)}}, queryRows(t, db, ast.String()))
+	})
+}
+
+func TestCompoundSelectDistinct(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name      string
+		stmt      string
+		deparsed  string
+		distincts []string
+	}
+
+	// distincts returns the Distinct of each select of a compound select, in order
+	var distincts func(stmt ReadStatement) []string
+	distincts = func(stmt ReadStatement) []string {
+		if compound, ok := stmt.(*CompoundSelect); ok {
+			return append(distincts(compound.Left), distincts(compound.Right)...)
+		}
+		return []string{stmt.(*Select).Distinct}
+	}
+
+	tests := []testCase{
+		{
+			name:      "distinct left",
+			stmt:      "SELECT DISTINCT a FROM t1 UNION ALL SELECT a FROM t2",
+			deparsed:  "select distinct a from t1 union all select a from t2",
+			distincts: []string{DistinctStr, ""},
+		},
+		{
+			name:      "distinct right",
+			stmt:      "SELECT a FROM t1 UNION ALL SELECT DISTINCT a FROM t2",
+			deparsed:  "select a from t1 union all select distinct a from t2",
+			distincts: []string{"", DistinctStr},
+		},
+		{
+			name:      "distinct both",
+			stmt:      "SELECT DISTINCT a FROM t1 UNION SELECT DISTINCT a FROM t2",
+			deparsed:  "select distinct a from t1 union select distinct a from t2",
+			distincts: []string{DistinctStr, DistinctStr},
+		},
+		{
+			name:      "all and distinct in three operands",
+			stmt:      "SELECT ALL a FROM t1 UNION ALL SELECT DISTINCT a FROM t2 EXCEPT SELECT a FROM t1 WHERE a > 2",
+			deparsed:  "select all a from t1 union all select distinct a from t2 except select a from t1 where a>2",
+			distincts: []string{AllStr, DistinctStr, ""},
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	// the tables have duplicated rows, so DISTINCT changes the results of UNION ALL
+	_, err = db.Exec(`create table t1 (a int); create table t2 (a int);
+		insert into t1 values (1), (1), (2), (3); insert into t2 values (2), (2), (4)`)
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+				require.Equal(t, tc.distincts, distincts(ast.Statements[0].(ReadStatement)))
+
+				// the deparsed statement round-trips and has the same results
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+				require.Equal(t, queryRows(t, db, tc.stmt+" order by 1"), queryRows(t, db, ast.String()+" order by 1"))
+			}
+		}(it))
+	}
+}
+
+func TestAliasKeywordBoundaries(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "column alias without as",
+			stmt:     "select a x, count(*) c, 1 y from t",
+			deparsed: "select a as x,count(*)as c,1 as y from t",
+		},
+		{
+			name:     "quoted column aliases without as",
+			stmt:     "select a 'x', a \"y\", a [z], a `w` from t",
+			deparsed: "select a as 'x',a as \"y\",a as [z],a as `w` from t",
+		},
+		{
+			name:     "from is not an alias",
+			stmt:     "select a from t",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "where is not a table alias",
+			stmt:     "select a from t where a = 1",
+			deparsed: "select a from t where a=1",
+		},
+		{
+			name:     "table alias before where",
+			stmt:     "select a from t x where x.a = 1",
+			deparsed: "select a from t as x where x.a=1",
+		},
+		{
+			name:     "table alias before group by, order by and limit",
+			stmt:     "select x.a from t x group by x.a order by x.a limit 1",
+			deparsed: "select x.a from t as x group by x.a order by x.a asc limit 1",
+		},
+		{
+			name:     "join keywords are not table aliases",
+			stmt:     "select x.a from t x left join t2 y on x.a = y.a inner join t2 z on y.a = z.a cross join t2 w",
+			deparsed: "select x.a from t as x left join t2 as y on x.a=y.a inner join t2 as z on y.a=z.a join t2 as w",
+		},
+		{
+			name:     "natural is not a table alias",
+			stmt:     "select x.a from t x natural join t2",
+			deparsed: "select x.a from t as x natural join t2",
+		},
+		{
+			name:     "comma join with aliases",
+			stmt:     "select x.a from t x, t2 y",
+			deparsed: "select x.a from t as x join t2 as y",
+		},
+		{
+			name:     "subquery alias without as",
+			stmt:     "select s.a from (select a from t) s where s.a > 0",
+			deparsed: "select s.a from(select a from t)as s where s.a>0",
+		},
+		{
+			name:     "compound operators are not aliases",
+			stmt:     "select a x from t union select a from t2 except select a y from t2 intersect select a from t",
+			deparsed: "select a as x from t union select a from t2 except select a as y from t2 intersect select a from t",
+		},
+		{
+			name:     "compound operators are not table aliases",
+			stmt:     "select x.a from t x union all select y.a from t2 y",
+			deparsed: "select x.a from t as x union all select y.a from t2 as y",
+		},
+		{
+			name:     "postfix operators are not aliases",
+			stmt:     "select a isnull, a notnull, a collate nocase from t",
+			deparsed: "select a isnull,a notnull,a collate nocase from t",
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec("create table t (a int); create table t2 (a int)")
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				_, err = db.Exec(tc.stmt)
+				require.NoError(t, err)
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+			}
+		}(it))
+	}
+
+	t.Run("keywords can't be aliases", func(t *testing.T) {
+		t.Parallel()
+
+		for _, stmt := range []string{
+			"select a as from from t",
+			"select a from from t",
+			"select a select from t",
+			"select a where from t",
+			"select a from t as where",
+			"select a from t as join t2",
+		} {
+			_, err := Parse(stmt)
+			require.ErrorAs(t, err, new(*ErrSyntaxError), stmt)
+
+			_, err = db.Exec(stmt)
+			require.Error(t, err, stmt)
+		}
+	})
+}
+
+func TestMaxJoins(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		maxJoins    int
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "no limit",
+			stmt:     "select * from t1 join t2 join t3 join t4 join t5 join t6 join t7",
+			maxJoins: 0,
+		},
+		{
+			name:     "nested joins at the limit",
+			stmt:     "select * from t1 join t2 on t1.a = t2.a join t3 on t2.a = t3.a join t4 on t3.a = t4.a",
+			maxJoins: 3,
+		},
+		{
+			name:        "nested joins over the limit",
+			stmt:        "select * from t1 join t2 on t1.a = t2.a join t3 on t2.a = t3.a join t4 on t3.a = t4.a",
+			maxJoins:    2,
+			expectedErr: &ErrTooManyJoins{JoinsCount: 3, MaxAllowed: 2},
+		},
+		{
+			name:        "comma joins",
+			stmt:        "select * from t1, t2, t3",
+			maxJoins:    1,
+			expectedErr: &ErrTooManyJoins{JoinsCount: 2, MaxAllowed: 1},
+		},
+		{
+			name:        "joins in subqueries",
+			stmt:        "select * from t1 join (select t2.a from t2 join t3) as s where a in (select t4.a from t4 join t5)",
+			maxJoins:    2,
+			expectedErr: &ErrTooManyJoins{JoinsCount: 3, MaxAllowed: 2},
+		},
+		{
+			name:        "compound select",
+			stmt:        "select * from t1 join t2 union select * from t3 join t4",
+			maxJoins:    1,
+			expectedErr: &ErrTooManyJoins{JoinsCount: 2, MaxAllowed: 1},
+		},
+		{
+			name:     "no joins",
+			stmt:     "select * from t1 where a = 1",
+			maxJoins: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				_, err := ParseWithOptions(tc.stmt, ParseOptions{MaxJoins: tc.maxJoins})
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrTooManyJoins))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
+
+func TestMaxIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name           string
+		stmt           string
+		maxIdentifiers int
+		expectedErr    error
+	}
+
+	tests := []testCase{
+		{
+			name:           "within the limit",
+			stmt:           "select a, b from t where a = 1",
+			maxIdentifiers: 3,
+		},
+		{
+			name:           "repeated identifiers are counted once",
+			stmt:           "select a, A, t.a from t where a > 1 and \"a\" < 10 order by a",
+			maxIdentifiers: 2,
+		},
+		{
+			name:           "aliases are not counted",
+			stmt:           "select a as x from t as y",
+			maxIdentifiers: 2,
+		},
+		{
+			name:           "too many columns",
+			stmt:           "select a, b, c from t where d = 1",
+			maxIdentifiers: 4,
+			expectedErr:    &ErrTooManyIdentifiers{IdentifiersCount: 5, MaxAllowed: 4},
+		},
+		{
+			name:           "tables in joins and subqueries",
+			stmt:           "select t.a from t join t2 on t.a = t2.a where t.a in (select a from t3)",
+			maxIdentifiers: 3,
+			expectedErr:    &ErrTooManyIdentifiers{IdentifiersCount: 4, MaxAllowed: 3},
+		},
+		{
+			name:           "write statement",
+			stmt:           "update t set a = 1, b = 2 where c = 3",
+			maxIdentifiers: 3,
+			expectedErr:    &ErrTooManyIdentifiers{IdentifiersCount: 4, MaxAllowed: 3},
+		},
+		{
+			name:           "no limit",
+			stmt:           "select a, b, c, d, e, f from t",
+			maxIdentifiers: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				_, err := ParseWithOptions(tc.stmt, ParseOptions{MaxIdentifiers: tc.maxIdentifiers})
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrTooManyIdentifiers))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+
+	t.Run("second statement", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := ParseWithOptions(
+			"insert into t (a) values (1); update t set a = 1, b = 2 where c = 3",
+			ParseOptions{MaxIdentifiers: 3},
+		)
+		require.NoError(t, err)
+		require.Len(t, ast.Errors, 1)
+		require.ErrorAs(t, ast.Errors[1], new(*ErrTooManyIdentifiers))
+	})
+}
+
+func TestRequireLimitOnSelect(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name      string
+		stmt      string
+		mustLimit bool
+	}
+
+	tests := []testCase{
+		{
+			name: "select with limit",
+			stmt: "select a from t limit 10",
+		},
+		{
+			name: "select with limit and offset",
+			stmt: "select a from t order by a limit 10 offset 20",
+		},
+		{
+			name:      "select without limit",
+			stmt:      "select a from t",
+			mustLimit: true,
+		},
+		{
+			name: "subqueries don't need a limit",
+			stmt: "select a from (select a from t) s where a in (select b from t2) limit 1",
+		},
+		{
+			name:      "limit only in subquery",
+			stmt:      "select a from (select a from t limit 1) s",
+			mustLimit: true,
+		},
+		{
+			name: "compound select with limit",
+			stmt: "select a from t union select a from t2 limit 5",
+		},
+		{
+			name:      "compound select without limit",
+			stmt:      "select a from t union select a from t2",
+			mustLimit: true,
+		},
+		{
+			name: "compound select with values and limit",
+			stmt: "values (1) union select a from t limit 5",
+		},
+		{
+			name: "values",
+			stmt: "values (1), (2)",
+		},
+		{
+			name: "writes are not checked",
+			stmt: "insert into t (a) select a from t2; delete from t where a in (1)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				_, err = ParseWithOptions(tc.stmt, ParseOptions{RequireLimitOnSelect: true})
+				if !tc.mustLimit {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrMissingLimit))
+			}
+		}(tc))
+	}
+
+	t.Run("compound select ending with values", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions("select a from t union values (1)", ParseOptions{RequireLimitOnSelect: true})
+		require.ErrorAs(t, err, new(*ErrMissingLimitAfterValues))
+
+		// VALUES can't be followed by a LIMIT
+		_, err = Parse("select a from t union values (1) limit 5")
+		require.ErrorAs(t, err, new(*ErrSyntaxError))
+	})
+}
+
+func TestRequireWhereOnDestructive(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+		stmtIndex   int
+	}
+
+	tests := []testCase{
+		{
+			name:        "delete without where",
+			stmt:        "delete from t",
+			expectedErr: &ErrMissingWhere{StatementKind: "delete"},
+		},
+		{
+			name:        "update without where",
+			stmt:        "update t set a = 1",
+			expectedErr: &ErrMissingWhere{StatementKind: "update"},
+		},
+		{
+			name:        "second statement without where",
+			stmt:        "update t set a = 1 where b = 2; delete from t",
+			expectedErr: &ErrMissingWhere{StatementKind: "delete"},
+			stmtIndex:   1,
+		},
+		{
+			name: "delete with where",
+			stmt: "delete from t where a = 1",
+		},
+		{
+			name: "update with where",
+			stmt: "update t set a = 1 where true",
+		},
+		{
+			name: "insert and select",
+			stmt: "insert into t (a) values (1); insert into t (a) select b from t2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{RequireWhereOnDestructive: true})
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.Len(t, ast.Errors, 0)
+					return
+				}
+				require.Len(t, ast.Errors, 1)
+				require.ErrorAs(t, ast.Errors[tc.stmtIndex], new(*ErrMissingWhere))
+				require.ErrorContains(t, ast.Errors[tc.stmtIndex], tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
+
+func TestDoubleQuoteIsString(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		opts        ParseOptions
+		deparsed    string
+		expectedAST *AST
+	}
+
+	tests := []testCase{
+		{
+			name:     "default identifier",
+			stmt:     `SELECT "foo" FROM t`,
+			opts:     ParseOptions{},
+			deparsed: `select "foo" from t`,
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{Expr: &Column{Name: `"foo"`}},
+						},
+						From: &AliasedTableExpr{Expr: &Table{Name: "t", IsTarget: true}},
+					},
+				},
+			},
+		},
+		{
+			name:     "string",
+			stmt:     `SELECT "foo" FROM t`,
+			opts:     ParseOptions{DoubleQuoteIsString: true},
+			deparsed: `select 'foo' from t`,
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{Expr: &Value{Type: StrValue, Value: []byte("foo")}},
+						},
+						From: &AliasedTableExpr{Expr: &Table{Name: "t", IsTarget: true}},
+					},
+				},
+			},
+		},
+		{
+			name:     "string with quotes",
+			stmt:     `SELECT "it's ""quoted""" FROM t`,
+			opts:     ParseOptions{DoubleQuoteIsString: true},
+			deparsed: `select 'it''s "quoted"' from t`,
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{Expr: &Value{Type: StrValue, Value: []byte(`it''s "quoted"`)}},
+						},
+						From: &AliasedTableExpr{Expr: &Table{Name: "t", IsTarget: true}},
+					},
+				},
+			},
+		},
+		{
+			name:     "string in where and backtick identifier",
+			stmt:     "SELECT `a` FROM t WHERE b = \"x\"",
+			opts:     ParseOptions{DoubleQuoteIsString: true},
+			deparsed: "select `a` from t where b='x'",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{Expr: &Column{Name: "`a`"}},
+						},
+						From: &AliasedTableExpr{Expr: &Table{Name: "t", IsTarget: true}},
+						Where: &Where{
+							Type: WhereStr,
+							Expr: &CmpExpr{
+								Operator: EqualStr,
+								Left:     &Column{Name: "b"},
+								Right:    &Value{Type: StrValue, Value: []byte("x")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := ParseWithOptions(tc.stmt, tc.opts)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedAST, ast)
+				require.Equal(t, tc.deparsed, ast.String())
+			}
+		}(tc))
+	}
+
+	t.Run("unterminated", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseWithOptions(`SELECT "foo FROM t`, ParseOptions{DoubleQuoteIsString: true})
+		require.Error(t, err)
+	})
+
+	t.Run("double quoted identifier is not allowed", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseWithOptions(`CREATE TABLE t ("a" int)`, ParseOptions{DoubleQuoteIsString: true})
+		require.Error(t, err)
+	})
+}
+
+func TestAllowedFunctions(t *testing.T) {
+	t.Parallel()
+
+	genFunctionCallAndArgs := func(fname string) (string, Exprs) {
+		switch fname {
+		case "like":
+			return "like(a,b)", Exprs{
+				&Column{Name: "a"},
+				&Column{Name: "b"},
+			}
+		case "glob":
+			return "glob(a,b)", Exprs{
+				&Column{Name: "a"},
+				&Column{Name: "b"},
+			}
+		default:
+			arity, ok := functionArities[fname]
+			if !ok || arity.min == 0 {
+				return fmt.Sprintf("%s(*)", fname), nil
+			}
+
+			columns, args := make([]string, arity.min), make(Exprs, arity.min)
+			for i := range columns {
+				columns[i] = fmt.Sprintf("c%d", i)
+				args[i] = &Column{Name: Identifier(columns[i])}
+			}
+			return fmt.Sprintf("%s(%s)", fname, strings.Join(columns, ",")), args
+		}
+	}
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedAST *AST
+	}
+
+	tests := []testCase{}
+	for allowedFunction, isCustom := range AllowedFunctions {
+		if isCustom {
+			continue
+		}
+		functionCall, args := genFunctionCallAndArgs(allowedFunction)
+		tests = append(tests, testCase{
+			name:     allowedFunction,
+			stmt:     fmt.Sprintf("select %s from t", functionCall),
+			deparsed: fmt.Sprintf("select %sfrom t", functionCall),
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Select{
+						SelectColumnList: SelectColumnList{
+							&AliasedSelectColumn{
+								Expr: &FuncExpr{
+									Name: Identifier(allowedFunction),
+									Args: args,
+								},
+							},
+						},
+						From: &AliasedTableExpr{
+							Expr: &Table{Name: "t", IsTarget: true},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, tc.expectedAST, ast)
+				require.Equal(t, tc.deparsed, ast.String())
+			}
+		}(tc))
+	}
+}
+
+func TestFunctionArity(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		argsErr  *ErrWrongNumberOfArguments
+	}
+
+	tests := []testCase{
+		{
+			name:     "iif",
+			stmt:     "SELECT iif(a > 1, b, c) FROM t",
+			deparsed: "select iif(a>1,b,c)from t",
+		},
+		{
+			name:     "nullif",
+			stmt:     "SELECT nullif(a, 0) FROM t",
+			deparsed: "select nullif(a,0)from t",
+		},
+		{
+			name:     "ifnull",
+			stmt:     "SELECT ifnull(a, 'x') FROM t",
+			deparsed: "select ifnull(a,'x')from t",
+		},
+		{
+			name:    "iif missing argument",
+			stmt:    "SELECT iif(a > 1, b) FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "iif", ArgsCount: 2, Min: 3, Max: 3},
+		},
+		{
+			name:    "iif star",
+			stmt:    "SELECT iif(*) FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "iif", ArgsCount: 0, Min: 3, Max: 3},
+		},
+		{
+			name:    "nullif extra argument",
+			stmt:    "SELECT NULLIF(a, b, c) FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "nullif", ArgsCount: 3, Min: 2, Max: 2},
+		},
+		{
+			name:    "ifnull no arguments",
+			stmt:    "SELECT ifnull() FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "ifnull", ArgsCount: 0, Min: 2, Max: 2},
+		},
+		{
+			name:     "max aggregate",
+			stmt:     "SELECT max(a) FROM t",
+			deparsed: "select max(a)from t",
+		},
+		{
+			name:     "max scalar",
+			stmt:     "SELECT max(a, b) FROM t",
+			deparsed: "select max(a,b)from t",
+		},
+		{
+			name:     "min scalar",
+			stmt:     "SELECT min(a, b, c) FROM t",
+			deparsed: "select min(a,b,c)from t",
+		},
+		{
+			name:    "min no arguments",
+			stmt:    "SELECT min() FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "min", ArgsCount: 0, Min: 1, Max: -1},
+		},
+		{
+			name:     "coalesce",
+			stmt:     "SELECT coalesce(a, b, 0) FROM t",
+			deparsed: "select coalesce(a,b,0)from t",
+		},
+		{
+			name:    "coalesce one argument",
+			stmt:    "SELECT coalesce(a) FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "coalesce", ArgsCount: 1, Min: 2, Max: -1},
+		},
+		{
+			name:     "typeof",
+			stmt:     "SELECT typeof(a) FROM t",
+			deparsed: "select typeof(a)from t",
+		},
+		{
+			name:    "typeof extra argument",
+			stmt:    "SELECT typeof(a, b) FROM t",
+			argsErr: &ErrWrongNumberOfArguments{FunctionName: "typeof", ArgsCount: 2, Min: 1, Max: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int, b int, c int);")
+				require.NoError(t, err)
+
+				ast, err := Parse(tc.stmt)
+				if tc.argsErr == nil {
+					require.NoError(t, err)
+					require.Equal(t, tc.deparsed, ast.String())
+
+					_, err = db.Exec(ast.String())
+					require.NoError(t, err)
+					return
+				}
+
+				require.Error(t, err)
+				e := &ErrWrongNumberOfArguments{}
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, tc.argsErr, e)
+
+				// SQLite also rejects it
+				_, err = db.Exec(tc.stmt)
+				require.Error(t, err)
+			}
+		}(tc))
+	}
+}
+
+func TestFilterOnNonAggregate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "count",
+			stmt: "select count(a) filter(where a>0) from t",
+		},
+		{
+			name: "count star",
+			stmt: "select count(*) filter(where a>0) from t",
+		},
+		{
+			name: "aggregate max",
+			stmt: "select max(a) filter(where a>0), sum(a) filter(where a>0), json_group_array(a) filter(where a>0) from t",
+		},
+		{
+			name:        "abs",
+			stmt:        "select abs(a) filter(where a>0) from t",
+			expectedErr: &ErrFilterOnNonAggregate{FunctionName: "abs"},
+		},
+		{
+			name:        "scalar max",
+			stmt:        "select max(a, 1) filter(where a>0) from t",
+			expectedErr: &ErrFilterOnNonAggregate{FunctionName: "max"},
+		},
+		{
+			name:        "upper case name",
+			stmt:        "select UPPER(a) filter(where a>0) from t",
+			expectedErr: &ErrFilterOnNonAggregate{FunctionName: "upper"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int)")
+				require.NoError(t, err)
+				_, sqliteErr := db.Exec(tc.stmt)
+
+				_, err = Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.NoError(t, sqliteErr)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrFilterOnNonAggregate))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				require.ErrorContains(t, sqliteErr, "FILTER may not be used with non-aggregate")
+			}
+		}(tc))
+	}
+}
+
+func TestWindowFrameSpec(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "empty window",
+			stmt:     "SELECT a, count(*) OVER () FROM t",
+			deparsed: "select a,count(*)over()from t",
+		},
+		{
+			name:     "partition and order",
+			stmt:     "SELECT a, sum(b) OVER (PARTITION BY a ORDER BY b DESC) FROM t",
+			deparsed: "select a,sum(b)over(partition by a order by b desc)from t",
+		},
+		{
+			name:     "rows unbounded preceding",
+			stmt:     "SELECT sum(b) OVER (ORDER BY b ROWS UNBOUNDED PRECEDING) FROM t",
+			deparsed: "select sum(b)over(order by b asc rows unbounded preceding)from t",
+		},
+		{
+			name:     "rows between unbounded preceding and current row",
+			stmt:     "SELECT sum(b) OVER (ORDER BY b ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM t",
+			deparsed: "select sum(b)over(order by b asc rows between unbounded preceding and current row)from t",
+		},
+		{
+			name:     "rows between offsets",
+			stmt:     "SELECT sum(b) OVER (ORDER BY b ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t",
+			deparsed: "select sum(b)over(order by b asc rows between 1 preceding and 1 following)from t",
+		},
+		{
+			name:     "range between current row and unbounded following",
+			stmt:     "SELECT sum(b) OVER (PARTITION BY a ORDER BY b RANGE BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) FROM t",
+			deparsed: "select sum(b)over(partition by a order by b asc range between current row and unbounded following)from t",
+		},
+		{
+			name:     "groups with exclude current row",
+			stmt:     "SELECT sum(b) OVER (ORDER BY a GROUPS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM t",
+			deparsed: "select sum(b)over(order by a asc groups between 1 preceding and 1 following exclude current row)from t",
+		},
+		{
+			name:     "exclude group, ties and no others",
+			stmt:     "SELECT sum(b) OVER (ORDER BY a ROWS CURRENT ROW EXCLUDE GROUP), sum(b) OVER (ORDER BY a ROWS 2 PRECEDING EXCLUDE TIES), count(*) OVER (ORDER BY a ROWS 1 PRECEDING EXCLUDE NO OTHERS) FROM t",
+			deparsed: "select sum(b)over(order by a asc rows current row exclude group),sum(b)over(order by a asc rows 2 preceding exclude ties),count(*)over(order by a asc rows 1 preceding exclude no others)from t",
+		},
+		{
+			name:     "filter and over",
+			stmt:     "SELECT count(*) FILTER (WHERE b > 1) OVER (PARTITION BY a) FROM t",
+			deparsed: "select count(*)filter(where b>1)over(partition by a)from t",
+		},
+		{
+			name: "window keywords as identifiers",
+			// inside a window definition they must be parenthesized to not be read as keywords
+			stmt:     "SELECT rows, range, current, partition, sum(rows) OVER (PARTITION BY (range) ORDER BY (current)) FROM w",
+			deparsed: "select rows,range,current,partition,sum(rows)over(partition by(range)order by(current)asc)from w",
+		},
+		{
+			name: "over as an alias",
+			// OVER is only read as a keyword after the parens of a function call, when a window definition follows
+			stmt:     "SELECT a over, (b) over, (SELECT max(a) FROM t) over, count(*) over FROM t",
+			deparsed: "select a as over,(b)as over,(select max(a)from t)as over,count(*)as over from t",
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec(`CREATE TABLE t (a int, b int); INSERT INTO t VALUES (1, 1), (1, 2), (1, 2), (2, 3), (2, 5), (3, 8);
+		CREATE TABLE w (rows int, range int, current int, partition int); INSERT INTO w VALUES (1, 1, 1, 1), (2, 1, 2, 2)`)
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, ast.String()))
+			}
+		}(it))
+	}
+
+	t.Run("ast", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("SELECT sum(b) OVER (PARTITION BY a ORDER BY b ROWS BETWEEN 2 PRECEDING AND CURRENT ROW EXCLUDE TIES) FROM t")
+		require.NoError(t, err)
+		require.Equal(t, &FuncExpr{
+			Name: "sum",
+			Args: Exprs{&Column{Name: "b"}},
+			Over: &WindowDef{
+				PartitionBy: Exprs{&Column{Name: "a"}},
+				OrderBy:     OrderBy{&OrderingTerm{Expr: &Column{Name: "b"}, Direction: AscStr, Nulls: NullsNil}},
+				Frame: &FrameSpec{
+					Unit:    FrameRowsStr,
+					Start:   &FrameBound{Type: FramePrecedingStr, Expr: &Value{Type: IntValue, Value: []byte("2")}},
+					End:     &FrameBound{Type: FrameCurrentRowStr},
+					Exclude: FrameExcludeTiesStr,
+				},
+			},
+		}, ast.Statements[0].(*Select).SelectColumnList[0].(*AliasedSelectColumn).Expr)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("SELECT abs(a) OVER () FROM t")
+		require.ErrorAs(t, err, new(*ErrOverOnNonAggregate))
+
+		_, err = Parse("SELECT block_num(1) OVER () FROM t")
+		require.ErrorContains(t, err, "custom function cannot have OVER")
+
+		for _, stmt := range []string{
+			"SELECT sum(b) OVER (ROWS UNBOUNDED FOLLOWING) FROM t",
+			"SELECT sum(b) OVER (ROWS 1 FOLLOWING) FROM t",
+			"SELECT sum(b) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW) FROM t",
+			"SELECT sum(b) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING) FROM t",
+			"SELECT sum(b) OVER (ROWS BETWEEN 1 PRECEDING) FROM t",
+			"SELECT sum(b) OVER (EXCLUDE TIES) FROM t",
+			"SELECT (sum(b)) OVER () FROM t",
+		} {
+			_, err := Parse(stmt)
+			require.ErrorAs(t, err, new(*ErrSyntaxError), stmt)
+
+			_, err = db.Exec(stmt)
+			require.Error(t, err, stmt)
+		}
+	})
+}
+
+func TestOrderingTermNulls(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		terms    []string
+		nulls    []NullsType
+	}
+
+	tests := []testCase{
+		{
+			name:     "select",
+			stmt:     "SELECT a FROM t ORDER BY b DESC NULLS LAST, a NULLS FIRST",
+			deparsed: "select a from t order by b desc nulls last,a asc nulls first",
+			terms:    []string{"b desc nulls last", "a asc nulls first"},
+			nulls:    []NullsType{NullsLast, NullsFirst},
+		},
+		{
+			name:     "compound select",
+			stmt:     "SELECT b FROM t UNION ALL SELECT b FROM t2 ORDER BY b DESC NULLS LAST",
+			deparsed: "select b from t union all select b from t2 order by b desc nulls last",
+			terms:    []string{"b desc nulls last"},
+			nulls:    []NullsType{NullsLast},
+		},
+		{
+			name:     "compound select nulls first",
+			stmt:     "SELECT b FROM t EXCEPT SELECT b FROM t2 ORDER BY 1 NULLS FIRST",
+			deparsed: "select b from t except select b from t2 order by 1 asc nulls first",
+			terms:    []string{"1 asc nulls first"},
+			nulls:    []NullsType{NullsFirst},
+		},
+		{
+			name:     "window",
+			stmt:     "SELECT a, count(b) OVER (ORDER BY b DESC NULLS LAST, a NULLS FIRST) FROM t ORDER BY a",
+			deparsed: "select a,count(b)over(order by b desc nulls last,a asc nulls first)from t order by a asc",
+			terms:    []string{"b desc nulls last", "a asc nulls first", "a asc"},
+			nulls:    []NullsType{NullsLast, NullsFirst, NullsNil},
+		},
+		{
+			name:     "window with partition and frame",
+			stmt:     "SELECT a, sum(a) OVER (PARTITION BY c ORDER BY b NULLS LAST ROWS UNBOUNDED PRECEDING) FROM t ORDER BY a",
+			deparsed: "select a,sum(a)over(partition by c order by b asc nulls last rows unbounded preceding)from t order by a asc",
+			terms:    []string{"b asc nulls last", "a asc"},
+			nulls:    []NullsType{NullsLast, NullsNil},
+		},
+	}
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.Exec(`create table t (a int, b int, c int); create table t2 (b int);
+		insert into t values (1, null, 1), (2, 2, 1), (3, 1, 2), (4, null, 2); insert into t2 values (null), (3)`)
+	require.NoError(t, err)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				// the ordering terms are rendered the same way in every context
+				terms, nulls := []string{}, []NullsType{}
+				require.NoError(t, Walk(func(node Node) (bool, error) {
+					if term, ok := node.(*OrderingTerm); ok && term != nil {
+						terms = append(terms, term.String())
+						nulls = append(nulls, term.Nulls)
+					}
+					return false, nil
+				}, ast))
+				require.Equal(t, tc.terms, terms)
+				require.Equal(t, tc.nulls, nulls)
+
+				require.Equal(t, queryRows(t, db, tc.stmt), queryRows(t, db, ast.String()))
+			}
+		}(it))
+	}
+}
+
+func TestSelectUnordered(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name      string
+		stmt      string
+		unordered string
+	}
+
+	tests := []testCase{
+		{
+			name:      "without order by",
+			stmt:      "select a from t where b > 1",
+			unordered: "select a from t where b>1",
+		},
+		{
+			name:      "order by",
+			stmt:      "select a from t where b > 1 order by b desc, a",
+			unordered: "select a from t where b>1",
+		},
+		{
+			name:      "order by with limit",
+			stmt:      "select a from t order by b desc limit 2",
+			unordered: "select a from t order by b desc limit 2",
+		},
+		{
+			name:      "order by in subquery",
+			stmt:      "select a from (select a, b from t order by b limit 1) s order by a",
+			unordered: "select a from(select a,b from t order by b asc limit 1)as s",
+		},
+	}
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				sel := ast.Statements[0].(*Select)
+				original := sel.String()
+
+				unordered := sel.Unordered()
+				require.Equal(t, tc.unordered, unordered.String())
+				require.Equal(t, original, sel.String())
+
+				_, err = Parse("select a from t where a in (" + unordered.String() + ")")
+				require.NoError(t, err)
+			}
+		}(it))
+	}
+
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t where b > 1 order by a")
+		require.NoError(t, err)
+		sel := ast.Statements[0].(*Select)
+
+		unordered := sel.Unordered()
+		unordered.Where.Expr.(*CmpExpr).Right = &Value{Type: IntValue, Value: []byte("2")}
+		require.Equal(t, "select a from t where b>1 order by a asc", sel.String())
+		require.Equal(t, "select a from t where b>2", unordered.String())
+	})
+}
+
+func TestSelectIsAggregated(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		aggregated bool
+	}
+
+	tests := []testCase{
+		{
+			name:       "plain select",
+			stmt:       "select a, b from t where a > 1",
+			aggregated: false,
+		},
+		{
+			name:       "scalar functions",
+			stmt:       "select abs(a), max(a, b), min(a, 1) from t",
+			aggregated: false,
+		},
+		{
+			name:       "count star",
+			stmt:       "select count(*) from t",
+			aggregated: true,
+		},
+		{
+			name:       "nested aggregate",
+			stmt:       "select a + sum(b) from t",
+			aggregated: true,
+		},
+		{
+			name:       "upper case aggregate",
+			stmt:       "select AVG(a), TOTAL(b), GROUP_CONCAT(a) from t",
+			aggregated: true,
+		},
+		{
+			name:       "max with single argument",
+			stmt:       "select max(a) from t",
+			aggregated: true,
+		},
+		{
+			name:       "group by",
+			stmt:       "select a from t group by a",
+			aggregated: true,
+		},
+		{
+			name:       "having",
+			stmt:       "select a from t having a > 1",
+			aggregated: true,
+		},
+		{
+			name:       "aggregate in subquery",
+			stmt:       "select a, (select count(*) from t2) from t",
+			aggregated: false,
+		},
+		{
+			name:       "aggregate in where subquery",
+			stmt:       "select a from t where a > (select avg(a) from t)",
+			aggregated: false,
+		},
+		{
+			name:       "window function",
+			stmt:       "select a, sum(b) over (partition by a) from t",
+			aggregated: false,
+		},
+		{
+			name:       "aggregate inside window function",
+			stmt:       "select sum(count(*)) over () from t",
+			aggregated: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				sel := ast.Statements[0].(*Select)
+				require.Equal(t, tc.aggregated, sel.IsAggregated())
+
+				// over an empty table, SQLite returns a single row for an aggregated query without GROUP BY
+				if len(sel.GroupBy) == 0 && sel.Having == nil {
+					db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+					require.NoError(t, err)
+					defer func() { require.NoError(t, db.Close()) }()
+					_, err = db.Exec("CREATE TABLE t (a int, b int); CREATE TABLE t2 (a int)")
+					require.NoError(t, err)
+
+					rows := queryRows(t, db, tc.stmt)
+					if tc.aggregated {
+						require.Len(t, rows, 1)
+					} else {
+						require.Len(t, rows, 0)
+					}
+				}
+			}
+		}(tc))
+	}
+}
+
+func TestHavingWithoutGroupBy(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		rows     [][]interface{}
+	}
+
+	tests := []testCase{
+		{
+			name:     "count",
+			stmt:     "SELECT count(*) FROM t HAVING count(*) > 1",
+			deparsed: "select count(*)from t having count(*)>1",
+			rows:     [][]interface{}{{int64(3)}},
+		},
+		{
+			name:     "false condition",
+			stmt:     "SELECT count(*) FROM t HAVING count(*) > 3",
+			deparsed: "select count(*)from t having count(*)>3",
+			rows:     [][]interface{}{},
+		},
+		{
+			name:     "with where",
+			stmt:     "SELECT sum(a) FROM t WHERE a > 1 HAVING sum(a) >= 5",
+			deparsed: "select sum(a)from t where a>1 having sum(a)>=5",
+			rows:     [][]interface{}{{int64(5)}},
+		},
+		{
+			name:     "in subquery",
+			stmt:     "SELECT a FROM t WHERE a IN (SELECT max(a) FROM t HAVING count(*) = 3)",
+			deparsed: "select a from t where a in(select max(a)from t having count(*)=3)",
+			rows:     [][]interface{}{{int64(3)}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int); INSERT INTO t VALUES (1), (2), (3);")
+				require.NoError(t, err)
+
+				require.Equal(t, tc.rows, queryRows(t, db, tc.stmt))
+				require.Equal(t, tc.rows, queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+}
+
+func TestOrderByOrdinal(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "first column",
+			stmt: "select a, b from t order by 1",
+		},
+		{
+			name: "last column desc",
+			stmt: "select a, b from t order by 2 desc, a",
+		},
+		{
+			name: "star",
+			stmt: "select * from t order by 2",
+		},
+		{
+			name: "compound",
+			stmt: "select a, b from t union select b, a from t order by 2",
+		},
+		{
+			name:        "zero",
+			stmt:        "select a, b from t order by 0",
+			expectedErr: &ErrOrderByOrdinalOutOfRange{Ordinal: "0", ColumnsCount: 2},
+		},
+		{
+			name:        "negative",
+			stmt:        "select a, b from t order by -1",
+			expectedErr: &ErrOrderByOrdinalOutOfRange{Ordinal: "-1", ColumnsCount: 2},
+		},
+		{
+			name:        "greater than columns count",
+			stmt:        "select a, b from t order by a, 3",
+			expectedErr: &ErrOrderByOrdinalOutOfRange{Ordinal: "3", ColumnsCount: 2},
+		},
+		{
+			name:        "compound out of range",
+			stmt:        "select a from t union select b from t order by 2",
+			expectedErr: &ErrOrderByOrdinalOutOfRange{Ordinal: "2", ColumnsCount: 1},
+		},
+		{
+			name:        "subquery",
+			stmt:        "select a from t where a in (select b from t order by 2)",
+			expectedErr: &ErrOrderByOrdinalOutOfRange{Ordinal: "2", ColumnsCount: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int, b int)")
+				require.NoError(t, err)
+				_, sqliteErr := db.Exec(tc.stmt)
+
+				_, err = Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.NoError(t, sqliteErr)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrOrderByOrdinalOutOfRange))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				require.ErrorContains(t, sqliteErr, "ORDER BY term out of range")
+			}
+		}(tc))
+	}
+}
+
+func TestGroupByOrdinal(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "in range",
+			stmt: "select a, count(b) from t group by 1",
+		},
+		{
+			name: "mixed with columns",
+			stmt: "select a, b, count(*) from t group by 2, a having count(*) > 1",
+		},
+		{
+			name: "star",
+			stmt: "select * from t group by 2",
+		},
+		{
+			name:        "zero",
+			stmt:        "select a, count(b) from t group by 0",
+			expectedErr: &ErrGroupByOrdinalOutOfRange{Ordinal: "0", ColumnsCount: 2},
+		},
+		{
+			name:        "greater than columns count",
+			stmt:        "select a, count(b) from t group by a, 3",
+			expectedErr: &ErrGroupByOrdinalOutOfRange{Ordinal: "3", ColumnsCount: 2},
+		},
+		{
+			name:        "subquery",
+			stmt:        "select a from t where a in (select max(b) from t group by 2)",
+			expectedErr: &ErrGroupByOrdinalOutOfRange{Ordinal: "2", ColumnsCount: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int, b int)")
+				require.NoError(t, err)
+				_, sqliteErr := db.Exec(tc.stmt)
+
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.NoError(t, sqliteErr)
+
+					// ordinals are kept as integer literals
+					groupBy := ast.Statements[0].(*Select).GroupBy
+					require.Equal(t, &Value{Type: IntValue, Value: []byte(groupBy[0].String())}, groupBy[0])
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrGroupByOrdinalOutOfRange))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				require.ErrorContains(t, sqliteErr, "GROUP BY term out of range")
+			}
+		}(tc))
+	}
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "block comment",
+			stmt:     "SELECT a /* x */ FROM t",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "multiline block comment",
+			stmt:     "SELECT a, /* first\n second */ a+1 FROM t",
+			deparsed: "select a,a+1 from t",
+		},
+		{
+			name:     "block comment without spaces",
+			stmt:     "SELECT a/**/FROM t WHERE a/*x*/-1>0",
+			deparsed: "select a from t where a-1>0",
+		},
+		{
+			name:     "line comment",
+			stmt:     "SELECT a -- x\nFROM t",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "line comment at the end",
+			stmt:     "SELECT a FROM t -- x",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "unterminated block comment",
+			stmt:     "SELECT a FROM t /* x",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "comment markers inside string",
+			stmt:     "SELECT '-- x', '/* y */' FROM t",
+			deparsed: "select '-- x','/* y */' from t",
+		},
+		{
+			name:     "comment between statements",
+			stmt:     "INSERT INTO t VALUES (1); -- first\n/* second */ DELETE FROM t",
+			deparsed: "insert into t values(1);delete from t",
+		},
+		{
+			name:     "double minus starts a comment",
+			stmt:     "SELECT a--1\nFROM t",
+			deparsed: "select a from t",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int)")
+				require.NoError(t, err)
+
+				// SQLite ignores the comments in the same way
+				if _, ok := ast.Statements[0].(*Select); ok {
+					_, err = db.Exec("INSERT INTO t VALUES (1), (2)")
+					require.NoError(t, err)
+					require.Equal(t, queryRows(t, db, tc.deparsed), queryRows(t, db, tc.stmt))
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("only comments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("-- nothing\n/* here */")
+		require.ErrorContains(t, err, "syntax error")
+	})
+}
+
+func TestNotPrecedence(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		where    string
+		deparsed string
+		expected Expr
+	}
+
+	tests := []testCase{
+		{
+			name:     "not column",
+			where:    "NOT a",
+			deparsed: "not a",
+			expected: Not(Col("a")),
+		},
+		{
+			name:     "not in",
+			where:    "NOT a IN (1)",
+			deparsed: "not a in(1)",
+			expected: Not(In(Col("a"), Int(1))),
+		},
+		{
+			name:     "in operator",
+			where:    "a NOT IN (1)",
+			deparsed: "a not in(1)",
+			expected: &CmpExpr{Operator: NotInStr, Left: Col("a"), Right: Exprs{Int(1)}},
+		},
+		{
+			name:     "not between",
+			where:    "NOT a BETWEEN 1 AND 2",
+			deparsed: "not a between 1 and 2",
+			expected: Not(&BetweenExpr{Operator: BetweenStr, Left: Col("a"), From: Int(1), To: Int(2)}),
+		},
+		{
+			name:     "between operator",
+			where:    "a NOT BETWEEN 1 AND 2",
+			deparsed: "a not between 1 and 2",
+			expected: &BetweenExpr{Operator: NotBetweenStr, Left: Col("a"), From: Int(1), To: Int(2)},
+		},
+		{
+			name:     "not like",
+			where:    "NOT a LIKE 'x%'",
+			deparsed: "not a like 'x%'",
+			expected: Not(Like(Col("a"), Str("x%"))),
+		},
+		{
+			name:     "not comparison",
+			where:    "NOT a = 1",
+			deparsed: "not a=1",
+			expected: Not(Eq(Col("a"), Int(1))),
+		},
+		{
+			name:     "not is null",
+			where:    "NOT a IS NULL",
+			deparsed: "not a is null",
+			expected: Not(&IsExpr{Left: Col("a"), Right: Null()}),
+		},
+		{
+			name:     "not and",
+			where:    "NOT a AND b",
+			deparsed: "not a and b",
+			expected: And(Not(Col("a")), Col("b")),
+		},
+		{
+			name:     "not or",
+			where:    "NOT a OR b",
+			deparsed: "not a or b",
+			expected: Or(Not(Col("a")), Col("b")),
+		},
+		{
+			name:     "and not",
+			where:    "a AND NOT b = 1",
+			deparsed: "a and not b=1",
+			expected: And(Col("a"), Not(Eq(Col("b"), Int(1)))),
+		},
+		{
+			name:     "not parenthesized and",
+			where:    "NOT (a AND b)",
+			deparsed: "not(a and b)",
+			expected: Not(Paren(And(Col("a"), Col("b")))),
+		},
+		{
+			name:     "double not",
+			where:    "NOT NOT a",
+			deparsed: "not not a",
+			expected: Not(Not(Col("a"))),
+		},
+		{
+			name:     "not exists",
+			where:    "NOT EXISTS (SELECT 1 FROM t)",
+			deparsed: "not exists(select 1 from t)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				stmt := "SELECT a FROM t WHERE " + tc.where
+				ast, err := Parse(stmt)
+				require.NoError(t, err)
+				where := ast.Statements[0].(*Select).Where
+				require.Equal(t, tc.deparsed, where.Expr.String())
+				if tc.expected != nil {
+					require.Equal(t, tc.expected, where.Expr)
+				}
+
+				// the deparsed statement has the same AST
+				reparsed, err := Parse(ast.String())
+				require.NoError(t, err)
+				require.Equal(t, ast.Statements, reparsed.Statements)
+
+				// and the same result in SQLite
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec(`
+					CREATE TABLE t (a, b);
+					INSERT INTO t VALUES (0, 0), (1, 0), (1, 1), (2, 1), (NULL, 1), ('xy', 0);
+				`)
+				require.NoError(t, err)
+				require.Equal(t, queryRows(t, db, stmt), queryRows(t, db, ast.String()))
+				require.Equal(t, queryRows(t, db, stmt), queryRows(t, db, SimplifyParens(ast).String()))
+			}
+		}(tc))
+	}
+}
+
+func TestFunctionAritiesAgainstSQLite(t *testing.T) {
+	t.Parallel()
+
+	call := func(fname string, argsCount int) string {
+		args := make([]string, argsCount)
+		for i := range args {
+			args[i] = "a"
+		}
+		return fmt.Sprintf("SELECT %s(%s) FROM t", fname, strings.Join(args, ","))
+	}
+
+	// SQLite registers these as variadic and only checks the number of arguments
+	// when evaluating them, so it does not reject calls with too few arguments.
+	checkedAtRuntime := map[string]bool{
+		"json_extract": true,
+		"json_insert":  true,
+		"json_remove":  true,
+		"json_replace": true,
+		"json_set":     true,
+	}
+
+	for fname, arity := range functionArities {
+		t.Run(fname, func(fname string, arity functionArity) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int);")
+				require.NoError(t, err)
+
+				valid := []int{arity.min}
+				if arity.max != -1 {
+					valid = append(valid, arity.max)
+				}
+				for _, argsCount := range valid {
+					stmt := call(fname, argsCount)
+					_, err := Parse(stmt)
+					require.NoError(t, err, stmt)
+
+					_, err = db.Exec(stmt)
+					require.NoError(t, err, stmt)
+				}
+
+				invalid := []int{}
+				if arity.min > 0 {
+					invalid = append(invalid, arity.min-1)
+				}
+				if arity.max != -1 {
+					invalid = append(invalid, arity.max+1)
+				}
+				for _, argsCount := range invalid {
+					stmt := call(fname, argsCount)
+					_, err := Parse(stmt)
+					require.ErrorAs(t, err, new(*ErrWrongNumberOfArguments), stmt)
+
+					if checkedAtRuntime[fname] && argsCount < arity.min {
+						continue
+					}
+					_, err = db.Exec(stmt)
+					require.Error(t, err, stmt)
+				}
+			}
+		}(fname, arity))
 	}
 }
 
-func TestAllowedFunctions(t *testing.T) {
+func TestJSONFunctions(t *testing.T) {
 	t.Parallel()
 
-	genFunctionCallAndArgs := func(fname string) (string, Exprs) {
-		switch fname {
-		case "like":
-			return "like(a,b)", Exprs{
-				&Column{Name: "a"},
-				&Column{Name: "b"},
-			}
-		case "glob":
-			return "glob(a,b)", Exprs{
-				&Column{Name: "a"},
-				&Column{Name: "b"},
-			}
-		default:
-			return fmt.Sprintf("%s(*)", fname), nil
-		}
-	}
-
 	type testCase struct {
-		name        string
-		stmt        string
-		deparsed    string
-		expectedAST *AST
+		name     string
+		stmt     string
+		expected string
 	}
 
-	tests := []testCase{}
-	for allowedFunction, isCustom := range AllowedFunctions {
-		if isCustom {
-			continue
-		}
-		functionCall, args := genFunctionCallAndArgs(allowedFunction)
-		tests = append(tests, testCase{
-			name:     allowedFunction,
-			stmt:     fmt.Sprintf("select %s from t", functionCall),
-			deparsed: fmt.Sprintf("select %sfrom t", functionCall),
-			expectedAST: &AST{
-				Statements: []Statement{
-					&Select{
-						SelectColumnList: SelectColumnList{
-							&AliasedSelectColumn{
-								Expr: &FuncExpr{
-									Name: Identifier(allowedFunction),
-									Args: args,
-								},
-							},
-						},
-						From: &AliasedTableExpr{
-							Expr: &Table{Name: "t", IsTarget: true},
-						},
-					},
-				},
-			},
-		})
+	tests := []testCase{
+		{
+			name:     "json_extract",
+			stmt:     "select json_extract(data,'$.a')from t",
+			expected: "1",
+		},
+		{
+			name:     "json_extract multiple paths",
+			stmt:     "select json_extract(data,'$.a','$.b[0]')from t",
+			expected: "[1,2]",
+		},
+		{
+			name:     "json_array_length",
+			stmt:     "select json_array_length(data,'$.b')from t",
+			expected: "3",
+		},
+		{
+			name:     "json_valid",
+			stmt:     "select json_valid(data)from t",
+			expected: "1",
+		},
+		{
+			name:     "json_type",
+			stmt:     "select json_type(data,'$.b')from t",
+			expected: "array",
+		},
+		{
+			name:     "json_set",
+			stmt:     "select json_set(data,'$.a',5,'$.c','x')from t",
+			expected: `{"a":5,"b":[2,3,4],"c":"x"}`,
+		},
+		{
+			name:     "json_remove",
+			stmt:     "select json_remove(data,'$.b')from t",
+			expected: `{"a":1}`,
+		},
+		{
+			name:     "json_object and json_array",
+			stmt:     "select json_object('x',json_array(1,2))from t",
+			expected: `{"x":[1,2]}`,
+		},
+		{
+			name:     "json_patch",
+			stmt:     `select json_patch(data,'{"a":null}')from t`,
+			expected: `{"b":[2,3,4]}`,
+		},
+		{
+			name:     "json_group_array",
+			stmt:     "select json_group_array(json_extract(data,'$.a'))from t",
+			expected: "[1]",
+		},
+		{
+			name:     "extract operators",
+			stmt:     "select json_quote(data->>'$.a')from t",
+			expected: "1",
+		},
+		{
+			name:     "extract operator with string path",
+			stmt:     "select data->'$.b' from t",
+			expected: "[2,3,4]",
+		},
+		{
+			name:     "unquote extract operator with integer index",
+			stmt:     "select data->'$.b'->>0 from t",
+			expected: "2",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
+
 				ast, err := Parse(tc.stmt)
 				require.NoError(t, err)
-				require.Len(t, ast.Errors, 0)
-				require.Equal(t, tc.expectedAST, ast)
-				require.Equal(t, tc.deparsed, ast.String())
+				require.Equal(t, tc.stmt, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec(`CREATE TABLE t (data text); INSERT INTO t VALUES ('{"a":1,"b":[2,3,4]}');`)
+				require.NoError(t, err)
+
+				var result string
+				require.NoError(t, db.QueryRow(ast.String()).Scan(&result))
+				require.Equal(t, tc.expected, result)
 			}
 		}(tc))
 	}
+
+	t.Run("wrong number of arguments", func(t *testing.T) {
+		t.Parallel()
+
+		for _, stmt := range []string{
+			"select json_extract(data) from t",
+			"select json_set(data, '$.a') from t",
+			"select json_valid(data, 1) from t",
+			"select json_patch(data) from t",
+		} {
+			_, err := Parse(stmt)
+			require.ErrorAs(t, err, new(*ErrWrongNumberOfArguments), stmt)
+		}
+	})
 }
 
 func TestCreateTable(t *testing.T) {
@@ -3776,70 +6858,376 @@ func TestCreateTable(t *testing.T) {
 			},
 		},
 		{
-			name:         "create table replace table constraint with name",
-			stmt:         "CREATE TABLE t(x INTEGER, CONSTRAINT pk PRIMARY KEY (x));",
-			deparsed:     "create table t(x integer constraint pk primary key autoincrement)",
-			expectedHash: "858688370cc6ddf501ebbfe878877e83edeaaf247d1e12faff8bb77ce904e935",
-			expectedAST: &AST{
-				Statements: []Statement{
-					&CreateTable{
-						Table: &Table{Name: "t", IsTarget: true},
-						ColumnsDef: []*ColumnDef{
-							{
-								Column: &Column{Name: "x"},
-								Type:   TypeIntegerStr,
-								Constraints: []ColumnConstraint{
-									&ColumnConstraintPrimaryKey{
-										Name:          "pk",
-										AutoIncrement: true,
-									},
-								},
-							},
-						},
-						Constraints: []TableConstraint{},
-					},
-				},
-			},
+			name:         "create table replace table constraint with name",
+			stmt:         "CREATE TABLE t(x INTEGER, CONSTRAINT pk PRIMARY KEY (x));",
+			deparsed:     "create table t(x integer constraint pk primary key autoincrement)",
+			expectedHash: "858688370cc6ddf501ebbfe878877e83edeaaf247d1e12faff8bb77ce904e935",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&CreateTable{
+						Table: &Table{Name: "t", IsTarget: true},
+						ColumnsDef: []*ColumnDef{
+							{
+								Column: &Column{Name: "x"},
+								Type:   TypeIntegerStr,
+								Constraints: []ColumnConstraint{
+									&ColumnConstraintPrimaryKey{
+										Name:          "pk",
+										AutoIncrement: true,
+									},
+								},
+							},
+						},
+						Constraints: []TableConstraint{},
+					},
+				},
+			},
+		},
+		{
+			name:         "create table replace table constraint middle",
+			stmt:         "CREATE TABLE t(x INTEGER NOT NULL, b INT, check(b>0), PRIMARY KEY (x DESC), UNIQUE (b));",
+			deparsed:     "create table t(x integer not null primary key desc,b int,check(b>0),unique(b))",
+			expectedHash: "783f0ed7cc77247b44f69325d3c865b05ebdbc9c8087d367d685ea63af87fdbd",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&CreateTable{
+						Table: &Table{Name: "t", IsTarget: true},
+						ColumnsDef: []*ColumnDef{
+							{
+								Column: &Column{Name: "x"},
+								Type:   TypeIntegerStr,
+								Constraints: []ColumnConstraint{
+									&ColumnConstraintNotNull{},
+									&ColumnConstraintPrimaryKey{
+										Name:          "",
+										AutoIncrement: false,
+										Order:         "desc",
+									},
+								},
+							},
+							{
+								Column:      &Column{Name: "b"},
+								Type:        TypeIntStr,
+								Constraints: []ColumnConstraint{},
+							},
+						},
+						Constraints: []TableConstraint{
+							&TableConstraintCheck{
+								Expr: &CmpExpr{
+									Operator: GreaterThanStr,
+									Left:     &Column{Name: "b"},
+									Right:    &Value{Type: IntValue, Value: []byte("0")},
+								},
+							},
+							&TableConstraintUnique{
+								Columns: ColumnList{
+									&Column{Name: "b"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, tc.expectedHash, ast.Statements[0].(*CreateTable).StructureHash())
+				require.Equal(t, tc.expectedAST, ast)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				// test all CREATE statements against SQLite3
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+
+				_, err = db.Exec(tc.stmt)
+				require.NoError(t, err)
+
+				_, err = db.Exec(fmt.Sprintf("DROP TABLE %s", ast.Statements[0].(*CreateTable).Table.Name))
+				require.NoError(t, err)
+
+				// test AST SQL generation against SQLite3
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+				require.NoError(t, db.Close())
+			}
+		}(tc))
+	}
+}
+
+func TestSchemaHash(t *testing.T) {
+	t.Parallel()
+
+	ast, err := Parse("create table t (a int primary key, b text not null, c blob)")
+	require.NoError(t, err)
+	create := ast.Statements[0].(*CreateTable)
+
+	t.Run("matches structure hash", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, create.StructureHash(), SchemaHash(create.ColumnsDef))
+	})
+
+	t.Run("columns built elsewhere", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []*ColumnDef{
+			{Column: &Column{Name: "a"}, Type: TypeIntStr},
+			{Column: &Column{Name: "b"}, Type: TypeTextStr},
+			{Column: &Column{Name: "c"}, Type: TypeBlobStr},
+		}
+		require.Equal(t, create.StructureHash(), SchemaHash(columns))
+	})
+
+	t.Run("constraints and type casing are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []*ColumnDef{
+			{Column: &Column{Name: "a"}, Type: "INT"},
+			{Column: &Column{Name: "b"}, Type: "Text"},
+			{Column: &Column{Name: "c"}, Type: TypeBlobStr, Constraints: []ColumnConstraint{&ColumnConstraintNotNull{}}},
+		}
+		require.Equal(t, create.StructureHash(), SchemaHash(columns))
+	})
+
+	t.Run("altered schema", func(t *testing.T) {
+		t.Parallel()
+
+		altered, err := Parse("create table t (a int primary key, b text not null, c blob, d integer)")
+		require.NoError(t, err)
+
+		alter, err := Parse("alter table t add column d integer")
+		require.NoError(t, err)
+		column := alter.Statements[0].(*AlterTable).AlterTableClause.(*AlterTableAdd).ColumnDef
+
+		columns := append(append([]*ColumnDef{}, create.ColumnsDef...), column)
+		require.Equal(t, altered.Statements[0].(*CreateTable).StructureHash(), SchemaHash(columns))
+		require.NotEqual(t, create.StructureHash(), SchemaHash(columns))
+	})
+
+	t.Run("order matters", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []*ColumnDef{create.ColumnsDef[1], create.ColumnsDef[0], create.ColumnsDef[2]}
+		require.NotEqual(t, create.StructureHash(), SchemaHash(columns))
+	})
+}
+
+func TestCreateTableApply(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		create      string
+		alter       string
+		expected    string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "add column",
+			create:   "create table t (a int primary key, b text)",
+			alter:    "alter table t add column c integer not null default 1",
+			expected: "create table t (a int primary key, b text, c integer not null default 1)",
+		},
+		{
+			name:     "drop column",
+			create:   "create table t (a int primary key, b text, c blob)",
+			alter:    "alter table t drop column b",
+			expected: "create table t (a int primary key, c blob)",
+		},
+		{
+			name:     "drop last column",
+			create:   "create table t (a int primary key, b text)",
+			alter:    "alter table t drop b",
+			expected: "create table t (a int primary key)",
+		},
+		{
+			name:     "rename column",
+			create:   "create table t (a int, b text check (length(b) > 1), primary key (a), check (a > 0))",
+			alter:    "alter table t rename column a to x",
+			expected: "create table t (x int, b text check (length(b) > 1), primary key (x), check (x > 0))",
+		},
+		{
+			name:     "rename quoted column",
+			create:   "create table t (\"a\" int, b text)",
+			alter:    "alter table [t] rename A to [y]",
+			expected: "create table t ([y] int, b text)",
+		},
+		{
+			name:        "drop nonexistent column",
+			create:      "create table t (a int, b text)",
+			alter:       "alter table t drop column c",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "c"},
+		},
+		{
+			name:        "drop primary key column",
+			create:      "create table t (a int primary key, b text)",
+			alter:       "alter table t drop column a",
+			expectedErr: &ErrDropColumnInUse{Column: "a", Constraint: "primary key"},
+		},
+		{
+			name:        "drop unique column",
+			create:      "create table t (a int, b text unique)",
+			alter:       "alter table t drop column b",
+			expectedErr: &ErrDropColumnInUse{Column: "b", Constraint: "unique"},
+		},
+		{
+			name:        "drop column in table constraint",
+			create:      "create table t (a int, b text, c int, unique (a, c))",
+			alter:       "alter table t drop column c",
+			expectedErr: &ErrDropColumnInUse{Column: "c", Constraint: "unique(a,c)"},
 		},
 		{
-			name:         "create table replace table constraint middle",
-			stmt:         "CREATE TABLE t(x INTEGER NOT NULL, b INT, check(b>0), PRIMARY KEY (x DESC), UNIQUE (b));",
-			deparsed:     "create table t(x integer not null primary key desc,b int,check(b>0),unique(b))",
-			expectedHash: "783f0ed7cc77247b44f69325d3c865b05ebdbc9c8087d367d685ea63af87fdbd",
+			name:        "drop column in table check",
+			create:      "create table t (a int, b text, check (a > 0))",
+			alter:       "alter table t drop a",
+			expectedErr: &ErrDropColumnInUse{Column: "a", Constraint: "check(a>0)"},
+		},
+		{
+			name:        "drop column in another column check",
+			create:      "create table t (a int, b text check (length(b) > a))",
+			alter:       "alter table t drop a",
+			expectedErr: &ErrDropColumnInUse{Column: "a", Constraint: "check(length(b)>a)"},
+		},
+		{
+			name:     "drop column with its own check",
+			create:   "create table t (a int, b text check (length(b) > 1))",
+			alter:    "alter table t drop b",
+			expected: "create table t (a int)",
+		},
+		{
+			name:        "rename nonexistent column",
+			create:      "create table t (a int, b text)",
+			alter:       "alter table t rename c to d",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "c"},
+		},
+		{
+			name:        "rename to existing column",
+			create:      "create table t (a int, b text)",
+			alter:       "alter table t rename a to B",
+			expectedErr: &ErrDuplicateColumnName{Name: "B"},
+		},
+		{
+			name:        "add existing column",
+			create:      "create table t (a int, b text)",
+			alter:       "alter table t add a text",
+			expectedErr: &ErrDuplicateColumnName{Name: "a"},
+		},
+		{
+			name:        "different table",
+			create:      "create table t (a int, b text)",
+			alter:       "alter table t2 drop b",
+			expectedErr: &ErrUnknownTable{Name: "t2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.create)
+				require.NoError(t, err)
+				create := ast.Statements[0].(*CreateTable)
+				original := create.String()
+
+				alterAST, err := Parse(tc.alter)
+				require.NoError(t, err)
+				alter := alterAST.Statements[0].(*AlterTable)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec(tc.create)
+				require.NoError(t, err)
+
+				err = create.Apply(alter)
+				if tc.expectedErr != nil {
+					require.Equal(t, tc.expectedErr, err)
+					require.Equal(t, original, create.String())
+
+					// SQLite rejects the change too
+					_, err = db.Exec(tc.alter)
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				expected, err := Parse(tc.expected)
+				require.NoError(t, err)
+				require.Equal(t, expected.String(), create.String())
+				require.Equal(t, expected.Statements[0].(*CreateTable).StructureHash(), create.StructureHash())
+
+				// SQLite ends up with the same columns
+				_, err = db.Exec(tc.alter)
+				require.NoError(t, err)
+
+				columns := [][]interface{}{}
+				for _, column := range create.ColumnsDef {
+					columns = append(columns, []interface{}{normalizeIdentifier(column.Column.Name), column.Type})
+				}
+				require.Equal(t, columns, queryRows(t, db, "SELECT lower(name), lower(type) FROM pragma_table_info('t')"))
+			}
+		}(tc))
+	}
+
+	t.Run("added column is not shared", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("create table t (a int)")
+		require.NoError(t, err)
+		create := ast.Statements[0].(*CreateTable)
+
+		alterAST, err := Parse("alter table t add b text")
+		require.NoError(t, err)
+		alter := alterAST.Statements[0].(*AlterTable)
+
+		require.NoError(t, create.Apply(alter))
+		alter.AlterTableClause.(*AlterTableAdd).ColumnDef.Type = TypeBlobStr
+		require.Equal(t, "create table t(a int,b text)", create.String())
+	})
+}
+
+func TestCreateView(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedAST *AST
+	}
+
+	tests := []testCase{
+		{
+			name:     "simple",
+			stmt:     "CREATE VIEW v AS SELECT a, b FROM t WHERE a > 1",
+			deparsed: "create view v as select a,b from t where a>1",
 			expectedAST: &AST{
 				Statements: []Statement{
-					&CreateTable{
-						Table: &Table{Name: "t", IsTarget: true},
-						ColumnsDef: []*ColumnDef{
-							{
-								Column: &Column{Name: "x"},
-								Type:   TypeIntegerStr,
-								Constraints: []ColumnConstraint{
-									&ColumnConstraintNotNull{},
-									&ColumnConstraintPrimaryKey{
-										Name:          "",
-										AutoIncrement: false,
-										Order:         "desc",
-									},
-								},
-							},
-							{
-								Column:      &Column{Name: "b"},
-								Type:        TypeIntStr,
-								Constraints: []ColumnConstraint{},
+					&CreateView{
+						View:    &Table{Name: "v", IsTarget: true},
+						Columns: ColumnList{},
+						Select: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{Expr: &Column{Name: "a"}},
+								&AliasedSelectColumn{Expr: &Column{Name: "b"}},
 							},
-						},
-						Constraints: []TableConstraint{
-							&TableConstraintCheck{
+							From: &AliasedTableExpr{Expr: &Table{Name: "t", IsTarget: true}},
+							Where: &Where{
+								Type: WhereStr,
 								Expr: &CmpExpr{
 									Operator: GreaterThanStr,
-									Left:     &Column{Name: "b"},
-									Right:    &Value{Type: IntValue, Value: []byte("0")},
-								},
-							},
-							&TableConstraintUnique{
-								Columns: ColumnList{
-									&Column{Name: "b"},
+									Left:     &Column{Name: "a"},
+									Right:    &Value{Type: IntValue, Value: []byte("1")},
 								},
 							},
 						},
@@ -3847,36 +7235,133 @@ func TestCreateTable(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "columns",
+			stmt:     "create view [v] (x, \"y\") as select a, count(*) from t group by a",
+			deparsed: "create view [v](x,\"y\")as select a,count(*)from t group by a",
+		},
+		{
+			name:     "compound select",
+			stmt:     "CREATE VIEW v AS SELECT a FROM t UNION SELECT b FROM t2 ORDER BY 1",
+			deparsed: "create view v as select a from t union select b from t2 order by 1 asc",
+		},
+		{
+			name:     "join and subquery",
+			stmt:     "CREATE VIEW v AS SELECT t.a, s.b FROM t JOIN (SELECT a, b FROM t2) AS s ON t.a = s.a",
+			deparsed: "create view v as select t.a,s.b from t join(select a,b from t2)as s on t.a=s.a",
+		},
+		{
+			name:     "values",
+			stmt:     "CREATE VIEW v (a, b) AS VALUES (1, 2)",
+			deparsed: "create view v(a,b)as values(1,2)",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
+
 				ast, err := Parse(tc.stmt)
 				require.NoError(t, err)
-				require.Len(t, ast.Errors, 0)
-				require.Equal(t, tc.expectedHash, ast.Statements[0].(*CreateTable).StructureHash())
-				require.Equal(t, tc.expectedAST, ast)
+				if tc.expectedAST != nil {
+					require.Equal(t, tc.expectedAST, ast)
+				}
 				require.Equal(t, tc.deparsed, ast.String())
 
-				// test all CREATE statements against SQLite3
-				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				reparsed, err := Parse(ast.String())
 				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, reparsed.String())
 
-				_, err = db.Exec(tc.stmt)
-				require.NoError(t, err)
+				// the original and the deparsed statements create the same view in SQLite
+				for _, stmt := range []string{tc.stmt, ast.String()} {
+					db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+					require.NoError(t, err)
+					_, err = db.Exec(`
+						CREATE TABLE t (a int, b int);
+						CREATE TABLE t2 (a int, b int);
+						INSERT INTO t VALUES (1, 1), (2, 2);
+						INSERT INTO t2 VALUES (2, 3);
+					`)
+					require.NoError(t, err)
+					_, err = db.Exec(stmt)
+					require.NoError(t, err)
+					require.NotEmpty(t, queryRows(t, db, "SELECT * FROM "+ast.Statements[0].(*CreateView).View.String()))
+					require.NoError(t, db.Close())
+				}
+			}
+		}(tc))
+	}
 
-				_, err = db.Exec(fmt.Sprintf("DROP TABLE %s", ast.Statements[0].(*CreateTable).Table.Name))
-				require.NoError(t, err)
+	t.Run("view as identifier", func(t *testing.T) {
+		t.Parallel()
 
-				// test AST SQL generation against SQLite3
-				_, err = db.Exec(ast.String())
-				require.NoError(t, err)
-				require.NoError(t, db.Close())
+		ast, err := Parse("CREATE TABLE view (view INT)")
+		require.NoError(t, err)
+		require.Equal(t, "create table view(view int)", ast.String())
+	})
+
+	t.Run("custom functions", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("CREATE VIEW v AS SELECT block_num(1) FROM t")
+		require.ErrorAs(t, err, new(*ErrCustomFuncInDDL))
+	})
+
+	t.Run("multiple statements", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("CREATE VIEW v AS SELECT a FROM t; INSERT INTO t VALUES (1)")
+		require.ErrorAs(t, err, new(*ErrSyntaxError))
+	})
+}
+
+func TestCreateTrigger(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name string
+		stmt string
+	}
+
+	tests := []testCase{
+		{
+			name: "create trigger",
+			stmt: "CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM t2; END",
+		},
+		{
+			name: "lower case",
+			stmt: "create trigger if not exists tr before delete on t begin select 1; end;",
+		},
+		{
+			name: "quoted name",
+			stmt: "CREATE TRIGGER [tr] AFTER UPDATE OF a ON t BEGIN UPDATE t2 SET b = new.a; END",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.Nil(t, ast)
+				require.Equal(t, &ErrTriggersNotSupported{}, err)
 			}
 		}(tc))
 	}
+
+	t.Run("trigger as identifier", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("CREATE TABLE trigger (trigger INT)")
+		require.NoError(t, err)
+		require.Equal(t, "create table trigger(trigger int)", ast.String())
+
+		ast, err = Parse("SELECT trigger FROM t AS trigger")
+		require.NoError(t, err)
+		require.Equal(t, "select trigger from t as trigger", ast.String())
+	})
 }
 
 func TestCreateTableStrict(t *testing.T) {
@@ -3921,14 +7406,94 @@ func TestCreateTableAutoIncrementRules(t *testing.T) {
 			"create table t(a int primary key)",
 		},
 		{
-			"integer table primary key forces autoincrement",
-			"CREATE TABLE t (a INTEGER, PRIMARY KEY(a ASC))",
-			"create table t(a integer primary key asc autoincrement)",
+			"integer table primary key forces autoincrement",
+			"CREATE TABLE t (a INTEGER, PRIMARY KEY(a ASC))",
+			"create table t(a integer primary key asc autoincrement)",
+		},
+		{
+			"explicit autoincrement",
+			"CREATE TABLE t (a INTEGER PRIMARY KEY AUTOINCREMENT)",
+			"create table t(a integer primary key autoincrement)",
+		},
+		{
+			"integer table primary key desc",
+			"CREATE TABLE t (a INTEGER, PRIMARY KEY(a DESC))",
+			"create table t(a integer primary key desc)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				// test all CREATE statements against SQLite3
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+
+				_, err = db.Exec(tc.stmt)
+				require.NoError(t, err)
+
+				_, err = db.Exec(fmt.Sprintf("DROP TABLE %s", ast.Statements[0].(*CreateTable).Table.Name))
+				require.NoError(t, err)
+
+				// test AST SQL generation against SQLite3
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+				require.NoError(t, db.Close())
+			}
+		}(tc))
+	}
+}
+
+func TestCreateTablePrimaryKeyColumns(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		stmt    string
+		columns []string
+	}
+
+	tests := []testCase{
+		{
+			name:    "no primary key",
+			stmt:    "CREATE TABLE t (a INT, b TEXT)",
+			columns: []string{},
+		},
+		{
+			name:    "inline",
+			stmt:    "CREATE TABLE t (a INT, b TEXT PRIMARY KEY)",
+			columns: []string{"b"},
+		},
+		{
+			name:    "inline integer with forced autoincrement",
+			stmt:    "CREATE TABLE t (a INTEGER PRIMARY KEY, b TEXT)",
+			columns: []string{"a"},
+		},
+		{
+			name:    "table constraint",
+			stmt:    "CREATE TABLE t (a INT, b TEXT, PRIMARY KEY (b))",
+			columns: []string{"b"},
+		},
+		{
+			name:    "table constraint with multiple columns",
+			stmt:    "CREATE TABLE t (a INT, b TEXT, c INT, PRIMARY KEY (c, a))",
+			columns: []string{"c", "a"},
 		},
 		{
-			"integer table primary key desc",
-			"CREATE TABLE t (a INTEGER, PRIMARY KEY(a DESC))",
-			"create table t(a integer primary key desc)",
+			name:    "table constraint on integer normalized to column constraint",
+			stmt:    "CREATE TABLE t (a TEXT, b INTEGER, PRIMARY KEY (b ASC))",
+			columns: []string{"b"},
+		},
+		{
+			name:    "table constraint on integer desc",
+			stmt:    "CREATE TABLE t (a TEXT, b INTEGER, PRIMARY KEY (b DESC))",
+			columns: []string{"b"},
 		},
 	}
 
@@ -3936,30 +7501,55 @@ func TestCreateTableAutoIncrementRules(t *testing.T) {
 		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
+
 				ast, err := Parse(tc.stmt)
 				require.NoError(t, err)
-				require.Len(t, ast.Errors, 0)
-				require.Equal(t, tc.deparsed, ast.String())
+				require.Equal(t, tc.columns, ast.Statements[0].(*CreateTable).PrimaryKeyColumns())
 
-				// test all CREATE statements against SQLite3
 				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
 				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
 
-				_, err = db.Exec(tc.stmt)
-				require.NoError(t, err)
-
-				_, err = db.Exec(fmt.Sprintf("DROP TABLE %s", ast.Statements[0].(*CreateTable).Table.Name))
-				require.NoError(t, err)
-
-				// test AST SQL generation against SQLite3
 				_, err = db.Exec(ast.String())
 				require.NoError(t, err)
-				require.NoError(t, db.Close())
+
+				columns := []string{}
+				for _, row := range queryRows(t, db, "select name from pragma_table_info('t') where pk > 0 order by pk") {
+					columns = append(columns, row[0].(string))
+				}
+				require.Equal(t, tc.columns, columns)
 			}
 		}(tc))
 	}
 }
 
+func TestAutoIncrementNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	stmts := []string{
+		"CREATE TABLE t (a TEXT PRIMARY KEY AUTOINCREMENT)",
+		"CREATE TABLE t (a INTEGER PRIMARY KEY DESC AUTOINCREMENT)",
+	}
+
+	for _, stmt := range stmts {
+		t.Run(stmt, func(stmt string) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				_, err := Parse(stmt)
+				require.Error(t, err)
+				require.ErrorAs(t, err, new(*ErrAutoIncrementNotAllowed))
+			}
+		}(stmt))
+	}
+
+	t.Run("not autoincrement", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse("CREATE TABLE t (a INTEGER PRIMARY KEY foo)")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "syntax error")
+	})
+}
+
 func TestInsert(t *testing.T) {
 	t.Parallel()
 
@@ -4265,11 +7855,388 @@ func TestInsert(t *testing.T) {
 			},
 		},
 		{
-			name:        "upsert multiple clauses missing target",
-			stmt:        "INSERT INTO t (id) VALUES (1) ON CONFLICT DO NOTHING ON CONFLICT DO NOTHING;",
-			deparsed:    "insert into t(id)values(1)on conflict do nothing on conflict do nothing",
-			expectedAST: nil,
-			expectedErr: &ErrUpsertMissingTarget{},
+			name:     "upsert partial index target",
+			stmt:     "INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) WHERE a > 0 DO NOTHING;",
+			deparsed: "insert into t(a,b)values(1,2)on conflict(a)where a>0 do nothing",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Insert{
+						Table: &Table{Name: "t", IsTarget: true},
+						Columns: ColumnList{
+							&Column{Name: "a"},
+							&Column{Name: "b"},
+						},
+						Rows: []Exprs{
+							{
+								&Value{Type: IntValue, Value: []byte("1")},
+								&Value{Type: IntValue, Value: []byte("2")},
+							},
+						},
+						Upsert: Upsert{
+							&OnConflictClause{
+								Target: &OnConflictTarget{
+									Columns: []*Column{
+										{Name: "a"},
+									},
+									Where: &Where{
+										Type: WhereStr,
+										Expr: &CmpExpr{
+											Operator: GreaterThanStr,
+											Left:     &Column{Name: "a"},
+											Right:    &Value{Type: IntValue, Value: []byte("0")},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "upsert partial index target do update with where",
+			stmt:     "INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) WHERE a > 0 DO UPDATE SET b = excluded.b WHERE b < 10;",
+			deparsed: "insert into t(a,b)values(1,2)on conflict(a)where a>0 do update set b=excluded.b where b<10",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Insert{
+						Table: &Table{Name: "t", IsTarget: true},
+						Columns: ColumnList{
+							&Column{Name: "a"},
+							&Column{Name: "b"},
+						},
+						Rows: []Exprs{
+							{
+								&Value{Type: IntValue, Value: []byte("1")},
+								&Value{Type: IntValue, Value: []byte("2")},
+							},
+						},
+						Upsert: Upsert{
+							&OnConflictClause{
+								Target: &OnConflictTarget{
+									Columns: []*Column{
+										{Name: "a"},
+									},
+									Where: &Where{
+										Type: WhereStr,
+										Expr: &CmpExpr{
+											Operator: GreaterThanStr,
+											Left:     &Column{Name: "a"},
+											Right:    &Value{Type: IntValue, Value: []byte("0")},
+										},
+									},
+								},
+								DoUpdate: &OnConflictUpdate{
+									Exprs: []*UpdateExpr{
+										{
+											Column: &Column{Name: "b"},
+											Expr: &Column{
+												TableRef: &Table{Name: "excluded"},
+												Name:     "b",
+											},
+										},
+									},
+									Where: &Where{
+										Type: WhereStr,
+										Expr: &CmpExpr{
+											Operator: LessThanStr,
+											Left:     &Column{Name: "b"},
+											Right:    &Value{Type: IntValue, Value: []byte("10")},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "upsert multiple clauses missing target",
+			stmt:        "INSERT INTO t (id) VALUES (1) ON CONFLICT DO NOTHING ON CONFLICT DO NOTHING;",
+			deparsed:    "insert into t(id)values(1)on conflict do nothing on conflict do nothing",
+			expectedAST: nil,
+			expectedErr: &ErrUpsertMissingTarget{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.Len(t, ast.Errors, 0)
+					require.Equal(t, tc.expectedAST, ast)
+					require.Equal(t, tc.deparsed, ast.String())
+				} else {
+					require.ErrorAs(t, ast.Errors[0], &tc.expectedErr)
+				}
+			}
+		}(tc))
+	}
+}
+
+func TestInsertArityMismatch(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "matching rows",
+			stmt: "insert into t (a, b) values (1, 2), (3, 4)",
+		},
+		{
+			name: "without columns",
+			stmt: "insert into t values (1, 2)",
+		},
+		{
+			name:        "fewer values",
+			stmt:        "insert into t (a, b) values (1)",
+			expectedErr: &ErrInsertArityMismatch{RowIndex: 0, ColumnsCount: 2, ValuesCount: 1},
+		},
+		{
+			name:        "more values in a later row",
+			stmt:        "insert into t (a, b) values (1, 2), (3, 4, 5)",
+			expectedErr: &ErrInsertArityMismatch{RowIndex: 1, ColumnsCount: 2, ValuesCount: 3},
+		},
+		{
+			name:        "upsert",
+			stmt:        "insert into t (a) values (1, 2) on conflict do nothing",
+			expectedErr: &ErrInsertArityMismatch{RowIndex: 0, ColumnsCount: 1, ValuesCount: 2},
+		},
+		{
+			name: "matching rows without columns",
+			stmt: "insert into t values (1, 2), (3, 4), (5, 6)",
+		},
+		{
+			name:        "fewer values without columns",
+			stmt:        "insert into t values (1, 2), (3)",
+			expectedErr: &ErrInsertArityMismatch{RowIndex: 1, ColumnsCount: 2, ValuesCount: 1},
+		},
+		{
+			name:        "more values without columns",
+			stmt:        "insert into t values (1), (2), (3, 4)",
+			expectedErr: &ErrInsertArityMismatch{RowIndex: 2, ColumnsCount: 1, ValuesCount: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, err = db.Exec("CREATE TABLE t (a int, b int)")
+				require.NoError(t, err)
+				_, sqliteErr := db.Exec(tc.stmt)
+
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.NoError(t, sqliteErr)
+					return
+				}
+				require.Len(t, ast.Errors, 1)
+				require.ErrorAs(t, err, new(*ErrInsertArityMismatch))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				require.Error(t, sqliteErr)
+			}
+		}(tc))
+	}
+}
+
+func TestInsertDefaultKeyword(t *testing.T) {
+	t.Parallel()
+
+	opts := ParseOptions{AllowDefaultKeyword: true}
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "single row",
+			stmt:     "INSERT INTO t (a, b) VALUES (1, DEFAULT)",
+			deparsed: "insert into t(a,b)values(1,default)",
+		},
+		{
+			name:     "all columns",
+			stmt:     "insert into t values (default, default)",
+			deparsed: "insert into t values(default,default)",
+		},
+		{
+			name:     "upsert",
+			stmt:     "insert into t (a, b) values (1, default) on conflict (a) do update set b = excluded.b",
+			deparsed: "insert into t(a,b)values(1,default)on conflict(a)do update set b=excluded.b",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := ParseWithOptions(tc.stmt, opts)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				reparsed, err := ParseWithOptions(ast.String(), opts)
+				require.NoError(t, err)
+				require.Equal(t, ast, reparsed)
+			}
+		}(tc))
+	}
+
+	t.Run("ast", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := ParseWithOptions("INSERT INTO t (a, b) VALUES (1, DEFAULT), (DEFAULT, 2);", opts)
+		require.NoError(t, err)
+		require.Equal(t, []Exprs{
+			{&Value{Type: IntValue, Value: []byte("1")}, &DefaultExpr{}},
+			{&DefaultExpr{}, &Value{Type: IntValue, Value: []byte("2")}},
+		}, ast.Statements[0].(*Insert).Rows)
+	})
+
+	t.Run("not allowed by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("insert into t (a, b) values (1, default)")
+		require.ErrorAs(t, err, new(*ErrDefaultKeywordNotAllowed))
+
+		_, err = ParseTableland("insert into t_1_1 (a, b) values (1, default)", 1)
+		require.ErrorAs(t, err, new(*ErrDefaultKeywordNotAllowed))
+	})
+
+	t.Run("not a value outside insert rows", func(t *testing.T) {
+		t.Parallel()
+
+		for _, stmt := range []string{
+			"select a from t where a = default",
+			"values (1, default)",
+			"update t set a = default",
+			"insert into t (a) select default",
+		} {
+			_, err := ParseWithOptions(stmt, opts)
+			require.ErrorAs(t, err, new(*ErrSyntaxError), stmt)
+		}
+	})
+
+	// SQLite doesn't support the DEFAULT keyword as a value, so the statement must be rewritten before executing it.
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+
+		_, err = db.Exec("CREATE TABLE t (a int unique, b int default 5)")
+		require.NoError(t, err)
+
+		ast, err := ParseWithOptions("insert into t (a, b) values (1, default)", opts)
+		require.NoError(t, err)
+		_, err = db.Exec(ast.String())
+		require.ErrorContains(t, err, "syntax error")
+	})
+}
+
+func TestUpsertPartialIndexTarget(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	_, err = db.Exec(`
+		CREATE TABLE t (a int, b int);
+		CREATE UNIQUE INDEX t_a_positive ON t (a) WHERE a > 0;
+		INSERT INTO t VALUES (1, 1);
+	`)
+	require.NoError(t, err)
+
+	ast, err := Parse("INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) WHERE a > 0 DO UPDATE SET b = excluded.b;")
+	require.NoError(t, err)
+	require.Len(t, ast.Errors, 0)
+
+	_, err = db.Exec(ast.String())
+	require.NoError(t, err)
+
+	var b int
+	require.NoError(t, db.QueryRow("SELECT b FROM t WHERE a = 1").Scan(&b))
+	require.Equal(t, 2, b)
+}
+
+func TestExcludedOutsideUpsert(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		stmt    string
+		allowed bool
+	}
+
+	tests := []testCase{
+		{
+			name:    "upsert do update set",
+			stmt:    "insert into t (a, b) values (1, 2) on conflict (a) do update set b = excluded.b",
+			allowed: true,
+		},
+		{
+			name:    "upsert do update where",
+			stmt:    "insert into t (a, b) values (1, 2) on conflict (a) do update set b = 1 where EXCLUDED.b > 0",
+			allowed: true,
+		},
+		{
+			name:    "insert values",
+			stmt:    "insert into t (a, b) values (1, excluded.b)",
+			allowed: false,
+		},
+		{
+			name:    "upsert conflict target where",
+			stmt:    "insert into t (a, b) values (1, 2) on conflict (a) where excluded.a > 0 do nothing",
+			allowed: false,
+		},
+		{
+			name:    "update",
+			stmt:    "update t set a = excluded.a",
+			allowed: false,
+		},
+		{
+			name:    "delete",
+			stmt:    "delete from t where a = excluded.a",
+			allowed: false,
+		},
+		{
+			name:    "select",
+			stmt:    "select excluded.a from t",
+			allowed: false,
+		},
+		{
+			name:    "insert with select",
+			stmt:    "insert into t select excluded.a from t2",
+			allowed: false,
+		},
+		{
+			name:    "alias named excluded",
+			stmt:    "select excluded.a from t as excluded",
+			allowed: true,
+		},
+		{
+			name:    "table named excluded",
+			stmt:    "delete from excluded where excluded.a = 1",
+			allowed: true,
 		},
 	}
 
@@ -4277,14 +8244,12 @@ func TestInsert(t *testing.T) {
 		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
-				ast, err := Parse(tc.stmt)
-				if tc.expectedErr == nil {
+				_, err := Parse(tc.stmt)
+				if tc.allowed {
 					require.NoError(t, err)
-					require.Len(t, ast.Errors, 0)
-					require.Equal(t, tc.expectedAST, ast)
-					require.Equal(t, tc.deparsed, ast.String())
 				} else {
-					require.ErrorAs(t, ast.Errors[0], &tc.expectedErr)
+					require.Error(t, err)
+					require.ErrorAs(t, err, new(*ErrExcludedOutsideUpsert))
 				}
 			}
 		}(tc))
@@ -4520,6 +8485,40 @@ func TestGrant(t *testing.T) {
 	}
 }
 
+func TestGrantTriples(t *testing.T) {
+	t.Parallel()
+
+	t.Run("grant", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("GRANT UPDATE, INSERT, DELETE on t_1_2 TO 'b', 'a'")
+		require.NoError(t, err)
+
+		require.Equal(t, []GrantTriple{
+			{Role: "b", Table: "t_1_2", Privilege: "delete"},
+			{Role: "b", Table: "t_1_2", Privilege: "insert"},
+			{Role: "b", Table: "t_1_2", Privilege: "update"},
+			{Role: "a", Table: "t_1_2", Privilege: "delete"},
+			{Role: "a", Table: "t_1_2", Privilege: "insert"},
+			{Role: "a", Table: "t_1_2", Privilege: "update"},
+		}, ast.Statements[0].(*Grant).Triples())
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("REVOKE INSERT, DELETE on t_1_2 FROM 'a', 'b'")
+		require.NoError(t, err)
+
+		require.Equal(t, []GrantTriple{
+			{Role: "a", Table: "t_1_2", Privilege: "delete"},
+			{Role: "a", Table: "t_1_2", Privilege: "insert"},
+			{Role: "b", Table: "t_1_2", Privilege: "delete"},
+			{Role: "b", Table: "t_1_2", Privilege: "insert"},
+		}, ast.Statements[0].(*Revoke).Triples())
+	})
+}
+
 func TestMultipleStatements(t *testing.T) {
 	t.Parallel()
 
@@ -4620,15 +8619,484 @@ func TestMultipleStatements(t *testing.T) {
 		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
 			return func(t *testing.T) {
 				t.Parallel()
-				ast, err := Parse(tc.stmt)
-				if tc.expectedErrMsg == "" {
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+					require.Len(t, ast.Errors, 0)
+					require.Equal(t, tc.expectedAST, ast)
+					require.Equal(t, tc.deparsed, ast.String())
+				} else {
+					require.Contains(t, err.Error(), tc.expectedErrMsg)
+				}
+			}
+		}(tc))
+	}
+}
+
+func TestTrailingSemicolons(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name           string
+		stmt           string
+		deparsed       string
+		expectedErrMsg string
+	}
+
+	tests := []testCase{
+		{
+			name:     "select trailing semicolon",
+			stmt:     "select a from t;",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "select trailing semicolon and whitespace",
+			stmt:     "select a from t; \n",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "select empty statements",
+			stmt:     "select a from t;;",
+			deparsed: "select a from t",
+		},
+		{
+			name:     "create empty statements",
+			stmt:     "create table t (a int);; ;",
+			deparsed: "create table t(a int)",
+		},
+		{
+			name:     "insert empty statements",
+			stmt:     "insert into t values (1);;",
+			deparsed: "insert into t values(1)",
+		},
+		{
+			name:     "delete empty statements",
+			stmt:     "delete from t;;",
+			deparsed: "delete from t",
+		},
+		{
+			name:     "update empty statements",
+			stmt:     "update t set a = 1; ;",
+			deparsed: "update t set a=1",
+		},
+		{
+			name:     "grant empty statements",
+			stmt:     "grant insert on t to 'a';;",
+			deparsed: "grant insert on t to 'a'",
+		},
+		{
+			name:     "alter table empty statements",
+			stmt:     "alter table t drop a;;",
+			deparsed: "alter table t drop a",
+		},
+		{
+			name:     "empty statements between writes",
+			stmt:     "insert into t values (1);; delete from t;;; update t set a = 1;",
+			deparsed: "insert into t values(1);delete from t;update t set a=1",
+		},
+		{
+			name:           "select multiple with empty statement",
+			stmt:           "select a from t;; select a from t",
+			expectedErrMsg: "syntax error",
+		},
+		{
+			name:           "only semicolons",
+			stmt:           ";;",
+			expectedErrMsg: "syntax error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := Parse(tc.stmt)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+					require.Len(t, ast.Errors, 0)
+					require.Equal(t, tc.deparsed, ast.String())
+				} else {
+					require.Error(t, err)
+					require.Contains(t, err.Error(), tc.expectedErrMsg)
+				}
+			}
+		}(tc))
+	}
+}
+
+// cancelAfterContext is a context that is canceled after its Err method is called n times.
+type cancelAfterContext struct {
+	context.Context
+	n     int
+	calls int
+}
+
+func (ctx *cancelAfterContext) Err() error {
+	ctx.calls++
+	if ctx.calls > ctx.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	t.Parallel()
+
+	columns := make([]string, 10*ctxCheckInterval)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("c%d", i)
+	}
+	stmt := fmt.Sprintf("select %s from t", strings.Join(columns, ", "))
+
+	t.Run("not canceled", func(t *testing.T) {
+		t.Parallel()
+		ast, err := ParseContext(context.Background(), stmt)
+		require.NoError(t, err)
+		require.Len(t, ast.Statements[0].(*Select).SelectColumnList, len(columns))
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ParseContext(ctx, stmt)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("canceled mid-parse", func(t *testing.T) {
+		t.Parallel()
+		ctx := &cancelAfterContext{Context: context.Background(), n: 3}
+		_, err := ParseContext(ctx, stmt)
+		require.ErrorIs(t, err, context.Canceled)
+		// parsing stops at the first check that fails, long before the end of the statement
+		require.Equal(t, ctx.n+1, ctx.calls)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+		_, err := ParseContext(ctx, stmt)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestLossless(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		statements []string
+	}
+
+	tests := []testCase{
+		{
+			name:       "unusual spacing",
+			stmt:       "  SELECT   a ,B\n\tFROM  t\r\n  WHERE a>1   AND b  =  'X  y'  ",
+			statements: []string{"SELECT   a ,B\n\tFROM  t\r\n  WHERE a>1   AND b  =  'X  y'"},
+		},
+		{
+			name:       "mixed case",
+			stmt:       "Select Count(*) From T Where A In (1,2)",
+			statements: []string{"Select Count(*) From T Where A In (1,2)"},
+		},
+		{
+			name: "multiple statements",
+			stmt: "INSERT INTO t VALUES (1) ;\n\n  update t SET a=2 ;; delete\tFROM t;",
+			statements: []string{
+				"INSERT INTO t VALUES (1)",
+				"update t SET a=2",
+				"delete\tFROM t",
+			},
+		},
+		{
+			name:       "quoted identifiers and blobs",
+			stmt:       "select \"A\" , [b], `c` ,x'0A' from t\n",
+			statements: []string{"select \"A\" , [b], `c` ,x'0A' from t"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{Lossless: true})
+				require.NoError(t, err)
+				require.Equal(t, tc.stmt, ast.Source())
+				require.Len(t, ast.Statements, len(tc.statements))
+				for i, stmt := range tc.statements {
+					source, ok := ast.StatementSource(i)
+					require.True(t, ok)
+					require.Equal(t, stmt, source)
+				}
+				for _, i := range []int{-1, len(tc.statements)} {
+					_, ok := ast.StatementSource(i)
+					require.False(t, ok)
+				}
+
+				// the lossless mode doesn't change the AST
+				canonical, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, canonical.Statements, ast.Statements)
+				require.Equal(t, canonical.String(), ast.String())
+			}
+		}(tc))
+	}
+
+	t.Run("node source", func(t *testing.T) {
+		t.Parallel()
+
+		sql := "INSERT  INTO t VALUES (1);  Delete FROM t WHERE a = 1"
+		ast, err := ParseWithOptions(sql, ParseOptions{Lossless: true})
+		require.NoError(t, err)
+
+		source, ok := ast.NodeSource(ast)
+		require.True(t, ok)
+		require.Equal(t, sql, source)
+
+		source, ok = ast.NodeSource(ast.Statements[1])
+		require.True(t, ok)
+		require.Equal(t, "Delete FROM t WHERE a = 1", source)
+
+		// nodes inside a statement don't keep their source
+		_, ok = ast.NodeSource(ast.Statements[1].(*Delete).Where)
+		require.False(t, ok)
+
+		other, err := ParseWithOptions(sql, ParseOptions{Lossless: true})
+		require.NoError(t, err)
+		_, ok = ast.NodeSource(other.Statements[0])
+		require.False(t, ok)
+	})
+
+	t.Run("not lossless", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("INSERT  INTO t VALUES (1);  DELETE FROM t")
+		require.NoError(t, err)
+		require.Equal(t, "insert into t values(1);delete from t", ast.Source())
+		source, ok := ast.StatementSource(1)
+		require.True(t, ok)
+		require.Equal(t, "delete from t", source)
+
+		_, ok = ast.StatementSource(5)
+		require.False(t, ok)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := ParseWithOptions("", ParseOptions{Lossless: true})
+		require.NoError(t, err)
+		require.Equal(t, "", ast.Source())
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		sql        string
+		statements []string
+		err        bool
+	}
+
+	tests := []testCase{
+		{
+			name:       "empty",
+			sql:        "",
+			statements: []string{},
+		},
+		{
+			name:       "only semicolons",
+			sql:        " ; ;; ",
+			statements: []string{},
+		},
+		{
+			name:       "single statement",
+			sql:        "select a from t",
+			statements: []string{"select a from t"},
+		},
+		{
+			name:       "multiple statements",
+			sql:        "insert into t values (1);\n update t set a = 2 ;;delete from t;",
+			statements: []string{"insert into t values (1)", "update t set a = 2", "delete from t"},
+		},
+		{
+			name:       "semicolon inside string",
+			sql:        "insert into t values ('a;b'); insert into t values (';'), ('it''s; ok')",
+			statements: []string{"insert into t values ('a;b')", "insert into t values (';'), ('it''s; ok')"},
+		},
+		{
+			name:       "quoted identifiers",
+			sql:        "select \"a\" from t where b = ';'; select [c], `e` from t",
+			statements: []string{"select \"a\" from t where b = ';'", "select [c], `e` from t"},
+		},
+		{
+			name:       "invalid statements are not parsed",
+			sql:        "select from; update where",
+			statements: []string{"select from", "update where"},
+		},
+		{
+			name: "unterminated string",
+			sql:  "insert into t values ('a;b); delete from t",
+			err:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				statements, err := SplitStatements(tc.sql)
+				if tc.err {
+					require.ErrorAs(t, err, new(*ErrSyntaxError))
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.statements, statements)
+			}
+		}(tc))
+	}
+
+	t.Run("trigger", func(t *testing.T) {
+		t.Parallel()
+
+		statements, err := SplitStatements("select 1; create trigger tr after insert on t begin select 1; end")
+		require.Nil(t, statements)
+		require.Equal(t, &ErrTriggersNotSupported{}, err)
+	})
+}
+
+func TestMaxStatements(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name          string
+		stmt          string
+		maxStatements int
+		expectedCount int
+	}
+
+	tests := []testCase{
+		{
+			name:          "below the limit",
+			stmt:          "insert into t (a) values (1); delete from t",
+			maxStatements: 3,
+		},
+		{
+			name:          "at the limit",
+			stmt:          "insert into t (a) values (1); delete from t; update t set a = 1;",
+			maxStatements: 3,
+		},
+		{
+			name:          "empty statements are not counted",
+			stmt:          "insert into t (a) values (1);;; delete from t;;",
+			maxStatements: 2,
+		},
+		{
+			name:          "unlimited",
+			stmt:          "insert into t (a) values (1); insert into t (a) values (2); insert into t (a) values (3)",
+			maxStatements: 0,
+		},
+		{
+			name:          "above the limit",
+			stmt:          "insert into t (a) values (1); delete from t; update t set a = 1",
+			maxStatements: 2,
+			expectedCount: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{MaxStatements: tc.maxStatements})
+				if tc.expectedCount == 0 {
+					require.NoError(t, err)
+					require.NotEmpty(t, ast.Statements)
+					return
+				}
+
+				require.Equal(t, &ErrTooManyStatements{StatementsCount: tc.expectedCount, MaxAllowed: tc.maxStatements}, err)
+				require.Nil(t, ast)
+			}
+		}(tc))
+	}
+
+	t.Run("large batch", func(t *testing.T) {
+		t.Parallel()
+
+		stmt := strings.Repeat("insert into t (a) values (1);", 26)
+		_, err := ParseWithOptions(stmt, ParseOptions{MaxStatements: 25})
+		require.Equal(t, &ErrTooManyStatements{StatementsCount: 26, MaxAllowed: 25}, err)
+		require.EqualError(t, err, "too many statements (has 26, max 25)")
+	})
+}
+
+func TestSingleStatement(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name          string
+		stmt          string
+		deparsed      string
+		expectedCount int
+	}
+
+	tests := []testCase{
+		{
+			name:     "single insert",
+			stmt:     "insert into t (a) values (1);",
+			deparsed: "insert into t(a)values(1)",
+		},
+		{
+			name:     "single insert empty statements",
+			stmt:     "insert into t (a) values (1);;",
+			deparsed: "insert into t(a)values(1)",
+		},
+		{
+			name:     "single select",
+			stmt:     "select a from t",
+			deparsed: "select a from t",
+		},
+		{
+			name:          "two statements",
+			stmt:          "insert into t (a) values (1); delete from t",
+			expectedCount: 2,
+		},
+		{
+			name:          "three statements",
+			stmt:          "insert into t (a) values (1); delete from t; update t set a = 1",
+			expectedCount: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{SingleStatement: true})
+				if tc.expectedCount == 0 {
 					require.NoError(t, err)
-					require.Len(t, ast.Errors, 0)
-					require.Equal(t, tc.expectedAST, ast)
 					require.Equal(t, tc.deparsed, ast.String())
-				} else {
-					require.Contains(t, err.Error(), tc.expectedErrMsg)
+					return
 				}
+
+				require.Error(t, err)
+				e := &ErrMultipleStatements{}
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, tc.expectedCount, e.StatementsCount)
+
+				// multiple statements are allowed by default
+				ast, err = Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Len(t, ast.Statements, tc.expectedCount)
 			}
 		}(tc))
 	}
@@ -4700,6 +9168,130 @@ func TestKeywordsNotAllowed(t *testing.T) {
 	}
 }
 
+func TestStringLiterals(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name  string
+		value string
+	}
+
+	tests := []testCase{
+		{name: "newline", value: "line1\nline2"},
+		{name: "carriage return and tab", value: "line1\r\n\tline2\r\n"},
+		{name: "escaped quote across lines", value: "it''s\n''quoted''\n"},
+		{name: "utf-8", value: "héllo wörld, 日本語, emoji 🎉"},
+		{name: "utf-8 across lines", value: "første linje\nвторая строка\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				stmt := fmt.Sprintf("insert into t values('%s')", tc.value)
+				ast, err := Parse(stmt)
+				require.NoError(t, err)
+
+				// the statement round-trips byte-for-byte
+				require.Equal(t, stmt, ast.String())
+				value := ast.Statements[0].(*Insert).Rows[0][0].(*Value)
+				require.Equal(t, StrValue, value.Type)
+				require.Equal(t, []byte(tc.value), value.Value)
+
+				// and SQLite stores the unescaped bytes
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("create table t (a text)")
+				require.NoError(t, err)
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+
+				var stored string
+				require.NoError(t, db.QueryRow("select a from t").Scan(&stored))
+				require.Equal(t, strings.ReplaceAll(tc.value, "''", "'"), stored)
+			}
+		}(tc))
+	}
+
+	t.Run("length in bytes", func(t *testing.T) {
+		t.Parallel()
+
+		// é takes 2 bytes, so MaxTextLength/2 of them are allowed
+		_, err := Parse(fmt.Sprintf("insert into t values('%s')", strings.Repeat("é", MaxTextLength/2)))
+		require.NoError(t, err)
+
+		_, err = Parse(fmt.Sprintf("insert into t values('%s')", strings.Repeat("é", MaxTextLength/2+1)))
+		var e *ErrTextTooLong
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, MaxTextLength+2, e.Length)
+	})
+
+	t.Run("escaped quotes count once", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(fmt.Sprintf("insert into t values('%s')", strings.Repeat("''", MaxTextLength)))
+		require.NoError(t, err)
+
+		_, err = Parse(fmt.Sprintf("insert into t values('%s')", strings.Repeat("''", MaxTextLength+1)))
+		var e *ErrTextTooLong
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, MaxTextLength+1, e.Length)
+	})
+}
+
+func TestInvalidBlobLiteral(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name    string
+		stmt    string
+		literal string
+	}
+
+	tests := []testCase{
+		{name: "valid", stmt: "select x'0aFF', X''"},
+		{name: "odd length", stmt: "select x'abc'", literal: "abc"},
+		{name: "non hex", stmt: "select x'GG'", literal: "GG"},
+		{name: "non hex odd length", stmt: "insert into t values (x'0g1')", literal: "0g1"},
+		{name: "spaces", stmt: "select x'ab cd'", literal: "ab cd"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(tc.stmt)
+				if tc.literal == "" {
+					require.NoError(t, err)
+				} else {
+					var e *ErrInvalidBlobLiteral
+					require.ErrorAs(t, err, &e)
+					require.Equal(t, tc.literal, e.Literal)
+				}
+
+				// SQLite agrees
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("create table t (a blob); " + tc.stmt)
+				require.Equal(t, tc.literal == "", err == nil)
+			}
+		}(tc))
+	}
+
+	t.Run("unterminated", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("select x'abcd")
+		require.Error(t, err)
+	})
+}
+
 func TestLimits(t *testing.T) {
 	t.Parallel()
 
@@ -4772,6 +9364,83 @@ func TestLimits(t *testing.T) {
 	})
 }
 
+func TestInSubquery(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+		rows     [][]interface{}
+	}
+
+	tests := []testCase{
+		{
+			name:     "in select without from",
+			stmt:     "SELECT a FROM t WHERE a IN (SELECT 1)",
+			deparsed: "select a from t where a in(select 1)",
+			rows:     [][]interface{}{{int64(1)}},
+		},
+		{
+			name:     "not in empty subquery",
+			stmt:     "SELECT a FROM t WHERE a NOT IN (SELECT 1 FROM t WHERE 0)",
+			deparsed: "select a from t where a not in(select 1 from t where 0)",
+			rows:     [][]interface{}{{int64(1)}, {int64(2)}},
+		},
+		{
+			name:     "in empty subquery",
+			stmt:     "SELECT a FROM t WHERE a IN (SELECT a FROM t WHERE false)",
+			deparsed: "select a from t where a in(select a from t where false)",
+			rows:     [][]interface{}{},
+		},
+		{
+			name:     "null not in empty subquery",
+			stmt:     "SELECT NULL NOT IN (SELECT a FROM t WHERE false), NULL IN (SELECT a FROM t WHERE false) FROM t LIMIT 1",
+			deparsed: "select null not in(select a from t where false),null in(select a from t where false)from t limit 1",
+			rows:     [][]interface{}{{int64(1), int64(0)}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int); INSERT INTO t VALUES (1), (2);")
+				require.NoError(t, err)
+				require.Equal(t, tc.rows, queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+
+	t.Run("rejected in writes", func(t *testing.T) {
+		t.Parallel()
+
+		for stmt, kind := range map[string]string{
+			"delete from t where a in (select 1)":                         "delete",
+			"delete from t where a not in (select 1 from t where 0)":      "delete",
+			"update t set a = 1 where a in (select 1)":                    "where",
+			"update t set a = 1 where a not in (select 1 from t where 0)": "where",
+		} {
+			_, err := Parse(stmt)
+			var e *ErrStatementContainsSubquery
+			require.ErrorAs(t, err, &e, stmt)
+			require.Equal(t, kind, e.StatementKind, stmt)
+		}
+
+		_, err := Parse("insert into t (a) select 1 from t2 where a in (select 1)")
+		require.ErrorAs(t, err, new(*ErrStatementContainsSubquery))
+	})
+}
+
 func TestDisallowSubqueriesOnStatements(t *testing.T) {
 	t.Parallel()
 	t.Run("insert", func(t *testing.T) {
@@ -5313,6 +9982,31 @@ func TestInsertWithSelect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "insert with select already ordered by rowid",
+			stmt:     "INSERT INTO t_1_1 SELECT * FROM t_1_2 order by rowid",
+			deparsed: "insert into t_1_1 select * from t_1_2 order by rowid asc",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Insert{
+						Table:   &Table{Name: "t_1_1", IsTarget: true},
+						Columns: ColumnList{},
+						Rows:    []Exprs{},
+						Select: &Select{
+							SelectColumnList: SelectColumnList{
+								&StarSelectColumn{},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t_1_2", IsTarget: true},
+							},
+							OrderBy: OrderBy{
+								&OrderingTerm{Expr: &Column{Name: "rowid"}, Direction: AscStr, Nulls: NullsNil},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:     "insert with select with order by",
 			stmt:     "INSERT INTO t_1_1 SELECT * FROM t_1_2 order by c desc",
@@ -5457,6 +10151,59 @@ func TestInsertWithSelect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "insert with columns and select with where",
+			stmt:     "INSERT INTO t_1_1 (a, b) SELECT x, y FROM t_1_2 WHERE z > 0",
+			deparsed: "insert into t_1_1(a,b)select x,y from t_1_2 where z>0 order by rowid asc",
+			expectedAST: &AST{
+				Statements: []Statement{
+					&Insert{
+						Table: &Table{Name: "t_1_1", IsTarget: true},
+						Columns: ColumnList{
+							&Column{Name: "a"},
+							&Column{Name: "b"},
+						},
+						Rows: []Exprs{},
+						Select: &Select{
+							SelectColumnList: SelectColumnList{
+								&AliasedSelectColumn{Expr: &Column{Name: "x"}},
+								&AliasedSelectColumn{Expr: &Column{Name: "y"}},
+							},
+							From: &AliasedTableExpr{
+								Expr: &Table{Name: "t_1_2", IsTarget: true},
+							},
+							Where: &Where{
+								Type: WhereStr,
+								Expr: &CmpExpr{
+									Operator: GreaterThanStr,
+									Left:     &Column{Name: "z"},
+									Right:    &Value{Type: IntValue, Value: []byte("0")},
+								},
+							},
+							OrderBy: OrderBy{
+								&OrderingTerm{Expr: &Column{Name: "rowid"}, Direction: AscStr, Nulls: NullsNil},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "insert with select with where subquery",
+			stmt: "INSERT INTO t_1_1 (a, b) SELECT x, y FROM t_1_2 WHERE z IN (SELECT z FROM t_1_3)",
+			expectedErr: func() **ErrStatementContainsSubquery {
+				err := &ErrStatementContainsSubquery{StatementKind: "insert+select"}
+				return &err
+			}(),
+		},
+		{
+			name: "insert with select with join and where",
+			stmt: "INSERT INTO t_1_1 (a, b) SELECT x, y FROM t_1_2 JOIN t_1_3 ON t_1_2.z = t_1_3.z WHERE x > 0",
+			expectedErr: func() **ErrContainsJoinTableExpr {
+				err := &ErrContainsJoinTableExpr{}
+				return &err
+			}(),
+		},
 		{
 			name: "insert with compound select",
 			stmt: "INSERT INTO t_1_1 SELECT * FROM t_1_2 UNION SELECT * FROM t_1_3",
@@ -5501,6 +10248,88 @@ func TestInsertWithSelect(t *testing.T) {
 	}
 }
 
+func TestInsertWithSelectDisableAutoOrderByRowid(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		stmt     string
+		deparsed string
+	}
+
+	tests := []testCase{
+		{
+			name:     "without order by",
+			stmt:     "INSERT INTO t_1_1 SELECT * FROM t_1_2",
+			deparsed: "insert into t_1_1 select * from t_1_2",
+		},
+		{
+			name:     "with order by",
+			stmt:     "INSERT INTO t_1_1 SELECT * FROM t_1_2 ORDER BY c DESC",
+			deparsed: "insert into t_1_1 select * from t_1_2 order by c desc",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{DisableAutoOrderByRowid: true})
+				require.NoError(t, err)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				// enabled by default
+				ast, err = Parse(tc.stmt)
+				require.NoError(t, err)
+				require.True(t, strings.HasSuffix(ast.String(), "rowid asc"))
+			}
+		}(tc))
+	}
+
+	t.Run("select without from", func(t *testing.T) {
+		t.Parallel()
+
+		// there's no rowid to order by
+		ast, err := Parse("INSERT INTO t_1_1 SELECT 1, 'a'")
+		require.NoError(t, err)
+		require.Equal(t, "insert into t_1_1 select 1,'a'", ast.String())
+	})
+}
+
+func TestInsertWithSelectWhereSQLite(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	_, err = db.Exec(`
+		CREATE TABLE t_1_1 (a int, b int);
+		CREATE TABLE t_1_2 (x int, y int, z int);
+		INSERT INTO t_1_2 VALUES (1, 10, 0), (2, 20, 1), (3, 30, 2);
+	`)
+	require.NoError(t, err)
+
+	ast, err := Parse("INSERT INTO t_1_1 (a, b) SELECT x, y FROM t_1_2 WHERE z > 0")
+	require.NoError(t, err)
+
+	_, err = db.Exec(ast.String())
+	require.NoError(t, err)
+
+	rows, err := db.Query("SELECT a, b FROM t_1_1 ORDER BY rowid")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, rows.Close()) }()
+
+	var got [][2]int
+	for rows.Next() {
+		var a, b int
+		require.NoError(t, rows.Scan(&a, &b))
+		got = append(got, [2]int{a, b})
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, [][2]int{{2, 20}, {3, 30}}, got)
+}
+
 type readResolver struct {
 	m      map[int]int64
 	values []Expr
@@ -5525,6 +10354,11 @@ func (r *writeResolver) GetTxnHash() string {
 	return "0xabc"
 }
 
+func (r *writeResolver) GetBlockNumberForChain(chainID int64) (int64, bool) {
+	v, ok := map[int64]int64{1337: 100, 5: 200}[chainID]
+	return v, ok
+}
+
 func TestCustomFunctionResolveReadQuery(t *testing.T) {
 	t.Parallel()
 
@@ -5551,6 +10385,11 @@ func TestCustomFunctionResolveReadQuery(t *testing.T) {
 			query:    "select BlOcK_NuM(1337), block_num(5) from foo_1337_1 where a = BLOCK_NUM(1)",
 			expQuery: "select 100,200 from foo_1337_1 where a=300",
 		},
+		{
+			name:     "select with quoted identifiers",
+			query:    "select [a], `b`, block_num(1337) from [foo_1337_1] join \"foo_1337_2\" on [foo_1337_1].[a] = \"foo_1337_2\".\"a\" where `c` = block_num(5)",
+			expQuery: "select [a],`b`,100 from [foo_1337_1] join \"foo_1337_2\" on [foo_1337_1].[a]=\"foo_1337_2\".\"a\" where `c`=200",
+		},
 		{
 			name:     "select with block_num() with string argument",
 			query:    "select block_num('1337') from foo_1337_1",
@@ -5671,8 +10510,33 @@ func TestCustomFunctionResolveWriteQuery(t *testing.T) {
 			},
 		},
 		{
-			name:     "block_num() with integer argument",
-			query:    "delete from foo_1337_1 where a=block_num(1337)",
+			name:       "quoted identifiers",
+			query:      "update [foo_1337_1] set `a`=block_num(), \"b\"=txn_hash() where [foo_1337_1].[c]=block_num(5)",
+			expQueries: []string{"update [foo_1337_1] set `a`=100,\"b\"='0xabc' where [foo_1337_1].[c]=200"},
+		},
+		{
+			name:       "insert with quoted identifiers",
+			query:      "insert into `foo_1337_1` ([a], \"b\") select block_num(), `c` from [foo_1337_2]",
+			expQueries: []string{"insert into `foo_1337_1`([a],\"b\")select 100,`c` from [foo_1337_2] order by rowid asc"},
+		},
+		{
+			name:       "block_num() with chain id argument",
+			query:      "delete from foo_1337_1 where a=block_num(5) and b=block_num()",
+			expQueries: []string{"delete from foo_1337_1 where a=200 and b=100"},
+		},
+		{
+			name:       "insert with block_num() with chain id argument",
+			query:      "insert into foo_1337_1 values (block_num(1337), block_num(5))",
+			expQueries: []string{"insert into foo_1337_1 values(100,200)"},
+		},
+		{
+			name:     "block_num() with unknown chain id argument",
+			query:    "delete from foo_1337_1 where a=block_num(1)",
+			mustFail: true,
+		},
+		{
+			name:     "block_num() with two arguments",
+			query:    "delete from foo_1337_1 where a=block_num(1337, 5)",
 			mustFail: true,
 		},
 		{
@@ -5706,25 +10570,339 @@ func TestCustomFunctionResolveWriteQuery(t *testing.T) {
 						require.Error(t, err)
 						return
 					}
-					require.Equal(t, tc.expQueries[i], resolved)
+					require.Equal(t, tc.expQueries[i], resolved)
+				}
+			}
+		}(it))
+	}
+
+	t.Run("nil resolver", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("insert into foo_1337_1 values (txn_hash())")
+		require.NoError(t, err)
+
+		_, err = ast.Statements[0].(WriteStatement).Resolve(nil)
+		require.Error(t, err)
+		require.ErrorContains(t, err, "write resolver is needed")
+	})
+}
+
+func TestCustomFunctionInDDL(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:        "default",
+			stmt:        "CREATE TABLE t (a INT DEFAULT (block_num()))",
+			expectedErr: &ErrCustomFuncInDDL{FunctionName: "block_num"},
+		},
+		{
+			name:        "column check",
+			stmt:        "CREATE TABLE t (a TEXT CHECK (a != txn_hash()))",
+			expectedErr: &ErrCustomFuncInDDL{FunctionName: "txn_hash"},
+		},
+		{
+			name:        "table check",
+			stmt:        "CREATE TABLE t (a INT, CHECK (a > block_num(1)))",
+			expectedErr: &ErrCustomFuncInDDL{FunctionName: "block_num"},
+		},
+		{
+			name:        "generated column",
+			stmt:        "CREATE TABLE t (a INT, b INT GENERATED ALWAYS AS (a + block_num()))",
+			expectedErr: &ErrCustomFuncInDDL{FunctionName: "block_num"},
+		},
+		{
+			name:        "alter table add",
+			stmt:        "ALTER TABLE t ADD COLUMN a INT DEFAULT (BLOCK_NUM())",
+			expectedErr: &ErrCustomFuncInDDL{FunctionName: "block_num"},
+		},
+		{
+			name: "builtin functions",
+			stmt: "CREATE TABLE t (a INT DEFAULT (abs(-1)) CHECK (a > length('x')))",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrCustomFuncInDDL))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+
+	t.Run("bare function call as default", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("CREATE TABLE t (a INT DEFAULT block_num())")
+		require.ErrorAs(t, err, new(*ErrCustomFuncInDDL))
+		require.ErrorContains(t, err, (&ErrCustomFuncInDDL{FunctionName: "block_num"}).Error())
+
+		_, err = Parse("ALTER TABLE t ADD COLUMN a INT DEFAULT txn_hash()")
+		require.ErrorAs(t, err, new(*ErrCustomFuncInDDL))
+
+		// as in SQLite, a DEFAULT expression must be parenthesized
+		_, err = Parse("CREATE TABLE t (a INT DEFAULT abs(1))")
+		require.ErrorContains(t, err, "a DEFAULT function call must be parenthesized")
+	})
+}
+
+func TestDuplicateColumnName(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:        "same name",
+			stmt:        "CREATE TABLE t (a INT, a TEXT)",
+			expectedErr: &ErrDuplicateColumnName{Name: "a"},
+		},
+		{
+			name:        "different case",
+			stmt:        "CREATE TABLE t (a INT, b INT, A TEXT)",
+			expectedErr: &ErrDuplicateColumnName{Name: "A"},
+		},
+		{
+			name:        "quoted",
+			stmt:        "CREATE TABLE t (a INT, \"A\" TEXT)",
+			expectedErr: &ErrDuplicateColumnName{Name: "\"A\""},
+		},
+		{
+			name: "unique names",
+			stmt: "CREATE TABLE t (a INT, ab TEXT, b INT)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+				_, sqliteErr := db.Exec(tc.stmt)
+
+				_, err = Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					require.NoError(t, sqliteErr)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrDuplicateColumnName))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+				require.ErrorContains(t, sqliteErr, "duplicate column name")
+			}
+		}(tc))
+	}
+}
+
+func TestDuplicateConstraintName(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:        "column constraints",
+			stmt:        "CREATE TABLE t (a INT CONSTRAINT c NOT NULL CONSTRAINT c UNIQUE)",
+			expectedErr: &ErrDuplicateConstraintName{Name: "c"},
+		},
+		{
+			name:        "constraints in different columns",
+			stmt:        "CREATE TABLE t (a INT CONSTRAINT c CHECK (a > 0), b INT CONSTRAINT C DEFAULT 1)",
+			expectedErr: &ErrDuplicateConstraintName{Name: "C"},
+		},
+		{
+			name:        "table constraints",
+			stmt:        "CREATE TABLE t (a INT, b INT, CONSTRAINT c UNIQUE (a), CONSTRAINT c CHECK (b > 0))",
+			expectedErr: &ErrDuplicateConstraintName{Name: "c"},
+		},
+		{
+			name:        "column and table constraints",
+			stmt:        "CREATE TABLE t (a INT CONSTRAINT c NOT NULL, b INT, CONSTRAINT \"c\" PRIMARY KEY (a, b))",
+			expectedErr: &ErrDuplicateConstraintName{Name: "\"c\""},
+		},
+		{
+			name: "unique names",
+			stmt: "CREATE TABLE t (a INT CONSTRAINT c1 NOT NULL CONSTRAINT c2 UNIQUE, b INT, CONSTRAINT c3 CHECK (b > 0))",
+		},
+		{
+			name: "unnamed constraints",
+			stmt: "CREATE TABLE t (a INT NOT NULL UNIQUE, b INT NOT NULL, CHECK (b > 0), CHECK (a > 0))",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(tc.stmt)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.ErrorAs(t, err, new(*ErrDuplicateConstraintName))
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			}
+		}(tc))
+	}
+}
+
+func TestCustomFunctionResolvedString(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		query    string
+		resolved string
+		expQuery string
+	}
+
+	tests := []testCase{
+		{
+			name:     "blob literal adjacent to equal",
+			query:    "delete from foo_1337_1 where a=txn_hash()",
+			resolved: "x'abcd'",
+			expQuery: "delete from foo_1337_1 where a=x'abcd'",
+		},
+		{
+			name:     "blob literal adjacent to not equal",
+			query:    "select * from foo_1337_1 where txn_hash()!=a",
+			resolved: "X'abcd'",
+			expQuery: "select * from foo_1337_1 where X'abcd'!=a",
+		},
+		{
+			name:     "string literal",
+			query:    "update foo_1337_1 set a=txn_hash()",
+			resolved: "'0xabc'",
+			expQuery: "update foo_1337_1 set a='0xabc'",
+		},
+		{
+			name:     "surrounding whitespace",
+			query:    "delete from foo_1337_1 where a>=block_num()",
+			resolved: " 100 ",
+			expQuery: "delete from foo_1337_1 where a>=100",
+		},
+		{
+			name:     "expression adjacent to operator",
+			query:    "delete from foo_1337_1 where a=2*block_num()",
+			resolved: "1+2",
+			expQuery: "delete from foo_1337_1 where a=2*(1+2)",
+		},
+		{
+			name:     "negative number after minus",
+			query:    "delete from foo_1337_1 where a=1-block_num()",
+			resolved: "-1",
+			expQuery: "delete from foo_1337_1 where a=1-(-1)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.query)
+				require.NoError(t, err)
+
+				_ = Walk(func(node Node) (bool, error) {
+					if funcExpr, ok := node.(*CustomFuncExpr); ok {
+						funcExpr.ResolvedString = tc.resolved
+					}
+					return false, nil
+				}, ast)
+				require.Equal(t, tc.expQuery, ast.String())
+
+				_, err = Parse(ast.String())
+				require.NoError(t, err)
+			}
+		}(tc))
+	}
+}
+
+func TestBindValuesResolveReadQuery(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		query    string
+		mustFail bool
+		expQuery string
+	}
+
+	resolver := &readResolver{
+		values: []Expr{&Value{Type: StrValue, Value: []byte("joe")}, &Value{Type: IntValue, Value: []byte("12")}},
+	}
+
+	tests := []testCase{
+		{
+			name:     "select with params",
+			query:    "select * from from_1337_1 where name = ? and age = ?",
+			expQuery: "select * from from_1337_1 where name='joe' and age=12",
+		},
+		{
+			name:     "select with params with less values than params",
+			query:    "select * from from_1337_1 where name = ? and age = ? and height = ?",
+			mustFail: true,
+		},
+	}
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.query)
+				require.NoError(t, err)
+
+				for _, stmt := range ast.Statements {
+					resolved, err := stmt.(ReadStatement).Resolve(resolver)
+					if tc.mustFail {
+						require.Error(t, err)
+						return
+					}
+					require.Equal(t, tc.expQuery, resolved)
 				}
 			}
 		}(it))
 	}
+}
 
-	t.Run("nil resolver", func(t *testing.T) {
-		t.Parallel()
-
-		ast, err := Parse("insert into foo_1337_1 values (txn_hash())")
-		require.NoError(t, err)
+type bindListResolver struct {
+	readResolver
+	lists map[int][]Expr
+}
 
-		_, err = ast.Statements[0].(WriteStatement).Resolve(nil)
-		require.Error(t, err)
-		require.ErrorContains(t, err, "write resolver is needed")
-	})
+func (r *bindListResolver) GetBindList(index int) []Expr {
+	return r.lists[index]
 }
 
-func TestBindValuesResolveReadQuery(t *testing.T) {
+func TestBindListResolveReadQuery(t *testing.T) {
 	t.Parallel()
 
 	type testCase struct {
@@ -5734,19 +10912,38 @@ func TestBindValuesResolveReadQuery(t *testing.T) {
 		expQuery string
 	}
 
-	resolver := &readResolver{
-		values: []Expr{&Value{Type: StrValue, Value: []byte("joe")}, &Value{Type: IntValue, Value: []byte("12")}},
+	resolver := &bindListResolver{
+		readResolver: readResolver{
+			values: []Expr{&Value{Type: StrValue, Value: []byte("joe")}, nil, &Value{Type: IntValue, Value: []byte("2")}},
+		},
+		lists: map[int][]Expr{
+			1: {
+				&Value{Type: IntValue, Value: []byte("1")},
+				&Value{Type: IntValue, Value: []byte("2")},
+				&Value{Type: IntValue, Value: []byte("3")},
+			},
+		},
 	}
 
 	tests := []testCase{
 		{
-			name:     "select with params",
-			query:    "select * from from_1337_1 where name = ? and age = ?",
-			expQuery: "select * from from_1337_1 where name='joe' and age=12",
+			name:     "in with bind list",
+			query:    "select * from foo_1337_1 where name = ? and a in ?",
+			expQuery: "select * from foo_1337_1 where name='joe' and a in(1,2,3)",
 		},
 		{
-			name:     "select with params with less values than params",
-			query:    "select * from from_1337_1 where name = ? and age = ? and height = ?",
+			name:     "not in with bind list",
+			query:    "select * from foo_1337_1 where name = ? and a not in ?",
+			expQuery: "select * from foo_1337_1 where name='joe' and a not in(1,2,3)",
+		},
+		{
+			name:     "in with single bind value",
+			query:    "select * from foo_1337_1 where name = ? and a in ? and b in ?",
+			expQuery: "select * from foo_1337_1 where name='joe' and a in(1,2,3)and b in(2)",
+		},
+		{
+			name:     "in with missing bind value",
+			query:    "select * from foo_1337_1 where name = ? and a in ? and b in ? and c in ?",
 			mustFail: true,
 		},
 	}
@@ -5759,17 +10956,78 @@ func TestBindValuesResolveReadQuery(t *testing.T) {
 				ast, err := Parse(tc.query)
 				require.NoError(t, err)
 
-				for _, stmt := range ast.Statements {
-					resolved, err := stmt.(ReadStatement).Resolve(resolver)
-					if tc.mustFail {
-						require.Error(t, err)
-						return
-					}
-					require.Equal(t, tc.expQuery, resolved)
+				resolved, err := ast.Statements[0].(ReadStatement).Resolve(resolver)
+				if tc.mustFail {
+					require.Error(t, err)
+					return
 				}
+				require.NoError(t, err)
+				require.Equal(t, tc.expQuery, resolved)
+
+				_, err = Parse(resolved)
+				require.NoError(t, err)
 			}
 		}(it))
 	}
+
+	t.Run("ast", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select * from t where a in ?")
+		require.NoError(t, err)
+		require.Equal(t, &CmpExpr{
+			Operator: InStr,
+			Left:     &Column{Name: "a"},
+			Right:    &Param{},
+		}, ast.Statements[0].(*Select).Where.Expr)
+		require.Equal(t, "select * from t where a in ?", ast.String())
+	})
+
+	t.Run("not allowed outside reads", func(t *testing.T) {
+		t.Parallel()
+
+		for _, stmt := range []string{
+			"delete from t where a in ?",
+			"update t set b = 1 where a not in ?",
+			"insert into t values (1) on conflict (a) do update set b = 2 where b in ?",
+			"create table t (a int check (a in ?))",
+			"create view v as select a from t where a in ?",
+		} {
+			_, err := Parse(stmt)
+			require.ErrorAs(t, err, new(*ErrInParamNotAllowed), stmt)
+		}
+	})
+
+	t.Run("resolver without bind lists", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select * from t where a in ?")
+		require.NoError(t, err)
+
+		resolved, err := ast.Statements[0].(ReadStatement).Resolve(&readResolver{
+			values: []Expr{&Value{Type: IntValue, Value: []byte("1")}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "select * from t where a in(1)", resolved)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+
+		_, err = db.Exec("create table foo_1337_1 (name text, a int, b int, c int); insert into foo_1337_1 values ('joe', 1, 2, 0), ('joe', 4, 2, 0), ('ann', 2, 2, 0)")
+		require.NoError(t, err)
+
+		ast, err := Parse("select a from foo_1337_1 where name = ? and a in ?")
+		require.NoError(t, err)
+		resolved, err := ast.Statements[0].(ReadStatement).Resolve(resolver)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{{int64(1)}}, queryRows(t, db, resolved))
+	})
 }
 
 func TestAlterTable(t *testing.T) {
@@ -5934,6 +11192,297 @@ func TestAlterTable(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorAs(t, err, &expErr)
 	})
+
+	t.Run("not null constraint without default check", func(t *testing.T) {
+		t.Parallel()
+
+		var expErr *ErrNotNullConstraintDefaultNotNull
+		_, err := Parse("alter table t ADD COLUMN a INT NOT NULL")
+		require.Error(t, err)
+		require.ErrorAs(t, err, &expErr)
+
+		_, err = Parse("alter table t ADD COLUMN a INT NOT NULL DEFAULT 0")
+		require.NoError(t, err)
+
+		// SQLite fails to add the column to a table with rows
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+		_, err = db.Exec("CREATE TABLE t (b int); INSERT INTO t VALUES (1);")
+		require.NoError(t, err)
+		_, err = db.Exec("ALTER TABLE t ADD COLUMN a INT NOT NULL")
+		require.ErrorContains(t, err, "Cannot add a NOT NULL column with default value NULL")
+	})
+}
+
+func TestMaintenanceStatements(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		expectedAST *AST
+	}
+
+	tests := []testCase{
+		{
+			name:     "vacuum",
+			stmt:     "VACUUM;",
+			deparsed: "vacuum",
+			expectedAST: &AST{
+				Statements: []Statement{&Vacuum{}},
+			},
+		},
+		{
+			name:     "vacuum schema",
+			stmt:     "VACUUM main",
+			deparsed: "vacuum main",
+			expectedAST: &AST{
+				Statements: []Statement{&Vacuum{Schema: Identifier("main")}},
+			},
+		},
+		{
+			name:     "analyze",
+			stmt:     "ANALYZE",
+			deparsed: "analyze",
+			expectedAST: &AST{
+				Statements: []Statement{&Analyze{}},
+			},
+		},
+		{
+			name:     "analyze table",
+			stmt:     "ANALYZE t",
+			deparsed: "analyze t",
+			expectedAST: &AST{
+				Statements: []Statement{&Analyze{Table: &Table{Name: Identifier("t"), IsTarget: true}}},
+			},
+		},
+		{
+			name:     "reindex",
+			stmt:     "REINDEX",
+			deparsed: "reindex",
+			expectedAST: &AST{
+				Statements: []Statement{&Reindex{}},
+			},
+		},
+		{
+			name:     "reindex table",
+			stmt:     "reindex t;",
+			deparsed: "reindex t",
+			expectedAST: &AST{
+				Statements: []Statement{&Reindex{Table: &Table{Name: Identifier("t"), IsTarget: true}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{AllowMaintenanceStatements: true})
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, tc.expectedAST, ast)
+				require.Equal(t, tc.deparsed, ast.String())
+
+				_, ok := ast.Statements[0].(AdminStatement)
+				require.True(t, ok)
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int);")
+				require.NoError(t, err)
+
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+
+				// not allowed by default
+				_, err = Parse(tc.stmt)
+				require.Error(t, err)
+				require.ErrorAs(t, err, new(*ErrMaintenanceStatementNotAllowed))
+			}
+		}(tc))
+	}
+
+	t.Run("multiple statements", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseWithOptions("VACUUM; ANALYZE", ParseOptions{AllowMaintenanceStatements: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "syntax error")
+	})
+
+	t.Run("keywords as identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+
+		for _, stmt := range []string{
+			"CREATE TABLE t_1 (vacuum INT, analyze INT, reindex INT, pragma INT)",
+			"INSERT INTO t_1 (vacuum, analyze, reindex, pragma) VALUES (1, 2, 3, 4)",
+		} {
+			ast, err := Parse(stmt)
+			require.NoError(t, err)
+
+			_, err = db.Exec(ast.String())
+			require.NoError(t, err)
+		}
+
+		ast, err := Parse("SELECT vacuum, analyze AS a, pragma FROM t_1 WHERE reindex = 3")
+		require.NoError(t, err)
+		require.Equal(t, "select vacuum,analyze as a,pragma from t_1 where reindex=3", ast.String())
+		require.Equal(t, [][]interface{}{{int64(1), int64(2), int64(4)}}, queryRows(t, db, ast.String()))
+	})
+}
+
+func TestPragma(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		deparsed    string
+		readOnly    bool
+		expectedAST *AST
+	}
+
+	tests := []testCase{
+		{
+			name:     "table info",
+			stmt:     "PRAGMA table_info(t)",
+			deparsed: "pragma table_info(t)",
+			readOnly: true,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("table_info"), Arg: &Column{Name: Identifier("t")}}},
+			},
+		},
+		{
+			name:     "page count",
+			stmt:     "PRAGMA page_count;",
+			deparsed: "pragma page_count",
+			readOnly: true,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("page_count")}},
+			},
+		},
+		{
+			name:     "foreign keys",
+			stmt:     "PRAGMA foreign_keys;",
+			deparsed: "pragma foreign_keys",
+			readOnly: true,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("foreign_keys")}},
+			},
+		},
+		{
+			name:     "user version",
+			stmt:     "PRAGMA user_version = 3",
+			deparsed: "pragma user_version=3",
+			readOnly: false,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("user_version"), Value: &Value{Type: IntValue, Value: []byte("3")}}},
+			},
+		},
+		{
+			name:     "negative value",
+			stmt:     "PRAGMA cache_size = -2000",
+			deparsed: "pragma cache_size=-2000",
+			readOnly: false,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("cache_size"), Value: &Value{Type: IntValue, Value: []byte("-2000")}}},
+			},
+		},
+		{
+			name:     "string value",
+			stmt:     "PRAGMA journal_mode = 'wal'",
+			deparsed: "pragma journal_mode='wal'",
+			readOnly: false,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("journal_mode"), Value: &Value{Type: StrValue, Value: []byte("wal")}}},
+			},
+		},
+		{
+			name:     "setting with argument",
+			stmt:     "PRAGMA user_version(3)",
+			deparsed: "pragma user_version(3)",
+			readOnly: false,
+			expectedAST: &AST{
+				Statements: []Statement{&Pragma{Name: Identifier("user_version"), Arg: &Value{Type: IntValue, Value: []byte("3")}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				ast, err := ParseWithOptions(tc.stmt, ParseOptions{AllowMaintenanceStatements: true})
+				require.NoError(t, err)
+				require.Len(t, ast.Errors, 0)
+				require.Equal(t, tc.expectedAST, ast)
+				require.Equal(t, tc.deparsed, ast.String())
+				require.Equal(t, tc.readOnly, ast.Statements[0].(*Pragma).IsReadOnly())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec("CREATE TABLE t (a int);")
+				require.NoError(t, err)
+
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+
+				// only read-only pragmas are allowed by default
+				_, err = Parse(tc.stmt)
+				if tc.readOnly {
+					require.NoError(t, err)
+				} else {
+					require.ErrorAs(t, err, new(*ErrMaintenanceStatementNotAllowed))
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("writing forms are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+		require.NoError(t, err)
+		defer func() { require.NoError(t, db.Close()) }()
+		// foreign_keys and cache_size are set per connection
+		db.SetMaxOpenConns(1)
+
+		type writeCase struct {
+			stmt     string
+			query    string
+			expected [][]interface{}
+		}
+
+		for _, wc := range []writeCase{
+			{stmt: "PRAGMA user_version(3)", query: "PRAGMA user_version", expected: [][]interface{}{{int64(3)}}},
+			{stmt: "PRAGMA user_version = 4", query: "PRAGMA user_version", expected: [][]interface{}{{int64(4)}}},
+			{stmt: "PRAGMA application_id(7)", query: "PRAGMA application_id", expected: [][]interface{}{{int64(7)}}},
+			{stmt: "PRAGMA foreign_keys(0)", query: "PRAGMA foreign_keys", expected: [][]interface{}{{int64(0)}}},
+			{stmt: "PRAGMA foreign_keys = 1", query: "PRAGMA foreign_keys", expected: [][]interface{}{{int64(1)}}},
+			{stmt: "PRAGMA cache_size = -1000", query: "PRAGMA cache_size", expected: [][]interface{}{{int64(-1000)}}},
+		} {
+			_, err := Parse(wc.stmt)
+			require.ErrorAs(t, err, new(*ErrMaintenanceStatementNotAllowed), wc.stmt)
+
+			ast, err := ParseWithOptions(wc.stmt, ParseOptions{AllowMaintenanceStatements: true})
+			require.NoError(t, err, wc.stmt)
+			require.False(t, ast.Statements[0].(*Pragma).IsReadOnly(), wc.stmt)
+
+			_, err = db.Exec(wc.stmt)
+			require.NoError(t, err, wc.stmt)
+			require.Equal(t, wc.expected, queryRows(t, db, wc.query), wc.stmt)
+		}
+	})
 }
 
 func TestSelectStatementWithParams(t *testing.T) {
@@ -6014,3 +11563,82 @@ func TestReservedKeywords(t *testing.T) {
 		require.NoError(t, db.Close())
 	}
 }
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"select a, b from t where a = 1 and b like 'x%' order by a desc nulls last limit 10 offset 2",
+		"select count(*), max(a) filter (where b > 0) from t group by c having count(distinct a) > 1",
+		"select t.a from t join t2 on t.a = t2.a left join t3 using (b) where t.c in (select c from t4)",
+		"select a from t union all select a from t2 except select a from t3",
+		"select case when a > 1 then 'x' else 'y' end, cast(a as text), -a, ~b, a || b, a -> '$.x' from t",
+		"insert into t (a, b) values (1, 'a'), (2, x'00ff') on conflict (a) do update set b = excluded.b",
+		"insert into t select * from t2 where a is not null",
+		"update t set a = block_num(), b = txn_hash() where c between 1 and 10",
+		"delete from t where a = ? and b not in (1, 2)",
+		"create table t_1 (id integer primary key, a text not null default 'x', b int check (b > 0), unique (a))",
+		"alter table t rename column a to b; alter table t add column c int",
+		"grant insert, update on t to 'a', 'b'; revoke delete on t from 'a'",
+		"seleCt - -0",
+		"select a from t limit 1, 2",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, statement string) {
+		ast, err := Parse(statement)
+		if err != nil {
+			return
+		}
+
+		deparsed := ast.String()
+		reparsed, err := Parse(deparsed)
+		require.NoError(t, err, "deparsed: %s", deparsed)
+		require.Equal(t, ast, reparsed, "deparsed: %s", deparsed)
+	})
+}
+
+// benchmarkQueries returns representative query shapes used by the benchmarks.
+func benchmarkQueries() []struct{ name, stmt string } {
+	var insert strings.Builder
+	insert.WriteString("insert into t (a, b, c) values ")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			insert.WriteString(", ")
+		}
+		fmt.Fprintf(&insert, "(%d, 'value %d', ?)", i, i)
+	}
+
+	var join strings.Builder
+	join.WriteString("select t0.a from t0")
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&join, " join t%d on t%d.a = t%d.a", i, i-1, i)
+	}
+	join.WriteString(" where t0.b > 1")
+
+	expr := "a"
+	for i := 0; i < 100; i++ {
+		expr = fmt.Sprintf("(%s + %d) * b", expr, i)
+	}
+
+	return []struct{ name, stmt string }{
+		{name: "simple select", stmt: "select a, b from t where c = 1 order by a limit 10"},
+		{name: "multi-row insert", stmt: insert.String()},
+		{name: "deep join", stmt: join.String()},
+		{name: "nested expression", stmt: "select " + expr + " from t"},
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, query := range benchmarkQueries() {
+		query := query
+		b.Run(query.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(query.stmt); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,520 @@
+package sqlparser
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SimplifyParens returns a copy of the node without the parentheses that don't change its semantics.
+// A parenthesized operand is kept only when its expression doesn't bind tighter than the operator,
+// e.g. (a+b)*c is kept, but ((1)) becomes 1, (a*b)+c becomes a*b+c and from (t) becomes from t.
+// The node is not modified.
+func SimplifyParens(node Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	node = cloneNode(node)
+	for {
+		switch paren := node.(type) {
+		case *ParenExpr:
+			node = paren.Expr
+			continue
+		case *ParenTableExpr:
+			node = paren.TableExpr
+			continue
+		}
+		break
+	}
+
+	simplifyParens(reflect.ValueOf(node), 0)
+	return node
+}
+
+// simplifyParens removes the redundant parentheses of v's children.
+// operatorPrecedence is the precedence of the operator v's children are operands of, or 0 if they aren't operands.
+func simplifyParens(v reflect.Value, operatorPrecedence int) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if node, ok := v.Interface().(Node); ok {
+			operatorPrecedence = 0
+			if expr, ok := node.(Expr); ok && exprPrecedence(expr) < atomicPrecedence {
+				operatorPrecedence = exprPrecedence(expr)
+			}
+		}
+		simplifyParens(v.Elem(), operatorPrecedence)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// the children are simplified first, so the unwrapped node is in its final form
+		simplifyParens(v.Elem(), operatorPrecedence)
+		if v.CanSet() {
+			unwrapParens(v, operatorPrecedence)
+		}
+	case reflect.Slice:
+		// list items are separated by commas, so they are never operands
+		for i := 0; i < v.Len(); i++ {
+			simplifyParens(v.Index(i), 0)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				simplifyParens(v.Field(i), operatorPrecedence)
+			}
+		}
+	}
+}
+
+// unwrapParens replaces the parenthesized node held by v with its inner node while it's safe to do so.
+func unwrapParens(v reflect.Value, operatorPrecedence int) {
+	for {
+		var inner Node
+		switch paren := v.Elem().Interface().(type) {
+		case *ParenExpr:
+			if paren == nil || paren.Expr == nil {
+				return
+			}
+			if operatorPrecedence != 0 && exprPrecedence(paren.Expr) <= operatorPrecedence {
+				return
+			}
+			// an operand starting with a minus sign can't follow a minus operator, because -- starts a comment
+			if operatorPrecedence != 0 && strings.HasPrefix(paren.Expr.String(), "-") {
+				return
+			}
+			inner = paren.Expr
+		case *ParenTableExpr:
+			// the grammar only allows a single table or subquery inside the parentheses
+			if paren == nil || paren.TableExpr == nil {
+				return
+			}
+			inner = paren.TableExpr
+		default:
+			return
+		}
+
+		innerValue := reflect.ValueOf(inner)
+		if !innerValue.Type().AssignableTo(v.Type()) {
+			return
+		}
+		v.Set(innerValue)
+	}
+}
+
+// atomicPrecedence is the precedence of expressions that aren't operators, like literals and function calls.
+const atomicPrecedence = 100
+
+// exprPrecedence returns how tightly the expression binds its operands, following the grammar's precedence rules.
+// NOT has the same precedence as IS, so the NOT of an IS NOT expression is handled safely.
+func exprPrecedence(expr Expr) int {
+	switch expr := expr.(type) {
+	case *OrExpr:
+		return 1
+	case *AndExpr:
+		return 2
+	case *NotExpr, *IsExpr, *IsNullExpr, *NotNullExpr, *BetweenExpr:
+		return 3
+	case *CmpExpr:
+		switch expr.Operator {
+		case LessThanStr, GreaterThanStr, LessEqualStr, GreaterEqualStr:
+			return 4
+		}
+		return 3
+	case *BinaryExpr:
+		switch expr.Operator {
+		case BitAndStr, BitOrStr, ShiftLeftStr, ShiftRightStr:
+			return 5
+		case PlusStr, MinusStr:
+			return 6
+		case MultStr, DivStr, ModStr:
+			return 7
+		}
+		return 8
+	case *CollateExpr:
+		return 9
+	case *UnaryExpr:
+		return 10
+	}
+	return atomicPrecedence
+}
+
+// InlineParams returns the string representation of the node with its ? params replaced by values,
+// in the order the params appear in the statement.
+// It's meant for logging the executed SQL, and it doesn't resolve custom functions. The node is not modified.
+// It returns an error if the number of params and values differ.
+func InlineParams(node Node, values []Expr) (string, error) {
+	if node == nil {
+		return "", nil
+	}
+
+	node = cloneNode(node)
+	params := []*Param{}
+	listParams := map[*Param]struct{}{}
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		if param, ok := isInListParam(node); ok {
+			listParams[param] = struct{}{}
+		}
+		if param, ok := node.(*Param); ok && param != nil {
+			params = append(params, param)
+		}
+		return false, nil
+	}, node)
+
+	if len(params) != len(values) {
+		return "", &ErrParamsCountMismatch{ParamsCount: len(params), ValuesCount: len(values)}
+	}
+	for i, param := range params {
+		// the list of an IN operator must be rendered in parentheses, e.g. a IN ? as a in(1)
+		if _, ok := listParams[param]; ok {
+			param.ResolvedString = Exprs{values[i]}.String()
+			continue
+		}
+		param.ResolvedString = values[i].String()
+	}
+
+	return node.String(), nil
+}
+
+// QualifyColumns qualifies the unqualified columns referenced by the node with the table, or table alias,
+// they refer to in their FROM clause, so the node is rendered with fully qualified columns, e.g. t.a.
+// Columns are resolved to defaultTable if they aren't in the scope of any table, e.g. when the node is an expression.
+// It returns an error if an unqualified column may refer to more than one table.
+// References to result column aliases and columns of unaliased subqueries are not modified. Neither are
+// unqualified columns of a subquery, because without a schema they may refer to a table of the outer query.
+func QualifyColumns(node Node, defaultTable string) error {
+	var s *scope
+	if defaultTable != "" {
+		s = &scope{tables: []*scopeTable{
+			{name: normalizeIdentifier(Identifier(defaultTable)), ref: Identifier(defaultTable), isTable: true},
+		}}
+	}
+
+	r := &columnResolver{
+		ignoreUnproven: true,
+		onColumn: func(column *Column, table *scopeTable) error {
+			if column.TableRef == nil && table != nil && !table.ref.IsEmpty() {
+				column.TableRef = &Table{Name: table.ref}
+			}
+			return nil
+		},
+	}
+	return r.node(node, s)
+}
+
+// RenameAlias renames the oldAlias table alias to newAlias, in the FROM clauses that declare it
+// and in the column and star references qualified with it. Names are compared case-insensitively.
+// It returns an error if no FROM clause declares oldAlias, if newAlias is already declared,
+// or if a table referenced by its name is named oldAlias or newAlias, because the references
+// couldn't be renamed unambiguously.
+// The node is not modified if an error is returned.
+func RenameAlias(node Node, oldAlias, newAlias string) error {
+	oldName, newName := normalizeIdentifier(Identifier(oldAlias)), normalizeIdentifier(Identifier(newAlias))
+	aliased := []*AliasedTableExpr{}
+	refs := []*Table{}
+	// it's ok to ignore the error because the visit function does not throw an error
+	_ = Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *AliasedTableExpr:
+			if node != nil && normalizeIdentifier(node.As) == oldName {
+				aliased = append(aliased, node)
+			}
+		case *Column:
+			if node != nil && node.TableRef != nil {
+				refs = append(refs, node.TableRef)
+			}
+		case *StarSelectColumn:
+			if node != nil && node.TableRef != nil {
+				refs = append(refs, node.TableRef)
+			}
+		}
+		return false, nil
+	}, node)
+
+	if len(aliased) == 0 {
+		return &ErrUnknownTable{Name: oldAlias}
+	}
+	if oldName != newName {
+		// aliased tables can only be referenced by their alias
+		aliasedTables := map[*Table]struct{}{}
+		if err := Walk(func(node Node) (bool, error) {
+			switch node := node.(type) {
+			case *AliasedTableExpr:
+				if node == nil || node.As.IsEmpty() {
+					return false, nil
+				}
+				if normalizeIdentifier(node.As) == newName {
+					return true, &ErrAliasConflict{Name: newAlias}
+				}
+				if table, ok := node.Expr.(*Table); ok {
+					aliasedTables[table] = struct{}{}
+				}
+			case *Table:
+				if _, ok := aliasedTables[node]; ok || node == nil || !node.IsTarget {
+					return false, nil
+				}
+				switch normalizeIdentifier(node.Name) {
+				case oldName:
+					return true, &ErrAliasConflict{Name: oldAlias}
+				case newName:
+					return true, &ErrAliasConflict{Name: newAlias}
+				}
+			}
+			return false, nil
+		}, node); err != nil {
+			return err
+		}
+	}
+
+	for _, expr := range aliased {
+		expr.As = Identifier(newAlias)
+	}
+	for _, ref := range refs {
+		if normalizeIdentifier(ref.Name) == oldName {
+			ref.Name = Identifier(newAlias)
+		}
+	}
+	return nil
+}
+
+// FoldConstants returns a copy of the node with its constant integer and boolean expressions evaluated,
+// e.g. 1+2*3 becomes 7 and not true becomes false. The node is not modified.
+// Expressions whose result doesn't fit in an int64, divisions by zero and negative shifts are left unfolded,
+// so the database evaluates them the way it does for any other expression. ORDER BY and GROUP BY terms are
+// never folded into an integer literal, which would turn them into a column ordinal.
+func FoldConstants(node Node) (Node, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	node = cloneNode(node)
+	foldConstants(reflect.ValueOf(node))
+	if expr, ok := node.(Expr); ok {
+		if folded, ok := foldExpr(expr); ok {
+			return folded, nil
+		}
+	}
+	return node, nil
+}
+
+// foldConstants replaces the constant expressions of v's children with their values.
+func foldConstants(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		foldConstants(v.Elem())
+	case reflect.Interface:
+		foldInterface(v)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if v.Type() == reflect.TypeOf(GroupBy{}) {
+				foldTerm(v.Index(i))
+				continue
+			}
+			foldConstants(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(OrderingTerm{}) {
+			foldTerm(v.FieldByName("Expr"))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				foldConstants(v.Field(i))
+			}
+		}
+	}
+}
+
+// foldInterface folds the expression held by the interface value.
+func foldInterface(v reflect.Value) {
+	if v.IsNil() {
+		return
+	}
+	// the children are folded first, so the operands are already literals if they are constant
+	foldConstants(v.Elem())
+	expr, ok := v.Interface().(Expr)
+	if !ok || !v.CanSet() {
+		return
+	}
+	if folded, ok := foldExpr(expr); ok && reflect.TypeOf(folded).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(folded))
+	}
+}
+
+// foldTerm folds the expression of an ORDER BY or GROUP BY term. A term that is an integer literal,
+// even a parenthesized one, is a column ordinal, so the term is left unfolded if it would become one.
+func foldTerm(v reflect.Value) {
+	if v.IsNil() || !v.CanSet() {
+		return
+	}
+	expr, ok := v.Interface().(Expr)
+	if !ok {
+		return
+	}
+	folded, _ := FoldConstants(expr)
+	if _, ok := intConstant(folded.(Expr)); ok || !reflect.TypeOf(folded).AssignableTo(v.Type()) {
+		return
+	}
+	v.Set(reflect.ValueOf(folded))
+}
+
+// foldExpr evaluates the expression if its operands are constant literals.
+// It reports false if the expression isn't constant or its result can't be represented safely.
+func foldExpr(expr Expr) (Expr, bool) {
+	switch expr := expr.(type) {
+	case *ParenExpr:
+		// a negative literal keeps its parentheses, so it isn't rendered as a -- comment after a minus operator
+		if n, ok := intConstant(expr.Expr); ok && n >= 0 {
+			return intLiteral(n), true
+		}
+		if b, ok := expr.Expr.(BoolValue); ok {
+			return b, true
+		}
+	case *UnaryExpr:
+		n, ok := intConstant(expr.Expr)
+		if !ok {
+			return nil, false
+		}
+		switch expr.Operator {
+		case UPlusStr:
+			return intLiteral(n), true
+		case UMinusStr:
+			if n == math.MinInt64 {
+				return nil, false
+			}
+			return intLiteral(-n), true
+		case TildaStr:
+			return intLiteral(^n), true
+		}
+	case *BinaryExpr:
+		left, ok := intConstant(expr.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := intConstant(expr.Right)
+		if !ok {
+			return nil, false
+		}
+		if n, ok := foldIntOperation(expr.Operator, left, right); ok {
+			return intLiteral(n), true
+		}
+	case *AndExpr:
+		left, ok := boolConstant(expr.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := boolConstant(expr.Right)
+		if !ok {
+			return nil, false
+		}
+		return BoolValue(left && right), true
+	case *OrExpr:
+		left, ok := boolConstant(expr.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := boolConstant(expr.Right)
+		if !ok {
+			return nil, false
+		}
+		return BoolValue(left || right), true
+	case *NotExpr:
+		if b, ok := boolConstant(expr.Expr); ok {
+			return BoolValue(!b), true
+		}
+	}
+	return nil, false
+}
+
+// foldIntOperation applies the binary operator to the integers, reporting false when the result overflows
+// or SQLite wouldn't produce an integer.
+func foldIntOperation(operator string, left, right int64) (int64, bool) {
+	switch operator {
+	case PlusStr:
+		n := left + right
+		if (right > 0 && n < left) || (right < 0 && n > left) {
+			return 0, false
+		}
+		return n, true
+	case MinusStr:
+		n := left - right
+		if (right < 0 && n < left) || (right > 0 && n > left) {
+			return 0, false
+		}
+		return n, true
+	case MultStr:
+		if left == 0 || right == 0 {
+			return 0, true
+		}
+		n := left * right
+		if n/right != left || (right == -1 && left == math.MinInt64) {
+			return 0, false
+		}
+		return n, true
+	case DivStr:
+		// division by zero results in NULL
+		if right == 0 || (left == math.MinInt64 && right == -1) {
+			return 0, false
+		}
+		return left / right, true
+	case ModStr:
+		if right == 0 {
+			return 0, false
+		}
+		if right == -1 {
+			return 0, true
+		}
+		return left % right, true
+	case BitAndStr:
+		return left & right, true
+	case BitOrStr:
+		return left | right, true
+	case ShiftLeftStr:
+		// a negative shift reverses its direction
+		if right < 0 {
+			return 0, false
+		}
+		return left << uint64(right), true
+	case ShiftRightStr:
+		if right < 0 {
+			return 0, false
+		}
+		return left >> uint64(right), true
+	}
+	return 0, false
+}
+
+// intConstant returns the value of an integer literal, which may be parenthesized.
+func intConstant(expr Expr) (int64, bool) {
+	if paren, ok := expr.(*ParenExpr); ok && paren != nil {
+		return intConstant(paren.Expr)
+	}
+	value, ok := expr.(*Value)
+	if !ok || value == nil || value.Type != IntValue {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(string(value.Value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// boolConstant returns the value of a boolean literal.
+func boolConstant(expr Expr) (bool, bool) {
+	b, ok := expr.(BoolValue)
+	return bool(b), ok
+}
+
+// intLiteral returns the integer literal of n.
+func intLiteral(n int64) *Value {
+	return &Value{Type: IntValue, Value: []byte(strconv.FormatInt(n, 10))}
+}
@@ -0,0 +1,676 @@
+package sqlparser
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplifyParens(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name       string
+		stmt       string
+		simplified string
+	}
+
+	tests := []testCase{
+		{
+			name:       "nested literal",
+			stmt:       "select ((1)) from t",
+			simplified: "select 1 from t",
+		},
+		{
+			name:       "required parens",
+			stmt:       "select (a+b)*c from t",
+			simplified: "select(a+b)*c from t",
+		},
+		{
+			name:       "nested required parens",
+			stmt:       "select ((a+b))*c from t",
+			simplified: "select(a+b)*c from t",
+		},
+		{
+			name:       "atomic operands",
+			stmt:       "select (a)*(b), -(c), (a)||(b) from t",
+			simplified: "select a*b,-c,a||b from t",
+		},
+		{
+			name:       "unary operand",
+			stmt:       "select -(a+b), (-a)*b from t",
+			simplified: "select -(a+b),(-a)*b from t",
+		},
+		{
+			name:       "operator precedence",
+			stmt:       "select (a*b)+c, a-(b-c), (a-b)-c, a*(b/c), (a||b) collate nocase, (a<b) = (b<c) from t",
+			simplified: "select a*b+c,a-(b-c),(a-b)-c,a*(b/c),(a||b)collate nocase,a<b=b<c from t",
+		},
+		{
+			name:       "negative operands",
+			stmt:       "select a-(-1), a-(-b), -(-a), a-((-b)*c), (-1) from t",
+			simplified: "select a-(-1),a-(-b),-(-a),a-(-b)*c,-1 from t",
+		},
+		{
+			name:       "where",
+			stmt:       "select a from t where (a = 1 or b = 2)",
+			simplified: "select a from t where a=1 or b=2",
+		},
+		{
+			name:       "logical operators",
+			stmt:       "select a from t where (a = 1) and (b = 2 or c = 3) and (c)",
+			simplified: "select a from t where a=1 and(b=2 or c=3)and c",
+		},
+		{
+			name:       "is not",
+			stmt:       "select a from t where a is not (b = 1) and (a is null) = (b isnull)",
+			simplified: "select a from t where a is not(b=1)and(a is null)=(b isnull)",
+		},
+		{
+			name:       "function arguments and lists",
+			stmt:       "select abs((a-b)) from t where a in ((1+1), (2)) order by (a+b) desc",
+			simplified: "select abs(a-b)from t where a in(1+1,2)order by a+b desc",
+		},
+		{
+			name:       "between",
+			stmt:       "select a from t where a between (1) and (b+1)",
+			simplified: "select a from t where a between 1 and b+1",
+		},
+		{
+			name:       "case",
+			stmt:       "select case (a) when (1) then (b+1) else (c) end from t",
+			simplified: "select case a when 1 then b+1 else c end from t",
+		},
+		{
+			name:       "subquery",
+			stmt:       "select a from t where a = ((select max(b) from t))",
+			simplified: "select a from t where a=(select max(b)from t)",
+		},
+		{
+			name:       "paren table",
+			stmt:       "select a from ((t))",
+			simplified: "select a from t",
+		},
+		{
+			name:       "paren table in join",
+			stmt:       "select t.a from t join (t2) on t.a = t2.a",
+			simplified: "select t.a from t join t2 on t.a=t2.a",
+		},
+		{
+			name:       "update",
+			stmt:       "update t set a = (b+1) where (a > (1))",
+			simplified: "update t set a=b+1 where a>1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				simplified := SimplifyParens(ast)
+				require.Equal(t, tc.simplified, simplified.String())
+
+				// the original tree is not modified
+				require.Equal(t, original, ast.String())
+
+				// simplifying is idempotent
+				reparsed, err := Parse(simplified.String())
+				require.NoError(t, err)
+				require.Equal(t, tc.simplified, SimplifyParens(reparsed).String())
+
+				// and preserves the semantics
+				if _, ok := ast.Statements[0].(*Select); ok {
+					db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+					require.NoError(t, err)
+					defer func() { require.NoError(t, db.Close()) }()
+
+					_, err = db.Exec(`
+						CREATE TABLE t (a int, b int, c int);
+						CREATE TABLE t2 (a int);
+						INSERT INTO t VALUES (1, 2, 3), (2, 3, 1), (3, 1, 2);
+						INSERT INTO t2 VALUES (1), (3);
+					`)
+					require.NoError(t, err)
+					require.Equal(t, queryRows(t, db, original), queryRows(t, db, simplified.String()))
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("expression", func(t *testing.T) {
+		t.Parallel()
+
+		expr := &ParenExpr{Expr: &ParenExpr{Expr: &BinaryExpr{
+			Operator: PlusStr,
+			Left:     &Column{Name: "a"},
+			Right:    &ParenExpr{Expr: &Value{Type: IntValue, Value: []byte("1")}},
+		}}}
+		require.Equal(t, "a+1", SimplifyParens(expr).String())
+		require.Equal(t, "((a+(1)))", expr.String())
+	})
+}
+
+func queryRows(t *testing.T, db *sql.DB, query string, args ...interface{}) [][]interface{} {
+	t.Helper()
+
+	rows, err := db.Query(query, args...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, rows.Close()) }()
+
+	columns, err := rows.Columns()
+	require.NoError(t, err)
+
+	result := [][]interface{}{}
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+		require.NoError(t, rows.Scan(pointers...))
+		result = append(result, row)
+	}
+	require.NoError(t, rows.Err())
+
+	return result
+}
+
+func TestQualifyColumns(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		qualified   string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:      "single table",
+			stmt:      "select a, b from t where c > 1 group by a order by b",
+			qualified: "select t.a,t.b from t where t.c>1 group by t.a order by t.b asc",
+		},
+		{
+			name:      "table alias",
+			stmt:      "select a, x.b from t as x where abs(c) > 1",
+			qualified: "select x.a,x.b from t as x where abs(x.c)>1",
+		},
+		{
+			name:      "result column alias",
+			stmt:      "select a as y from t order by y",
+			qualified: "select t.a as y from t order by y asc",
+		},
+		{
+			name:      "join",
+			stmt:      "select t.a, t2.b from t join t2 on t.a = t2.a",
+			qualified: "select t.a,t2.b from t join t2 on t.a=t2.a",
+		},
+		{
+			name:      "join using",
+			stmt:      "select a from t join t2 using (a)",
+			qualified: "select t.a from t join t2 using(a)",
+		},
+		{
+			name:        "ambiguous column in join",
+			stmt:        "select a from t join t2 on t.a = t2.a",
+			expectedErr: &ErrAmbiguousColumn{Column: "a"},
+		},
+		{
+			name:      "subquery",
+			stmt:      "select a from t where a in (select a from t2)",
+			qualified: "select t.a from t where t.a in(select a from t2)",
+		},
+		{
+			name:      "correlated subquery",
+			stmt:      "select a from t where exists (select 1 from t2 where t2.a = c and b = t.b)",
+			qualified: "select t.a from t where exists(select 1 from t2 where t2.a=c and b=t.b)",
+		},
+		{
+			name:      "from subquery",
+			stmt:      "select a from (select a from t2) as s",
+			qualified: "select s.a from(select t2.a from t2)as s",
+		},
+		{
+			name:      "compound select",
+			stmt:      "select a from t union select a from t2 order by a",
+			qualified: "select t.a from t union select t2.a from t2 order by a asc",
+		},
+		{
+			name:      "update",
+			stmt:      "update t set a = b where c = 1",
+			qualified: "update t set a=t.b where t.c=1",
+		},
+		{
+			name:      "delete",
+			stmt:      "delete from t where c = 1",
+			qualified: "delete from t where t.c=1",
+		},
+		{
+			name:      "upsert",
+			stmt:      "insert into t (a, b) values (1, 2) on conflict (a) do update set b = excluded.b + b where c > 1",
+			qualified: "insert into t(a,b)values(1,2)on conflict(a)do update set b=excluded.b+t.b where t.c>1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				err = QualifyColumns(ast, "")
+				if tc.expectedErr != nil {
+					require.Equal(t, tc.expectedErr, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.qualified, ast.String())
+
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec(`
+					CREATE TABLE t (a int unique, b int, c int);
+					CREATE TABLE t2 (a int, b int);
+				`)
+				require.NoError(t, err)
+				_, err = db.Exec(ast.String())
+				require.NoError(t, err)
+			}
+		}(tc))
+	}
+
+	t.Run("expression with default table", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t where b > 1 and t.c = 2")
+		require.NoError(t, err)
+		where := ast.Statements[0].(*Select).Where
+
+		require.NoError(t, QualifyColumns(where, "t"))
+		require.Equal(t, " where t.b>1 and t.c=2", where.String())
+	})
+
+	t.Run("expression without default table", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := Parse("select a from t where b > 1")
+		require.NoError(t, err)
+		where := ast.Statements[0].(*Select).Where
+
+		require.Equal(t, &ErrUnknownColumn{Column: "b"}, QualifyColumns(where, ""))
+	})
+}
+
+func TestInlineParams(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		values      []Expr
+		inlined     string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:    "no params",
+			stmt:    "select a from t",
+			values:  []Expr{},
+			inlined: "select a from t",
+		},
+		{
+			name: "positional",
+			stmt: "select a, ? from t where b = ? and c in (?, ?) limit ?",
+			values: []Expr{
+				&Value{Type: StrValue, Value: []byte("x")},
+				&Value{Type: IntValue, Value: []byte("1")},
+				&NullValue{},
+				BoolValue(true),
+				&Value{Type: IntValue, Value: []byte("10")},
+			},
+			inlined: "select a,'x' from t where b=1 and c in(null,true)limit 10",
+		},
+		{
+			name: "insert",
+			stmt: "insert into t (a, b) values (?, ?), (?, x'ff')",
+			values: []Expr{
+				&Value{Type: StrValue, Value: []byte("it''s")},
+				&Value{Type: FloatValue, Value: []byte("1.5")},
+				&Value{Type: BlobValue, Value: []byte("0a")},
+			},
+			inlined: "insert into t(a,b)values('it''s',1.5),(X'0a',X'ff')",
+		},
+		{
+			name: "insert with upsert",
+			stmt: "insert into t (a, b) values (?, ?) on conflict (a) do update set b = ?",
+			values: []Expr{
+				&Value{Type: IntValue, Value: []byte("1")},
+				&Value{Type: IntValue, Value: []byte("2")},
+				&Value{Type: IntValue, Value: []byte("3")},
+			},
+			inlined: "insert into t(a,b)values(1,2)on conflict(a)do update set b=3",
+		},
+		{
+			name: "order by and limit",
+			stmt: "select a from t where a = ? order by ? limit ?",
+			values: []Expr{
+				&Value{Type: IntValue, Value: []byte("1")},
+				&Value{Type: StrValue, Value: []byte("x")},
+				&Value{Type: IntValue, Value: []byte("3")},
+			},
+			inlined: "select a from t where a=1 order by 'x' asc limit 3",
+		},
+		{
+			name: "comma limit",
+			stmt: "select a from t limit ?, ?",
+			values: []Expr{
+				&Value{Type: IntValue, Value: []byte("5")},
+				&Value{Type: IntValue, Value: []byte("10")},
+			},
+			inlined: "select a from t limit 5,10",
+		},
+		{
+			name:    "in list",
+			stmt:    "select a from t where b in ? and c not in ?",
+			values:  []Expr{&Value{Type: IntValue, Value: []byte("1")}, &Value{Type: StrValue, Value: []byte("x")}},
+			inlined: "select a from t where b in(1)and c not in('x')",
+		},
+		{
+			name:    "negative value after minus",
+			stmt:    "delete from t where b = -? and c = 'x'",
+			values:  []Expr{&Value{Type: IntValue, Value: []byte("-1")}},
+			inlined: "delete from t where b=- -1 and c='x'",
+		},
+		{
+			name:        "fewer values",
+			stmt:        "select a from t where b = ? and c = ?",
+			values:      []Expr{&Value{Type: IntValue, Value: []byte("1")}},
+			expectedErr: &ErrParamsCountMismatch{ParamsCount: 2, ValuesCount: 1},
+		},
+		{
+			name: "more values",
+			stmt: "select a from t where b = ?",
+			values: []Expr{
+				&Value{Type: IntValue, Value: []byte("1")},
+				&Value{Type: IntValue, Value: []byte("2")},
+			},
+			expectedErr: &ErrParamsCountMismatch{ParamsCount: 1, ValuesCount: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				inlined, err := InlineParams(ast, tc.values)
+				if tc.expectedErr != nil {
+					require.Equal(t, tc.expectedErr, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.inlined, inlined)
+
+				// the original tree is not modified
+				require.Equal(t, original, ast.String())
+			}
+		}(tc))
+	}
+}
+
+func TestFoldConstants(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name   string
+		stmt   string
+		folded string
+	}
+
+	tests := []testCase{
+		{
+			name:   "addition",
+			stmt:   "select 1+1",
+			folded: "select 2",
+		},
+		{
+			name:   "nested arithmetic",
+			stmt:   "select (1+2)*3-10/4%3, 7&~2|1<<4>>1",
+			folded: "select 7,40",
+		},
+		{
+			name:   "negative result",
+			stmt:   "select a-(1-6), -(2*3), 2*(1-6) from t",
+			folded: "select a-(-5),-6,-10 from t",
+		},
+		{
+			name:   "partially constant",
+			stmt:   "select a+(2*3) from t where a>1+1",
+			folded: "select a+6 from t where a>2",
+		},
+		{
+			name:   "booleans",
+			stmt:   "select a from t where not false and (true or false) and a>0",
+			folded: "select a from t where true and a>0",
+		},
+		{
+			name:   "addition overflow",
+			stmt:   "select 9223372036854775807+1",
+			folded: "select 9223372036854775807+1",
+		},
+		{
+			name:   "multiplication overflow",
+			stmt:   "select 4611686018427387904*2, -9223372036854775807-1",
+			folded: "select 4611686018427387904*2,-9223372036854775808",
+		},
+		{
+			name:   "negation overflow",
+			stmt:   "select -(-9223372036854775807-1)",
+			folded: "select -(-9223372036854775808)",
+		},
+		{
+			name:   "division by zero",
+			stmt:   "select 1/0, 1%0, 5/(1-1)",
+			folded: "select 1/0,1%0,5/0",
+		},
+		{
+			name:   "integer overflowing literal",
+			stmt:   "select 9223372036854775808+1",
+			folded: "select 9223372036854775808+1",
+		},
+		{
+			name:   "group by term",
+			stmt:   "select b from t group by 0+1",
+			folded: "select b from t group by 0+1",
+		},
+		{
+			name:   "order by terms",
+			stmt:   "select a from t order by 5-1, (1+1), a*(2-1), 1",
+			folded: "select a from t order by 5-1 asc,(1+1)asc,a*1 asc,1 asc",
+		},
+		{
+			name:   "update",
+			stmt:   "update t set a=60*60*24 where b=2-1",
+			folded: "update t set a=86400 where b=1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				folded, err := FoldConstants(ast)
+				require.NoError(t, err)
+				require.Equal(t, tc.folded, folded.String())
+
+				// the original tree is not modified
+				require.Equal(t, original, ast.String())
+
+				// folding preserves the semantics
+				if _, ok := ast.Statements[0].(*Select); ok {
+					db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+					require.NoError(t, err)
+					defer func() { require.NoError(t, db.Close()) }()
+
+					_, err = db.Exec(`
+						CREATE TABLE t (a int, b int);
+						INSERT INTO t VALUES (1, 2), (2, 3), (3, 1);
+					`)
+					require.NoError(t, err)
+					require.Equal(t, queryRows(t, db, original), queryRows(t, db, folded.String()))
+				}
+			}
+		}(tc))
+	}
+
+	t.Run("expression", func(t *testing.T) {
+		t.Parallel()
+
+		expr := &BinaryExpr{
+			Operator: MultStr,
+			Left:     &ParenExpr{Expr: &BinaryExpr{Operator: PlusStr, Left: intLiteral(1), Right: intLiteral(2)}},
+			Right:    intLiteral(3),
+		}
+		folded, err := FoldConstants(expr)
+		require.NoError(t, err)
+		require.Equal(t, "9", folded.String())
+		require.Equal(t, "(1+2)*3", expr.String())
+	})
+}
+
+func TestRenameAlias(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		stmt        string
+		oldAlias    string
+		newAlias    string
+		renamed     string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name:     "select list and where",
+			stmt:     "select x.a, x.*, b from t as x where x.a > 1 and x.b = b",
+			oldAlias: "x",
+			newAlias: "y",
+			renamed:  "select y.a,y.*,b from t as y where y.a>1 and y.b=b",
+		},
+		{
+			name:     "join and order by",
+			stmt:     "select X.a, t2.a from t x join t2 on x.a = t2.a order by x.b",
+			oldAlias: "x",
+			newAlias: "first",
+			renamed:  "select first.a,t2.a from t as first join t2 on first.a=t2.a order by first.b asc",
+		},
+		{
+			name:     "correlated subquery",
+			stmt:     "select a from t x where exists (select 1 from t2 where t2.a = x.a)",
+			oldAlias: "x",
+			newAlias: "y",
+			renamed:  "select a from t as y where exists(select 1 from t2 where t2.a=y.a)",
+		},
+		{
+			name:     "subquery alias",
+			stmt:     "select s.a from (select a from t) s where s.a > 0",
+			oldAlias: "s",
+			newAlias: "sub",
+			renamed:  "select sub.a from(select a from t)as sub where sub.a>0",
+		},
+		{
+			name:     "alias named as its table",
+			stmt:     "select t.a from t as t",
+			oldAlias: "t",
+			newAlias: "u",
+			renamed:  "select u.a from t as u",
+		},
+		{
+			name:        "unknown alias",
+			stmt:        "select a from t as x",
+			oldAlias:    "z",
+			newAlias:    "y",
+			expectedErr: &ErrUnknownTable{Name: "z"},
+		},
+		{
+			name:        "new alias already declared",
+			stmt:        "select x.a from t as x join t2 as y on x.a = y.a",
+			oldAlias:    "x",
+			newAlias:    "y",
+			expectedErr: &ErrAliasConflict{Name: "y"},
+		},
+		{
+			name:        "new alias is a table",
+			stmt:        "select x.a from t as x join t2 on x.a = t2.a",
+			oldAlias:    "x",
+			newAlias:    "t2",
+			expectedErr: &ErrAliasConflict{Name: "t2"},
+		},
+		{
+			name:        "old alias is also a table",
+			stmt:        "select x.a from t as x where exists (select 1 from x where x.a = 1)",
+			oldAlias:    "x",
+			newAlias:    "y",
+			expectedErr: &ErrAliasConflict{Name: "x"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+				original := ast.String()
+
+				err = RenameAlias(ast, tc.oldAlias, tc.newAlias)
+				if tc.expectedErr != nil {
+					require.Equal(t, tc.expectedErr, err)
+					// the node is not modified on error
+					require.Equal(t, original, ast.String())
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.renamed, ast.String())
+
+				// renaming preserves the semantics
+				db, err := sql.Open("sqlite3", "file::"+uuid.NewString()+":?mode=memory&cache=shared&_foreign_keys=on")
+				require.NoError(t, err)
+				defer func() { require.NoError(t, db.Close()) }()
+
+				_, err = db.Exec(`
+					CREATE TABLE t (a int, b int);
+					CREATE TABLE t2 (a int);
+					INSERT INTO t VALUES (1, 2), (2, 2), (3, 1);
+					INSERT INTO t2 VALUES (1), (3);
+				`)
+				require.NoError(t, err)
+				require.Equal(t, queryRows(t, db, original), queryRows(t, db, ast.String()))
+			}
+		}(tc))
+	}
+}
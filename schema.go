@@ -0,0 +1,590 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAgainstSchema checks that every table referenced by the node is in the schema,
+// and that every column reference is a column of its (possibly aliased) table.
+// The schema maps tables' names to their columns' names. Names are case-insensitive and may be quoted.
+// CREATE TABLE statements are not validated, because they don't reference existing tables.
+func ValidateAgainstSchema(node Node, schema map[string][]string) error {
+	tables := make(map[string]map[string]struct{}, len(schema))
+	for table, columns := range schema {
+		tables[normalizeIdentifier(Identifier(table))] = newColumnSet(columns...)
+	}
+
+	r := &columnResolver{schema: tables}
+	return r.node(node, nil)
+}
+
+// scopeTable is a table that can be referenced by the columns in a scope.
+type scopeTable struct {
+	// name is the normalized name used to reference the table, its alias or its name.
+	name string
+
+	// ref is the name used to reference the table as written in the statement.
+	ref Identifier
+
+	// columns are the normalized table's columns, or nil if they are not known.
+	columns map[string]struct{}
+
+	// isTable is true for tables, and false for subqueries, which don't have a rowid.
+	isTable bool
+
+	// qualifiedOnly is true for tables whose columns can only be referenced qualified, like excluded.
+	qualifiedOnly bool
+
+	// merged are the normalized columns merged into the left table by a USING clause or a NATURAL join,
+	// which don't make unqualified references ambiguous.
+	merged map[string]struct{}
+}
+
+// hasColumn checks if the table may have the column.
+func (t *scopeTable) hasColumn(column Identifier) bool {
+	if t.columns == nil {
+		return true
+	}
+	if t.isTable && isRowID(column) {
+		return true
+	}
+	_, ok := t.columns[normalizeIdentifier(column)]
+	return ok
+}
+
+// matchesUnqualified checks if an unqualified reference to the column may refer to the table.
+func (t *scopeTable) matchesUnqualified(column Identifier) bool {
+	if t.qualifiedOnly {
+		return false
+	}
+	if _, ok := t.merged[normalizeIdentifier(column)]; ok {
+		return false
+	}
+	return t.hasColumn(column)
+}
+
+// scope holds the tables and result column aliases that can be referenced by a column.
+type scope struct {
+	parent  *scope
+	tables  []*scopeTable
+	aliases map[string]struct{}
+}
+
+// columnResolver resolves column references to the tables in their scope.
+type columnResolver struct {
+	// schema maps the normalized tables' names to their normalized columns' names.
+	// If nil, any table is accepted and its columns are unknown.
+	schema map[string]map[string]struct{}
+
+	// onColumn is called for every column reference with the table it was resolved to,
+	// or nil if it was resolved to a result column alias.
+	onColumn func(column *Column, table *scopeTable) error
+
+	// onSubquery is called for every subquery with the scope its columns may reference, before resolving them.
+	onSubquery func(subquery *Subquery, parent *scope)
+
+	// ignoreAmbiguous resolves ambiguous unqualified columns to no table instead of returning an error.
+	ignoreAmbiguous bool
+
+	// ignoreUnproven resolves unqualified columns to no table when they are resolved to a table with unknown columns,
+	// but may refer to a table of an outer scope as well, e.g. in a correlated subquery without a schema.
+	ignoreUnproven bool
+}
+
+func (r *columnResolver) node(node Node, s *scope) error {
+	switch node := node.(type) {
+	case *AST:
+		if node == nil {
+			return nil
+		}
+		for _, stmt := range node.Statements {
+			if err := r.node(stmt, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ReadStatement:
+		_, err := r.readStatement(node, s)
+		return err
+	case *Insert:
+		return r.insert(node)
+	case *Update:
+		return r.update(node)
+	case *Delete:
+		return r.delete(node)
+	case *Grant:
+		_, err := r.table(node.Table, "")
+		return err
+	case *Revoke:
+		_, err := r.table(node.Table, "")
+		return err
+	case *AlterTable:
+		return r.alterTable(node)
+	case *CreateView:
+		// the view is being created, so only the tables its select reads must exist
+		_, err := r.readStatement(node.Select, nil)
+		return err
+	case *CreateTable, *Vacuum, *Analyze, *Reindex, *Pragma:
+		return nil
+	}
+	return r.expr(node, s)
+}
+
+// readStatement resolves the statement's columns and returns its result columns, or nil if they are not known.
+func (r *columnResolver) readStatement(stmt ReadStatement, parent *scope) (map[string]struct{}, error) {
+	switch stmt := stmt.(type) {
+	case *Select:
+		return r.selectStmt(stmt, parent, false, nil)
+	case *CompoundSelect:
+		left, err := r.readStatement(stmt.Left, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		// the ORDER BY of a compound select, which belongs to its last select, references the result columns
+		if sel, ok := stmt.Right.(*Select); ok {
+			if _, err := r.selectStmt(sel, parent, true, left); err != nil {
+				return nil, err
+			}
+			return left, nil
+		}
+		if _, err := r.readStatement(stmt.Right, parent); err != nil {
+			return nil, err
+		}
+		return left, nil
+	case *Values:
+		columns := map[string]struct{}{}
+		for _, row := range stmt.Rows {
+			if err := r.expr(row, parent); err != nil {
+				return nil, err
+			}
+			for i := range row {
+				columns[fmt.Sprintf("column%d", i+1)] = struct{}{}
+			}
+		}
+		return columns, nil
+	}
+	return nil, nil
+}
+
+// selectStmt resolves the select's columns and returns its result columns, or nil if they are not known.
+// If compound is true, the select is the last one of a compound select,
+// and its ORDER BY references the compound's result columns, compoundColumns, which may not be known.
+func (r *columnResolver) selectStmt(
+	sel *Select, parent *scope, compound bool, compoundColumns map[string]struct{},
+) (map[string]struct{}, error) {
+	if sel == nil {
+		return nil, nil
+	}
+
+	s := &scope{parent: parent, aliases: map[string]struct{}{}}
+	if sel.From != nil {
+		if err := r.tableExpr(sel.From, s); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := map[string]struct{}{}
+	for _, col := range sel.SelectColumnList {
+		switch col := col.(type) {
+		case *StarSelectColumn:
+			tables := s.tables
+			if col.TableRef != nil {
+				table := s.lookupTable(col.TableRef.Name)
+				if table == nil {
+					return nil, &ErrUnknownTable{Name: col.TableRef.Name.String()}
+				}
+				tables = []*scopeTable{table}
+			}
+			for _, table := range tables {
+				if columns != nil && table.columns == nil {
+					columns = nil
+				}
+				for column := range table.columns {
+					if columns != nil {
+						columns[column] = struct{}{}
+					}
+				}
+			}
+		case *AliasedSelectColumn:
+			if err := r.expr(col.Expr, s); err != nil {
+				return nil, err
+			}
+			name := strings.ToLower(col.Expr.String())
+			if column, ok := col.Expr.(*Column); ok {
+				name = normalizeIdentifier(column.Name)
+			}
+			if !col.As.IsEmpty() {
+				name = normalizeIdentifier(col.As)
+				s.aliases[name] = struct{}{}
+			}
+			if columns != nil {
+				columns[name] = struct{}{}
+			}
+		}
+	}
+
+	if err := r.expr(sel.Where, s); err != nil {
+		return nil, err
+	}
+	for _, expr := range sel.GroupBy {
+		if err := r.expr(expr, s); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.expr(sel.Having, s); err != nil {
+		return nil, err
+	}
+	orderByScope := s
+	if compound {
+		orderByScope = &scope{aliases: compoundColumns}
+	}
+	for _, term := range sel.OrderBy {
+		if compound && compoundColumns == nil {
+			break
+		}
+		if err := r.expr(term.Expr, orderByScope); err != nil {
+			return nil, err
+		}
+	}
+	if sel.Limit != nil {
+		if err := r.expr(sel.Limit.Limit, s); err != nil {
+			return nil, err
+		}
+		if err := r.expr(sel.Limit.Offset, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return columns, nil
+}
+
+// tableExpr adds the tables of the table expression to the scope.
+func (r *columnResolver) tableExpr(expr TableExpr, s *scope) error {
+	switch expr := expr.(type) {
+	case *AliasedTableExpr:
+		switch table := expr.Expr.(type) {
+		case *Table:
+			scopeTable, err := r.table(table, expr.As)
+			if err != nil {
+				return err
+			}
+			s.tables = append(s.tables, scopeTable)
+		case *Subquery:
+			if r.onSubquery != nil {
+				r.onSubquery(table, s.parent)
+			}
+			columns, err := r.readStatement(table.Select, s.parent)
+			if err != nil {
+				return err
+			}
+			s.tables = append(s.tables, &scopeTable{name: normalizeIdentifier(expr.As), ref: expr.As, columns: columns})
+		}
+	case *ParenTableExpr:
+		return r.tableExpr(expr.TableExpr, s)
+	case *JoinTableExpr:
+		if err := r.tableExpr(expr.LeftExpr, s); err != nil {
+			return err
+		}
+		if err := r.tableExpr(expr.RightExpr, s); err != nil {
+			return err
+		}
+		if err := r.expr(expr.On, s); err != nil {
+			return err
+		}
+		right := s.tables[len(s.tables)-1]
+		right.merged = map[string]struct{}{}
+		for _, column := range expr.Using {
+			if !right.hasColumn(column.Name) {
+				return &ErrUnknownColumn{Table: right.ref.String(), Column: column.Name.String()}
+			}
+			right.merged[normalizeIdentifier(column.Name)] = struct{}{}
+		}
+		if expr.JoinOperator != nil && expr.JoinOperator.Natural {
+			for column := range right.columns {
+				for _, left := range s.tables[:len(s.tables)-1] {
+					if left.hasColumn(Identifier(column)) {
+						right.merged[column] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// table returns the scope table of a table that must be in the schema.
+func (r *columnResolver) table(table *Table, as Identifier) (*scopeTable, error) {
+	var columns map[string]struct{}
+	if r.schema != nil {
+		var ok bool
+		if columns, ok = r.schema[normalizeIdentifier(table.Name)]; !ok {
+			return nil, &ErrUnknownTable{Name: table.Name.String()}
+		}
+	}
+
+	ref := table.Name
+	if !as.IsEmpty() {
+		ref = as
+	}
+	return &scopeTable{name: normalizeIdentifier(ref), ref: ref, columns: columns, isTable: true}, nil
+}
+
+// targetColumns checks that the columns, which can't be qualified, are columns of the table.
+func (r *columnResolver) targetColumns(table *scopeTable, columns ...*Column) error {
+	for _, column := range columns {
+		if column != nil && !table.hasColumn(column.Name) {
+			return &ErrUnknownColumn{Table: table.ref.String(), Column: column.Name.String()}
+		}
+	}
+	return nil
+}
+
+func (r *columnResolver) insert(insert *Insert) error {
+	table, err := r.table(insert.Table, "")
+	if err != nil {
+		return err
+	}
+	if err := r.targetColumns(table, insert.Columns...); err != nil {
+		return err
+	}
+	for _, row := range insert.Rows {
+		if err := r.expr(row, &scope{}); err != nil {
+			return err
+		}
+	}
+	if _, err := r.selectStmt(insert.Select, nil, false, nil); err != nil {
+		return err
+	}
+
+	s := &scope{tables: []*scopeTable{table}}
+	excluded := &scope{tables: []*scopeTable{
+		table,
+		{name: "excluded", ref: "excluded", columns: table.columns, isTable: true, qualifiedOnly: true},
+	}}
+	for _, clause := range insert.Upsert {
+		if clause.Target != nil {
+			if err := r.targetColumns(table, clause.Target.Columns...); err != nil {
+				return err
+			}
+			if err := r.expr(clause.Target.Where, s); err != nil {
+				return err
+			}
+		}
+		if clause.DoUpdate != nil {
+			if err := r.updateExprs(table, clause.DoUpdate.Exprs, excluded); err != nil {
+				return err
+			}
+			if err := r.expr(clause.DoUpdate.Where, excluded); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.expr(insert.ReturningClause, s)
+}
+
+func (r *columnResolver) update(update *Update) error {
+	table, err := r.table(update.Table, "")
+	if err != nil {
+		return err
+	}
+
+	s := &scope{tables: []*scopeTable{table}}
+	if err := r.updateExprs(table, update.Exprs, s); err != nil {
+		return err
+	}
+	if err := r.expr(update.Where, s); err != nil {
+		return err
+	}
+	return r.expr(update.ReturningClause, s)
+}
+
+func (r *columnResolver) updateExprs(table *scopeTable, exprs UpdateExprs, s *scope) error {
+	for _, expr := range exprs {
+		if err := r.targetColumns(table, expr.Column); err != nil {
+			return err
+		}
+		if err := r.expr(expr.Expr, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *columnResolver) delete(delete *Delete) error {
+	table, err := r.table(delete.Table, "")
+	if err != nil {
+		return err
+	}
+	return r.expr(delete.Where, &scope{tables: []*scopeTable{table}})
+}
+
+func (r *columnResolver) alterTable(alterTable *AlterTable) error {
+	table, err := r.table(alterTable.Table, "")
+	if err != nil {
+		return err
+	}
+
+	switch clause := alterTable.AlterTableClause.(type) {
+	case *AlterTableRename:
+		return r.targetColumns(table, clause.OldColumn)
+	case *AlterTableDrop:
+		return r.targetColumns(table, clause.Column)
+	}
+	return nil
+}
+
+// expr resolves the columns referenced by the node, which may contain subqueries.
+func (r *columnResolver) expr(node Node, s *scope) error {
+	return Walk(func(node Node) (bool, error) {
+		switch node := node.(type) {
+		case *Subquery:
+			if node != nil {
+				if r.onSubquery != nil {
+					r.onSubquery(node, s)
+				}
+				if _, err := r.readStatement(node.Select, s); err != nil {
+					return true, err
+				}
+			}
+			return true, nil
+		case *Column:
+			if node != nil {
+				if err := r.column(node, s); err != nil {
+					return true, err
+				}
+			}
+			return true, nil
+		}
+		return false, nil
+	}, node)
+}
+
+// column resolves a column reference, looking up the scope and then its parents.
+func (r *columnResolver) column(column *Column, s *scope) error {
+	var table *scopeTable
+	if column.TableRef != nil {
+		for current := s; current != nil && table == nil; current = current.parent {
+			table = current.lookupTable(column.TableRef.Name)
+		}
+		if table == nil {
+			return &ErrUnknownTable{Name: column.TableRef.Name.String()}
+		}
+		if !table.hasColumn(column.Name) {
+			return &ErrUnknownColumn{Table: column.TableRef.Name.String(), Column: column.Name.String()}
+		}
+	} else {
+		var found bool
+		current := s
+		for ; current != nil && !found; current = current.parent {
+			var err error
+			if table, found, err = current.lookupColumn(column.Name); err != nil {
+				if _, ok := err.(*ErrAmbiguousColumn); !ok || !r.ignoreAmbiguous {
+					return err
+				}
+				found = true
+			}
+		}
+		if !found {
+			return &ErrUnknownColumn{Column: column.Name.String()}
+		}
+		if r.ignoreUnproven && table != nil && table.columns == nil && current.mayReference(column.Name) {
+			table = nil
+		}
+	}
+
+	if r.onColumn != nil {
+		return r.onColumn(column, table)
+	}
+	return nil
+}
+
+// lookupColumn returns the table of the scope an unqualified column refers to.
+// The table is nil if the column refers to a result column alias.
+// Tables with known columns take precedence over aliases, which take precedence over tables with unknown columns.
+func (s *scope) lookupColumn(column Identifier) (*scopeTable, bool, error) {
+	var known, unknown []*scopeTable
+	for _, table := range s.tables {
+		if table.matchesUnqualified(column) {
+			if table.columns != nil {
+				known = append(known, table)
+			} else {
+				unknown = append(unknown, table)
+			}
+		}
+	}
+
+	_, isAlias := s.aliases[normalizeIdentifier(column)]
+	switch {
+	case len(known) > 1:
+		return nil, false, &ErrAmbiguousColumn{Column: column.String()}
+	case len(known) == 1:
+		return known[0], true, nil
+	case isAlias:
+		return nil, true, nil
+	case len(unknown) > 1:
+		return nil, false, &ErrAmbiguousColumn{Column: column.String()}
+	case len(unknown) == 1:
+		return unknown[0], true, nil
+	}
+	return nil, false, nil
+}
+
+// mayReference checks if an unqualified reference to the column may refer to a table of the scope or its parents.
+func (s *scope) mayReference(column Identifier) bool {
+	for current := s; current != nil; current = current.parent {
+		if _, found, err := current.lookupColumn(column); found || err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks if the table is in the scope or its parents.
+func (s *scope) contains(table *scopeTable) bool {
+	for current := s; current != nil; current = current.parent {
+		for _, t := range current.tables {
+			if t == table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupTable returns the table of the scope referenced by name, or nil if there is none.
+func (s *scope) lookupTable(name Identifier) *scopeTable {
+	normalized := normalizeIdentifier(name)
+	for _, table := range s.tables {
+		if table.name != "" && table.name == normalized {
+			return table
+		}
+	}
+	return nil
+}
+
+// newColumnSet returns a set of normalized columns' names.
+func newColumnSet(columns ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		set[normalizeIdentifier(Identifier(column))] = struct{}{}
+	}
+	return set
+}
+
+// normalizeIdentifier returns the identifier unquoted and in lower case,
+// so identifiers can be compared the same way SQLite does.
+func normalizeIdentifier(name Identifier) string {
+	s := string(name)
+	if len(s) >= 2 {
+		switch {
+		case s[0] == '"' && s[len(s)-1] == '"',
+			s[0] == '`' && s[len(s)-1] == '`',
+			s[0] == '[' && s[len(s)-1] == ']':
+			s = s[1 : len(s)-1]
+		}
+	}
+	return strings.ToLower(s)
+}
@@ -0,0 +1,204 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string][]string{
+		"t":  {"a", "b", "c"},
+		"T2": {"a", "d"},
+	}
+
+	type testCase struct {
+		name        string
+		stmt        string
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "select",
+			stmt: "select a, b from t where c > 1 order by a",
+		},
+		{
+			name: "select star",
+			stmt: "select * from t",
+		},
+		{
+			name: "join with alias",
+			stmt: "select t.a, x.d from t join t2 as x on t.a = x.a",
+		},
+		{
+			name: "join using",
+			stmt: "select d from t join t2 using (a)",
+		},
+		{
+			name: "result column alias",
+			stmt: "select a as y from t order by y",
+		},
+		{
+			name: "correlated subquery",
+			stmt: "select a from t where a in (select a from t2 where t2.d = t.b)",
+		},
+		{
+			name: "from subquery",
+			stmt: "select s.total from (select count(*) as total from t) as s",
+		},
+		{
+			name: "rowid",
+			stmt: "select rowid from t",
+		},
+		{
+			name: "quoted identifiers",
+			stmt: `select "A", [b] from "T"`,
+		},
+		{
+			name: "compound select",
+			stmt: "select a from t union select d from t2 order by a",
+		},
+		{
+			name: "insert with upsert",
+			stmt: "insert into t (a, b) values (1, 2) on conflict (a) do update set b = excluded.b where t.c > 0",
+		},
+		{
+			name: "insert with select",
+			stmt: "insert into t (a) select d from t2",
+		},
+		{
+			name: "update",
+			stmt: "update t set a = b + 1 where c = 2",
+		},
+		{
+			name: "delete",
+			stmt: "delete from t where a = 1",
+		},
+		{
+			name: "grant",
+			stmt: "grant insert on t to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+		},
+		{
+			name: "alter table",
+			stmt: "alter table t drop column c",
+		},
+		{
+			name: "create table",
+			stmt: "create table t3 (a int)",
+		},
+		{
+			name: "create view",
+			stmt: "create view v (x, y) as select a, d from t join t2 using (a)",
+		},
+		{
+			name:        "unknown create view table",
+			stmt:        "create view v as select a from t3",
+			expectedErr: &ErrUnknownTable{Name: "t3"},
+		},
+		{
+			name:        "unknown create view column",
+			stmt:        "create view v as select z from t",
+			expectedErr: &ErrUnknownColumn{Column: "z"},
+		},
+		{
+			name:        "unknown column",
+			stmt:        "select x from t",
+			expectedErr: &ErrUnknownColumn{Column: "x"},
+		},
+		{
+			name:        "unknown table",
+			stmt:        "select a from t3",
+			expectedErr: &ErrUnknownTable{Name: "t3"},
+		},
+		{
+			name:        "table not in scope",
+			stmt:        "select t2.a from t",
+			expectedErr: &ErrUnknownTable{Name: "t2"},
+		},
+		{
+			name:        "table referenced by name instead of alias",
+			stmt:        "select t2.a from t2 as x",
+			expectedErr: &ErrUnknownTable{Name: "t2"},
+		},
+		{
+			name:        "unknown column of aliased table",
+			stmt:        "select x.b from t2 as x",
+			expectedErr: &ErrUnknownColumn{Table: "x", Column: "b"},
+		},
+		{
+			name:        "unknown column in subquery",
+			stmt:        "select a from t where a in (select z from t2)",
+			expectedErr: &ErrUnknownColumn{Column: "z"},
+		},
+		{
+			name:        "unknown column of from subquery",
+			stmt:        "select s.a from (select b from t) as s",
+			expectedErr: &ErrUnknownColumn{Table: "s", Column: "a"},
+		},
+		{
+			name:        "unknown join using column",
+			stmt:        "select d from t join t2 using (b)",
+			expectedErr: &ErrUnknownColumn{Table: "t2", Column: "b"},
+		},
+		{
+			name:        "ambiguous column",
+			stmt:        "select a from t join t2 on t.a = t2.a",
+			expectedErr: &ErrAmbiguousColumn{Column: "a"},
+		},
+		{
+			name: "join using column is not ambiguous",
+			stmt: "select a from t join t2 using (a)",
+		},
+		{
+			name:        "unknown insert column",
+			stmt:        "insert into t (z) values (1)",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "z"},
+		},
+		{
+			name:        "unknown excluded column",
+			stmt:        "insert into t (a) values (1) on conflict (a) do update set b = excluded.z",
+			expectedErr: &ErrUnknownColumn{Table: "excluded", Column: "z"},
+		},
+		{
+			name:        "unknown update column",
+			stmt:        "update t set z = 1",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "z"},
+		},
+		{
+			name:        "unknown delete column",
+			stmt:        "delete from t where t.z = 1",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "z"},
+		},
+		{
+			name:        "unknown alter table column",
+			stmt:        "alter table t drop column z",
+			expectedErr: &ErrUnknownColumn{Table: "t", Column: "z"},
+		},
+		{
+			name:        "unknown grant table",
+			stmt:        "grant insert on t3 to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			expectedErr: &ErrUnknownTable{Name: "t3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				ast, err := Parse(tc.stmt)
+				require.NoError(t, err)
+
+				err = ValidateAgainstSchema(ast, schema)
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+					return
+				}
+				require.Equal(t, tc.expectedErr, err)
+			}
+		}(tc))
+	}
+}
@@ -0,0 +1,165 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueryKind classifies a Tableland query.
+type QueryKind int
+
+// All possible query kinds.
+const (
+	// ReadQuery is a single read statement.
+	ReadQuery QueryKind = iota
+	// CreateQuery is a single CREATE TABLE statement.
+	CreateQuery
+	// WriteQuery is a batch of statements that modify a single table's rows, schema or access control.
+	WriteQuery
+)
+
+// String returns the string representation of the kind.
+func (k QueryKind) String() string {
+	switch k {
+	case ReadQuery:
+		return "read"
+	case CreateQuery:
+		return "create"
+	case WriteQuery:
+		return "write"
+	}
+	return fmt.Sprintf("QueryKind(%d)", int(k))
+}
+
+// TablelandQuery is a query that was validated against the Tableland policy.
+type TablelandQuery struct {
+	AST  *AST
+	Kind QueryKind
+
+	// Tables are the unique tables referenced by a read query, or the single table targeted by a write query.
+	Tables []*ValidatedTable
+
+	// CreateTable is the table created by a CREATE query.
+	CreateTable *ValidatedCreateTable
+}
+
+// ParseTableland parses the query and validates it against the full Tableland policy, for a query sent to chainID.
+// On top of the rules the parser enforces by default, like no subqueries in writes or no rowid references:
+//   - the errors of every statement are checked, not only the first one's;
+//   - views and PRAGMA statements are rejected;
+//   - every table name must have the Tableland format, prefix_chainID_tokenID, or prefix_chainID for CREATE;
+//   - created and written tables must belong to chainID, while reads can reference tables of any chain;
+//   - all the statements of a write query must target the same table;
+//   - GRANT and REVOKE roles must be Ethereum addresses, up to MaxAllowedRoles.
+func ParseTableland(sql string, chainID int64) (*TablelandQuery, error) {
+	ast, err := Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ast.Statements {
+		if err := ast.Errors[i]; err != nil {
+			return nil, err
+		}
+	}
+	if len(ast.Statements) == 0 {
+		return nil, &ErrSyntaxError{YaccError: "empty query", Position: 0, Literal: ""}
+	}
+
+	query := &TablelandQuery{AST: ast, Tables: []*ValidatedTable{}}
+	switch stmt := ast.Statements[0].(type) {
+	case ReadStatement:
+		query.Kind = ReadQuery
+		tables, err := validateTablelandReads(stmt)
+		if err != nil {
+			return nil, err
+		}
+		query.Tables = tables
+	case *CreateTable:
+		query.Kind = CreateQuery
+		validTable, err := ValidateCreateTargetTable(stmt.Table)
+		if err != nil {
+			return nil, err
+		}
+		if validTable.ChainID() != chainID {
+			return nil, &ErrWrongChainID{Table: validTable.Name(), ChainID: validTable.ChainID(), Expected: chainID}
+		}
+		query.CreateTable = validTable
+	case WriteStatement, GrantOrRevokeStatement:
+		query.Kind = WriteQuery
+		if err := validateTablelandWrites(query, chainID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &ErrStatementNotAllowed{Statement: statementName(stmt)}
+	}
+
+	return query, nil
+}
+
+// validateTablelandWrites validates the statements of a write query, which must target the same table of chainID.
+func validateTablelandWrites(query *TablelandQuery, chainID int64) error {
+	for _, stmt := range query.AST.Statements {
+		if grantOrRevoke, ok := stmt.(GrantOrRevokeStatement); ok {
+			if err := ValidateRoles(grantOrRevoke, nil, MaxAllowedRoles); err != nil {
+				return err
+			}
+		}
+
+		table := targetTable(stmt)
+		if table == nil {
+			return &ErrStatementNotAllowed{Statement: statementName(stmt)}
+		}
+		validTable, err := ValidateTargetTable(table)
+		if err != nil {
+			return err
+		}
+		if validTable.ChainID() != chainID {
+			return &ErrWrongChainID{Table: validTable.Name(), ChainID: validTable.ChainID(), Expected: chainID}
+		}
+
+		// the tables read by an INSERT ... SELECT can belong to any chain, like the ones of a read query
+		if insert, ok := stmt.(*Insert); ok && insert.Select != nil {
+			if _, err := validateTablelandReads(insert.Select); err != nil {
+				return err
+			}
+		}
+
+		if len(query.Tables) == 0 {
+			query.Tables = append(query.Tables, validTable)
+		} else if normalizeIdentifier(Identifier(query.Tables[0].Name())) != normalizeIdentifier(Identifier(validTable.Name())) {
+			return &ErrMultipleTargetTables{Tables: []string{query.Tables[0].Name(), validTable.Name()}}
+		}
+	}
+	return nil
+}
+
+// validateTablelandReads validates the names of the tables read by the node and returns them, without duplicates.
+func validateTablelandReads(node Node) ([]*ValidatedTable, error) {
+	tables := []*ValidatedTable{}
+	seen := map[string]struct{}{}
+	err := Walk(func(node Node) (bool, error) {
+		if table, ok := node.(*Table); ok && table != nil && table.IsTarget {
+			validTable, err := ValidateTargetTable(table)
+			if err != nil {
+				return true, err
+			}
+			if _, ok := seen[normalizeIdentifier(table.Name)]; !ok {
+				seen[normalizeIdentifier(table.Name)] = struct{}{}
+				tables = append(tables, validTable)
+			}
+		}
+		return false, nil
+	}, node)
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// statementName returns the name of the statement's kind, e.g. CREATE VIEW.
+func statementName(stmt Statement) string {
+	if _, ok := stmt.(*CreateView); ok {
+		return "CREATE VIEW"
+	}
+	return strings.ToUpper(strings.SplitN(stmt.String(), " ", 2)[0])
+}
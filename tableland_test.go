@@ -0,0 +1,213 @@
+package sqlparser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTableland(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		query       string
+		kind        QueryKind
+		tables      []string
+		createTable string
+	}
+
+	tests := []testCase{
+		{
+			name:   "read",
+			query:  "select a from t_1_1 join t_2_5 on t_1_1.a = t_2_5.a where a in (select a from t_1_1)",
+			kind:   ReadQuery,
+			tables: []string{"t_1_1", "t_2_5"},
+		},
+		{
+			name:        "create",
+			query:       "create table t_1 (a int primary key, b text)",
+			kind:        CreateQuery,
+			tables:      []string{},
+			createTable: "t_1",
+		},
+		{
+			name: "writes",
+			query: "insert into t_1_2 values (1); update t_1_2 set a = 2 where a = 1; " +
+				"grant insert on t_1_2 to '0xd43c59d5694ec111eb9e986c233200b14249558d'",
+			kind:   WriteQuery,
+			tables: []string{"t_1_2"},
+		},
+		{
+			name:   "insert select from another chain",
+			query:  "insert into t_1_2 select a from t_2_3",
+			kind:   WriteQuery,
+			tables: []string{"t_1_2"},
+		},
+		{
+			name:   "quoted write table",
+			query:  "delete from \"t_1_2\" where a = 1; alter table t_1_2 add b int",
+			kind:   WriteQuery,
+			tables: []string{"\"t_1_2\""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				query, err := ParseTableland(tc.query, 1)
+				require.NoError(t, err)
+				require.Equal(t, tc.kind, query.Kind)
+
+				tables := []string{}
+				for _, table := range query.Tables {
+					tables = append(tables, table.Name())
+				}
+				require.Equal(t, tc.tables, tables)
+
+				if tc.createTable != "" {
+					require.Equal(t, tc.createTable, query.CreateTable.Name())
+				} else {
+					require.Nil(t, query.CreateTable)
+				}
+			}
+		}(tc))
+	}
+}
+
+func TestParseTablelandRejections(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name  string
+		query string
+		err   error
+	}
+
+	tests := []testCase{
+		{
+			name:  "syntax error",
+			query: "select a frm t_1_1",
+			err:   &ErrSyntaxError{},
+		},
+		{
+			name:  "empty query",
+			query: "",
+			err:   &ErrSyntaxError{},
+		},
+		{
+			name:  "subquery in write",
+			query: "update t_1_1 set a = (select max(a) from t_1_1)",
+			err:   &ErrStatementContainsSubquery{},
+		},
+		{
+			name:  "rowid in write",
+			query: "insert into t_1_1 (rowid) values (1)",
+			err:   &ErrRowIDNotAllowed{},
+		},
+		{
+			name:  "non allowlisted function",
+			query: "select random() from t_1_1",
+			err:   &ErrNoSuchFunction{},
+		},
+		{
+			name:  "text too long",
+			query: "insert into t_1_1 values ('" + strings.Repeat("a", MaxTextLength+1) + "')",
+			err:   &ErrTextTooLong{},
+		},
+		{
+			name:  "maintenance statement",
+			query: "vacuum",
+			err:   &ErrMaintenanceStatementNotAllowed{},
+		},
+		{
+			name:  "error in a later statement",
+			query: "insert into t_1_1 values (1); update t_1_1 set rowid = 1",
+			err:   &ErrRowIDNotAllowed{},
+		},
+		{
+			name:  "view",
+			query: "create view v_1 as select a from t_1_1",
+			err:   &ErrStatementNotAllowed{},
+		},
+		{
+			name:  "pragma",
+			query: "pragma table_info(t_1_1)",
+			err:   &ErrStatementNotAllowed{},
+		},
+		{
+			name:  "read table name format",
+			query: "select a from t",
+			err:   &ErrTableNameWrongFormat{},
+		},
+		{
+			name:  "create table name format",
+			query: "create table t (a int)",
+			err:   &ErrTableNameWrongFormat{},
+		},
+		{
+			name:  "write table name format",
+			query: "insert into t values (1)",
+			err:   &ErrTableNameWrongFormat{},
+		},
+		{
+			name:  "insert select table name format",
+			query: "insert into t_1_2 select a from foo",
+			err:   &ErrTableNameWrongFormat{},
+		},
+		{
+			name:  "create on another chain",
+			query: "create table t_2 (a int)",
+			err:   &ErrWrongChainID{},
+		},
+		{
+			name:  "write on another chain",
+			query: "insert into t_2_1 values (1)",
+			err:   &ErrWrongChainID{},
+		},
+		{
+			name:  "writes to different tables",
+			query: "insert into t_1_1 values (1); delete from t_1_2 where a = 1",
+			err:   &ErrMultipleTargetTables{},
+		},
+		{
+			name:  "invalid role",
+			query: "grant insert on t_1_1 to 'admin'",
+			err:   &ErrInvalidRole{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				query, err := ParseTableland(tc.query, 1)
+				require.Nil(t, query)
+				require.Error(t, err)
+				// the error, which may be wrapped in the statement's multierror, has the expected type
+				target := reflect.New(reflect.TypeOf(tc.err))
+				require.True(t, errors.As(err, target.Interface()), "unexpected error: %s", err)
+			}
+		}(tc))
+	}
+
+	t.Run("details", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseTableland("insert into t_1_1 values (1); delete from t_1_2 where a = 1", 1)
+		var multipleTables *ErrMultipleTargetTables
+		require.ErrorAs(t, err, &multipleTables)
+		require.Equal(t, []string{"t_1_1", "t_1_2"}, multipleTables.Tables)
+
+		_, err = ParseTableland("insert into t_2_1 values (1)", 1)
+		var wrongChain *ErrWrongChainID
+		require.ErrorAs(t, err, &wrongChain)
+		require.Equal(t, &ErrWrongChainID{Table: "t_2_1", ChainID: 2, Expected: 1}, wrongChain)
+	})
+}
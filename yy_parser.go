@@ -40,6 +40,31 @@ func isRowID(column Identifier) bool {
 	return false
 }
 
+func endsWithRowIDOrderingTerm(orderBy OrderBy) bool {
+	if len(orderBy) == 0 {
+		return false
+	}
+
+	term := orderBy[len(orderBy)-1]
+	column, ok := term.Expr.(*Column)
+	return ok && column.TableRef == nil && string(column.Name) == "rowid" && term.Direction == AscStr && term.Nulls == NullsNil
+}
+
+// likePatternLength returns the length in bytes of a LIKE or GLOB pattern, if it's a string literal.
+func likePatternLength(operator string, pattern Expr) (int, bool) {
+	switch operator {
+	case LikeStr, NotLikeStr, GlobStr, NotGlobStr:
+	default:
+		return 0, false
+	}
+
+	value, ok := pattern.(*Value)
+	if !ok || value.Type != StrValue {
+		return 0, false
+	}
+	return len(bytes.ReplaceAll(value.Value, []byte("''"), []byte("'"))), true
+}
+
 type yySymType struct {
 	yys                  int
 	bool                 bool
@@ -97,6 +122,9 @@ type yySymType struct {
 	collateOpt           Identifier
 	joinOperator         *JoinOperator
 	param                *Param
+	windowDef            *WindowDef
+	frameSpec            *FrameSpec
+	frameBound           *FrameBound
 }
 
 const IDENTIFIER = 57346
@@ -143,70 +171,89 @@ const EXCEPT = 57386
 const INTERSECT = 57387
 const CREATE = 57388
 const TABLE = 57389
-const INT = 57390
-const BLOB = 57391
-const PRIMARY = 57392
-const KEY = 57393
-const UNIQUE = 57394
-const CHECK = 57395
-const DEFAULT = 57396
-const GENERATED = 57397
-const ALWAYS = 57398
-const STORED = 57399
-const VIRTUAL = 57400
-const CONSTRAINT = 57401
-const INSERT = 57402
-const INTO = 57403
-const VALUES = 57404
-const DELETE = 57405
-const UPDATE = 57406
-const SET = 57407
-const CONFLICT = 57408
-const DO = 57409
-const NOTHING = 57410
-const GRANT = 57411
-const TO = 57412
-const REVOKE = 57413
-const ALTER = 57414
-const RENAME = 57415
-const COLUMN = 57416
-const ADD = 57417
-const DROP = 57418
-const RIGHT = 57419
-const FULL = 57420
-const INNER = 57421
-const LEFT = 57422
-const NATURAL = 57423
-const OUTER = 57424
-const CROSS = 57425
-const JOIN = 57426
-const ON = 57427
-const USING = 57428
-const OR = 57429
-const ANDOP = 57430
-const NOT = 57431
-const IS = 57432
-const ISNOT = 57433
-const MATCH = 57434
-const GLOB = 57435
-const REGEXP = 57436
-const LIKE = 57437
-const BETWEEN = 57438
-const IN = 57439
-const ISNULL = 57440
-const NOTNULL = 57441
-const NE = 57442
-const LE = 57443
-const GE = 57444
-const INEQUALITY = 57445
-const ESCAPE = 57446
-const LSHIFT = 57447
-const RSHIFT = 57448
-const CONCAT = 57449
-const JSON_EXTRACT_OP = 57450
-const JSON_UNQUOTE_EXTRACT_OP = 57451
-const COLLATE = 57452
-const UNARY = 57453
+const VIEW = 57390
+const INT = 57391
+const BLOB = 57392
+const PRIMARY = 57393
+const KEY = 57394
+const UNIQUE = 57395
+const CHECK = 57396
+const DEFAULT = 57397
+const GENERATED = 57398
+const ALWAYS = 57399
+const STORED = 57400
+const VIRTUAL = 57401
+const CONSTRAINT = 57402
+const INSERT = 57403
+const INTO = 57404
+const VALUES = 57405
+const DELETE = 57406
+const UPDATE = 57407
+const SET = 57408
+const CONFLICT = 57409
+const DO = 57410
+const NOTHING = 57411
+const GRANT = 57412
+const TO = 57413
+const REVOKE = 57414
+const ALTER = 57415
+const RENAME = 57416
+const COLUMN = 57417
+const ADD = 57418
+const DROP = 57419
+const VACUUM = 57420
+const ANALYZE = 57421
+const REINDEX = 57422
+const PRAGMA = 57423
+const OVER = 57424
+const PARTITION = 57425
+const ROWS = 57426
+const RANGE = 57427
+const GROUPS = 57428
+const UNBOUNDED = 57429
+const PRECEDING = 57430
+const FOLLOWING = 57431
+const CURRENT = 57432
+const ROW = 57433
+const EXCLUDE = 57434
+const NO = 57435
+const OTHERS = 57436
+const TIES = 57437
+const RIGHT = 57438
+const FULL = 57439
+const INNER = 57440
+const LEFT = 57441
+const NATURAL = 57442
+const OUTER = 57443
+const CROSS = 57444
+const JOIN = 57445
+const ON = 57446
+const USING = 57447
+const OR = 57448
+const ANDOP = 57449
+const NOT = 57450
+const IS = 57451
+const ISNOT = 57452
+const MATCH = 57453
+const GLOB = 57454
+const REGEXP = 57455
+const LIKE = 57456
+const BETWEEN = 57457
+const IN = 57458
+const ISNULL = 57459
+const NOTNULL = 57460
+const NE = 57461
+const LE = 57462
+const GE = 57463
+const INEQUALITY = 57464
+const ESCAPE = 57465
+const LSHIFT = 57466
+const RSHIFT = 57467
+const CONCAT = 57468
+const JSON_EXTRACT_OP = 57469
+const JSON_UNQUOTE_EXTRACT_OP = 57470
+const COLLATE = 57471
+const UNARY = 57472
 
 var yyToknames = [...]string{
 	"$end",
@@ -262,6 +309,7 @@ var yyToknames = [...]string{
 	"INTERSECT",
 	"CREATE",
 	"TABLE",
+	"VIEW",
 	"INT",
 	"BLOB",
 	"PRIMARY",
@@ -291,6 +339,24 @@ var yyToknames = [...]string{
 	"COLUMN",
 	"ADD",
 	"DROP",
+	"VACUUM",
+	"ANALYZE",
+	"REINDEX",
+	"PRAGMA",
+	"OVER",
+	"PARTITION",
+	"ROWS",
+	"RANGE",
+	"GROUPS",
+	"UNBOUNDED",
+	"PRECEDING",
+	"FOLLOWING",
+	"CURRENT",
+	"ROW",
+	"EXCLUDE",
+	"NO",
+	"OTHERS",
+	"TIES",
 	"RIGHT",
 	"FULL",
 	"INNER",
@@ -349,459 +415,568 @@ var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 74,
-	18, 84,
-	-2, 132,
-	-1, 190,
-	83, 56,
-	84, 56,
-	85, 56,
-	86, 56,
-	-2, 36,
-	-1, 191,
-	83, 56,
-	84, 56,
-	85, 56,
-	86, 56,
-	-2, 37,
-	-1, 264,
-	1, 188,
-	16, 188,
-	17, 188,
-	19, 188,
-	-2, 201,
-	-1, 329,
-	1, 189,
-	16, 189,
-	17, 189,
-	19, 189,
-	-2, 201,
+	-1, 22,
+	49, 27,
+	50, 27,
+	51, 27,
+	-2, 89,
+	-1, 51,
+	49, 30,
+	50, 30,
+	51, 30,
+	-2, 24,
+	-1, 80,
+	49, 28,
+	50, 28,
+	51, 28,
+	-2, 89,
+	-1, 108,
+	18, 104,
+	-2, 153,
+	-1, 115,
+	49, 31,
+	50, 31,
+	51, 31,
+	-2, 89,
+	-1, 145,
+	49, 29,
+	50, 29,
+	51, 29,
+	-2, 23,
+	-1, 200,
+	49, 32,
+	50, 32,
+	51, 32,
+	-2, 26,
+	-1, 307,
+	102, 76,
+	103, 76,
+	104, 76,
+	105, 76,
+	-2, 55,
+	-1, 308,
+	102, 76,
+	103, 76,
+	104, 76,
+	105, 76,
+	-2, 56,
+	-1, 316,
+	1, 235,
+	16, 235,
+	17, 235,
+	19, 235,
+	-2, 250,
+	-1, 385,
+	1, 236,
+	16, 236,
+	17, 236,
+	19, 236,
+	-2, 250,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1736
+const yyLast = 2063
 
 var yyAct = [...]int16{
-	292, 473, 456, 63, 438, 75, 358, 243, 61, 386,
-	165, 278, 325, 330, 307, 305, 324, 389, 271, 279,
-	190, 314, 163, 186, 168, 229, 291, 191, 222, 152,
-	5, 252, 130, 99, 471, 59, 97, 111, 112, 113,
-	114, 106, 107, 108, 109, 110, 115, 116, 117, 128,
-	128, 236, 96, 69, 100, 368, 39, 249, 52, 115,
-	116, 117, 128, 383, 43, 146, 147, 148, 150, 151,
-	55, 60, 308, 41, 89, 90, 106, 107, 108, 109,
-	110, 115, 116, 117, 128, 95, 108, 109, 110, 115,
-	116, 117, 128, 57, 377, 170, 379, 380, 381, 382,
-	387, 388, 428, 427, 59, 187, 426, 194, 195, 196,
-	197, 198, 199, 200, 201, 202, 203, 204, 205, 206,
-	207, 208, 209, 210, 211, 188, 235, 273, 220, 172,
-	363, 174, 393, 378, 336, 390, 155, 93, 253, 213,
-	60, 192, 370, 432, 250, 273, 345, 74, 366, 227,
-	338, 339, 340, 337, 233, 466, 91, 242, 173, 238,
-	239, 273, 344, 93, 225, 245, 244, 40, 76, 86,
-	88, 87, 77, 244, 78, 79, 80, 248, 417, 256,
-	257, 40, 100, 224, 227, 40, 40, 37, 246, 226,
-	241, 175, 41, 176, 177, 452, 40, 189, 276, 451,
-	274, 272, 474, 475, 16, 308, 326, 143, 254, 255,
-	277, 237, 94, 287, 284, 258, 276, 422, 274, 272,
-	162, 285, 414, 283, 217, 216, 215, 218, 219, 214,
-	192, 296, 276, 44, 274, 272, 46, 45, 92, 171,
-	40, 240, 40, 288, 411, 371, 48, 372, 373, 171,
-	313, 170, 40, 326, 294, 33, 317, 359, 187, 319,
-	157, 299, 266, 267, 54, 161, 187, 260, 224, 35,
-	36, 51, 311, 316, 320, 321, 221, 331, 318, 143,
-	333, 420, 421, 439, 440, 334, 323, 346, 53, 270,
-	347, 16, 335, 171, 265, 268, 351, 164, 341, 429,
-	342, 251, 360, 361, 277, 41, 103, 38, 192, 16,
-	171, 350, 9, 367, 192, 230, 193, 171, 230, 362,
-	295, 365, 104, 445, 41, 282, 17, 7, 171, 18,
-	19, 364, 16, 26, 384, 20, 29, 21, 22, 180,
-	281, 40, 331, 376, 375, 280, 30, 31, 32, 24,
-	396, 394, 145, 397, 49, 395, 391, 392, 354, 356,
-	355, 385, 469, 468, 399, 309, 464, 42, 398, 137,
-	138, 139, 140, 357, 410, 111, 112, 113, 114, 106,
-	107, 108, 109, 110, 115, 116, 117, 128, 424, 47,
-	418, 309, 458, 349, 454, 171, 309, 453, 349, 450,
-	171, 343, 171, 171, 430, 349, 405, 349, 348, 322,
-	171, 244, 309, 312, 437, 433, 244, 441, 442, 40,
-	436, 309, 310, 300, 446, 40, 443, 444, 281, 244,
-	448, 431, 232, 447, 349, 434, 369, 41, 327, 457,
-	262, 41, 41, 76, 86, 88, 87, 77, 169, 78,
-	79, 80, 193, 68, 462, 290, 17, 171, 81, 18,
-	19, 247, 71, 472, 67, 20, 181, 21, 22, 16,
-	170, 435, 477, 457, 374, 478, 467, 476, 425, 423,
-	416, 413, 482, 412, 408, 82, 407, 404, 400, 304,
-	223, 281, 156, 159, 158, 154, 153, 101, 415, 481,
-	41, 282, 403, 402, 86, 88, 87, 41, 144, 409,
-	315, 23, 41, 1, 62, 406, 25, 306, 171, 4,
-	2, 15, 14, 13, 167, 166, 12, 11, 303, 10,
-	261, 41, 144, 83, 263, 328, 329, 84, 185, 85,
-	275, 234, 455, 160, 102, 259, 98, 27, 50, 228,
-	353, 332, 131, 56, 470, 171, 65, 64, 105, 28,
-	171, 264, 34, 182, 127, 66, 120, 119, 118, 269,
-	298, 419, 70, 171, 293, 149, 73, 72, 6, 122,
-	121, 126, 123, 171, 136, 135, 134, 141, 142, 129,
-	124, 125, 133, 132, 137, 138, 139, 140, 8, 3,
-	111, 112, 113, 114, 106, 107, 108, 109, 110, 115,
-	116, 117, 128, 0, 171, 183, 184, 171, 0, 479,
-	122, 121, 126, 123, 0, 136, 135, 134, 141, 142,
-	129, 124, 125, 133, 132, 137, 138, 139, 140, 178,
-	0, 111, 112, 113, 114, 106, 107, 108, 109, 110,
-	115, 116, 117, 128, 0, 0, 0, 0, 0, 0,
-	0, 179, 0, 0, 0, 0, 0, 0, 122, 121,
-	126, 123, 0, 136, 135, 134, 141, 142, 129, 124,
-	125, 133, 132, 137, 138, 139, 140, 480, 0, 111,
-	112, 113, 114, 106, 107, 108, 109, 110, 115, 116,
-	117, 128, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 122, 121, 126, 123,
-	0, 136, 135, 134, 141, 142, 129, 124, 125, 133,
-	132, 137, 138, 139, 140, 465, 0, 111, 112, 113,
-	114, 106, 107, 108, 109, 110, 115, 116, 117, 128,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 122, 121, 126, 123, 0, 136, 135,
-	134, 141, 142, 129, 124, 125, 133, 132, 137, 138,
-	139, 140, 463, 0, 111, 112, 113, 114, 106, 107,
-	108, 109, 110, 115, 116, 117, 128, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 122, 121, 126, 123, 0, 136, 135, 134, 141,
-	142, 129, 124, 125, 133, 132, 137, 138, 139, 140,
-	461, 0, 111, 112, 113, 114, 106, 107, 108, 109,
-	110, 115, 116, 117, 128, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 122, 121,
-	126, 123, 0, 136, 135, 134, 141, 142, 129, 124,
-	125, 133, 132, 137, 138, 139, 140, 460, 0, 111,
-	112, 113, 114, 106, 107, 108, 109, 110, 115, 116,
-	117, 128, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 122, 121, 126, 123,
-	0, 136, 135, 134, 141, 142, 129, 124, 125, 133,
-	132, 137, 138, 139, 140, 459, 0, 111, 112, 113,
-	114, 106, 107, 108, 109, 110, 115, 116, 117, 128,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 122, 121, 126, 123, 0, 136, 135,
-	134, 141, 142, 129, 124, 125, 133, 132, 137, 138,
-	139, 140, 449, 0, 111, 112, 113, 114, 106, 107,
-	108, 109, 110, 115, 116, 117, 128, 0, 0, 0,
+	85, 565, 558, 203, 505, 410, 543, 523, 205, 409,
+	449, 370, 468, 232, 465, 403, 45, 133, 100, 381,
+	86, 386, 380, 363, 307, 84, 371, 350, 348, 229,
+	308, 208, 192, 5, 280, 96, 355, 87, 274, 142,
+	125, 301, 223, 541, 77, 11, 149, 150, 151, 152,
+	153, 158, 159, 160, 171, 171, 44, 76, 108, 151,
+	152, 153, 158, 159, 160, 171, 415, 298, 351, 127,
+	158, 159, 160, 171, 43, 287, 408, 130, 62, 140,
+	143, 88, 466, 467, 501, 54, 56, 56, 121, 121,
+	56, 185, 186, 187, 188, 190, 191, 144, 101, 500,
+	56, 56, 154, 155, 156, 157, 149, 150, 151, 152,
+	153, 158, 159, 160, 171, 499, 472, 56, 56, 423,
+	44, 102, 112, 114, 113, 103, 56, 104, 105, 106,
+	422, 481, 199, 51, 136, 136, 44, 135, 112, 114,
+	113, 425, 426, 427, 424, 195, 579, 469, 245, 566,
+	246, 247, 248, 249, 250, 251, 252, 253, 254, 255,
+	256, 257, 258, 259, 260, 261, 262, 263, 75, 286,
+	351, 272, 244, 588, 134, 134, 580, 572, 211, 56,
+	589, 56, 122, 120, 365, 432, 225, 570, 211, 243,
+	211, 312, 445, 513, 284, 201, 450, 365, 288, 289,
+	213, 210, 302, 89, 215, 294, 216, 217, 276, 295,
+	277, 230, 265, 233, 417, 145, 548, 549, 550, 299,
+	127, 518, 305, 297, 439, 517, 441, 442, 443, 444,
+	273, 55, 57, 132, 494, 60, 234, 235, 324, 325,
+	143, 575, 414, 211, 290, 73, 74, 292, 535, 119,
+	200, 293, 63, 137, 138, 65, 64, 35, 303, 304,
+	306, 58, 117, 118, 321, 331, 278, 311, 211, 137,
+	138, 123, 128, 44, 367, 35, 366, 364, 44, 326,
+	56, 440, 339, 225, 310, 35, 211, 367, 382, 366,
+	364, 233, 35, 544, 545, 24, 486, 365, 431, 354,
+	35, 335, 276, 574, 277, 332, 573, 131, 576, 278,
+	139, 342, 67, 291, 337, 478, 269, 268, 267, 270,
+	271, 266, 475, 116, 212, 404, 214, 328, 352, 82,
+	81, 391, 369, 359, 392, 376, 387, 357, 24, 395,
+	382, 377, 46, 375, 379, 405, 406, 433, 411, 434,
+	435, 41, 42, 202, 22, 211, 69, 70, 318, 319,
+	211, 78, 211, 211, 48, 49, 50, 419, 373, 56,
+	389, 390, 10, 211, 362, 407, 533, 413, 210, 506,
+	507, 211, 420, 358, 79, 230, 360, 367, 421, 366,
+	364, 317, 320, 61, 428, 204, 230, 446, 490, 429,
+	219, 369, 80, 300, 388, 387, 437, 438, 115, 59,
+	394, 47, 281, 72, 338, 281, 221, 401, 448, 510,
+	463, 231, 56, 128, 275, 309, 71, 66, 56, 107,
+	38, 373, 44, 374, 398, 400, 399, 53, 470, 471,
+	227, 436, 474, 473, 71, 184, 464, 539, 538, 322,
+	537, 322, 525, 372, 557, 491, 411, 447, 411, 488,
+	430, 483, 484, 493, 482, 492, 402, 497, 147, 520,
+	322, 519, 378, 495, 457, 516, 211, 343, 504, 457,
+	456, 508, 509, 147, 393, 283, 496, 236, 511, 373,
+	503, 514, 29, 322, 353, 30, 31, 147, 329, 462,
+	147, 32, 485, 33, 34, 322, 323, 147, 148, 44,
+	44, 416, 521, 383, 309, 44, 226, 314, 211, 296,
+	209, 310, 530, 536, 83, 239, 461, 194, 502, 532,
+	498, 542, 489, 487, 480, 211, 224, 477, 476, 460,
+	459, 233, 455, 451, 552, 554, 347, 553, 561, 52,
+	198, 197, 196, 193, 146, 131, 563, 211, 233, 551,
+	569, 211, 568, 564, 129, 577, 479, 309, 112, 114,
+	113, 44, 374, 309, 44, 226, 356, 37, 582, 44,
+	233, 36, 40, 585, 524, 527, 39, 1, 547, 581,
+	567, 546, 512, 458, 211, 349, 4, 2, 211, 21,
+	555, 20, 19, 207, 206, 18, 17, 346, 16, 313,
+	315, 384, 385, 228, 368, 285, 522, 210, 218, 220,
+	327, 524, 141, 165, 164, 169, 166, 279, 177, 176,
+	175, 182, 183, 172, 167, 168, 174, 173, 178, 179,
+	180, 181, 586, 587, 154, 155, 156, 157, 149, 150,
+	151, 152, 153, 158, 159, 160, 171, 454, 453, 397,
+	165, 164, 169, 166, 418, 177, 176, 175, 182, 183,
+	172, 167, 168, 174, 173, 178, 179, 180, 181, 571,
+	578, 154, 155, 156, 157, 149, 150, 151, 152, 153,
+	158, 159, 160, 171, 124, 540, 222, 165, 164, 169,
+	166, 316, 177, 176, 175, 182, 183, 172, 167, 168,
+	174, 173, 178, 179, 180, 181, 241, 242, 154, 155,
+	156, 157, 149, 150, 151, 152, 153, 158, 159, 160,
+	171, 178, 179, 180, 181, 237, 330, 154, 155, 156,
+	157, 149, 150, 151, 152, 153, 158, 159, 160, 171,
+	68, 240, 170, 165, 164, 169, 166, 238, 177, 176,
+	175, 182, 183, 172, 167, 168, 174, 173, 178, 179,
+	180, 181, 163, 162, 154, 155, 156, 157, 149, 150,
+	151, 152, 153, 158, 159, 160, 171, 161, 165, 164,
+	169, 166, 361, 177, 176, 175, 182, 183, 172, 167,
+	168, 174, 173, 178, 179, 180, 181, 556, 341, 154,
+	155, 156, 157, 149, 150, 151, 152, 153, 158, 159,
+	160, 171, 97, 336, 189, 99, 6, 25, 23, 12,
+	7, 165, 164, 169, 166, 9, 177, 176, 175, 182,
+	183, 172, 167, 168, 174, 173, 178, 179, 180, 181,
+	571, 534, 154, 155, 156, 157, 149, 150, 151, 152,
+	153, 158, 159, 160, 171, 14, 8, 3, 165, 164,
+	169, 166, 0, 177, 176, 175, 182, 183, 172, 167,
+	168, 174, 173, 178, 179, 180, 181, 531, 0, 154,
+	155, 156, 157, 149, 150, 151, 152, 153, 158, 159,
+	160, 171, 165, 164, 169, 166, 0, 177, 176, 175,
+	182, 183, 172, 167, 168, 174, 173, 178, 179, 180,
+	181, 529, 0, 154, 155, 156, 157, 149, 150, 151,
+	152, 153, 158, 159, 160, 171, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 165, 164, 169, 166,
+	0, 177, 176, 175, 182, 183, 172, 167, 168, 174,
+	173, 178, 179, 180, 181, 528, 0, 154, 155, 156,
+	157, 149, 150, 151, 152, 153, 158, 159, 160, 171,
+	0, 0, 165, 164, 169, 166, 0, 177, 176, 175,
+	182, 183, 172, 167, 168, 174, 173, 178, 179, 180,
+	181, 526, 0, 154, 155, 156, 157, 149, 150, 151,
+	152, 153, 158, 159, 160, 171, 165, 164, 169, 166,
+	0, 177, 176, 175, 182, 183, 172, 167, 168, 174,
+	173, 178, 179, 180, 181, 515, 0, 154, 155, 156,
+	157, 149, 150, 151, 152, 153, 158, 159, 160, 171,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 122, 121, 126, 123, 0, 136, 135, 134, 141,
-	142, 129, 124, 125, 133, 132, 137, 138, 139, 140,
-	401, 0, 111, 112, 113, 114, 106, 107, 108, 109,
-	110, 115, 116, 117, 128, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 122, 121,
-	126, 123, 0, 136, 135, 134, 141, 142, 129, 124,
-	125, 133, 132, 137, 138, 139, 140, 352, 0, 111,
-	112, 113, 114, 106, 107, 108, 109, 110, 115, 116,
-	117, 128, 0, 0, 0, 0, 0, 0, 0, 302,
-	0, 0, 0, 0, 0, 0, 122, 121, 126, 123,
-	0, 136, 135, 134, 141, 142, 129, 124, 125, 133,
-	132, 137, 138, 139, 140, 0, 0, 111, 112, 113,
-	114, 106, 107, 108, 109, 110, 115, 116, 117, 128,
-	301, 0, 122, 121, 126, 123, 0, 136, 135, 134,
-	141, 142, 129, 124, 125, 133, 132, 137, 138, 139,
-	140, 0, 0, 111, 112, 113, 114, 106, 107, 108,
-	109, 110, 115, 116, 117, 128, 122, 121, 126, 123,
-	0, 136, 135, 134, 141, 142, 129, 124, 125, 133,
-	132, 137, 138, 139, 140, 297, 0, 111, 112, 113,
-	114, 106, 107, 108, 109, 110, 115, 116, 117, 128,
-	0, 0, 0, 0, 0, 0, 0, 122, 121, 126,
-	123, 289, 136, 135, 134, 141, 142, 129, 124, 125,
-	133, 132, 137, 138, 139, 140, 0, 0, 111, 112,
-	113, 114, 106, 107, 108, 109, 110, 115, 116, 117,
-	128, 0, 0, 0, 0, 0, 0, 0, 231, 0,
-	0, 0, 0, 122, 121, 126, 123, 0, 136, 135,
-	134, 141, 142, 129, 124, 125, 133, 132, 137, 138,
-	139, 140, 0, 0, 111, 112, 113, 114, 106, 107,
-	108, 109, 110, 115, 116, 117, 128, 0, 0, 0,
-	122, 121, 126, 123, 0, 136, 135, 134, 141, 142,
-	129, 124, 125, 133, 132, 137, 138, 139, 140, 0,
-	0, 111, 112, 113, 114, 106, 107, 108, 109, 110,
-	115, 116, 117, 128, 122, 121, 126, 123, 0, 136,
-	135, 134, 141, 142, 129, 124, 125, 133, 132, 137,
-	138, 139, 140, 0, 0, 111, 112, 113, 114, 106,
-	107, 108, 109, 110, 115, 116, 117, 128, 122, 121,
-	126, 123, 0, 136, 135, 134, 141, 142, 129, 124,
-	125, 133, 132, 137, 138, 139, 140, 0, 0, 111,
-	112, 113, 114, 106, 107, 108, 109, 110, 115, 116,
-	117, 128, 121, 126, 123, 0, 136, 135, 134, 141,
-	142, 129, 124, 125, 133, 132, 137, 138, 139, 140,
-	0, 0, 111, 112, 113, 114, 106, 107, 108, 109,
-	110, 115, 116, 117, 128, 126, 123, 0, 136, 135,
-	134, 141, 142, 129, 124, 125, 133, 132, 137, 138,
-	139, 140, 0, 0, 111, 112, 113, 114, 106, 107,
-	108, 109, 110, 115, 116, 117, 128, 41, 76, 86,
-	88, 87, 77, 0, 78, 79, 80, 0, 68, 0,
-	0, 0, 0, 81, 0, 0, 0, 71, 0, 67,
-	0, 0, 41, 76, 86, 88, 87, 77, 0, 78,
-	79, 80, 0, 68, 0, 0, 0, 0, 81, 0,
-	82, 0, 71, 0, 67, 0, 0, 0, 0, 0,
-	0, 0, 41, 76, 86, 88, 87, 77, 0, 78,
-	79, 80, 0, 68, 0, 82, 0, 0, 81, 0,
-	0, 0, 71, 0, 67, 0, 0, 0, 0, 16,
-	0, 0, 0, 0, 0, 0, 0, 0, 83, 0,
-	212, 0, 84, 0, 85, 82, 0, 0, 0, 0,
+	165, 164, 169, 166, 0, 177, 176, 175, 182, 183,
+	172, 167, 168, 174, 173, 178, 179, 180, 181, 452,
+	0, 154, 155, 156, 157, 149, 150, 151, 152, 153,
+	158, 159, 160, 171, 0, 0, 165, 164, 169, 166,
+	0, 177, 176, 175, 182, 183, 172, 167, 168, 174,
+	173, 178, 179, 180, 181, 0, 0, 154, 155, 156,
+	157, 149, 150, 151, 152, 153, 158, 159, 160, 171,
+	165, 164, 169, 166, 396, 177, 176, 175, 182, 183,
+	172, 167, 168, 174, 173, 178, 179, 180, 181, 0,
+	0, 154, 155, 156, 157, 149, 150, 151, 152, 153,
+	158, 159, 160, 171, 345, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 165, 164, 169, 166, 0, 177,
+	176, 175, 182, 183, 172, 167, 168, 174, 173, 178,
+	179, 180, 181, 0, 0, 154, 155, 156, 157, 149,
+	150, 151, 152, 153, 158, 159, 160, 171, 344, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 165, 164,
+	169, 166, 0, 177, 176, 175, 182, 183, 172, 167,
+	168, 174, 173, 178, 179, 180, 181, 0, 0, 154,
+	155, 156, 157, 149, 150, 151, 152, 153, 158, 159,
+	160, 171, 340, 0, 0, 0, 0, 0, 0, 0,
+	165, 164, 169, 166, 0, 177, 176, 175, 182, 183,
+	172, 167, 168, 174, 173, 178, 179, 180, 181, 333,
+	0, 154, 155, 156, 157, 149, 150, 151, 152, 153,
+	158, 159, 160, 171, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 165, 164, 169, 166, 0, 177,
+	176, 175, 182, 183, 172, 167, 168, 174, 173, 178,
+	179, 180, 181, 282, 0, 154, 155, 156, 157, 149,
+	150, 151, 152, 153, 158, 159, 160, 171, 0, 165,
+	164, 169, 166, 0, 177, 176, 175, 182, 183, 172,
+	167, 168, 174, 173, 178, 179, 180, 181, 0, 0,
+	154, 155, 156, 157, 149, 150, 151, 152, 153, 158,
+	159, 160, 171, 0, 0, 0, 0, 165, 164, 169,
+	166, 0, 177, 176, 175, 182, 183, 172, 167, 168,
+	174, 173, 178, 179, 180, 181, 0, 0, 154, 155,
+	156, 157, 149, 150, 151, 152, 153, 158, 159, 160,
+	171, 0, 0, 0, 0, 0, 0, 0, 165, 164,
+	169, 166, 0, 177, 176, 175, 182, 183, 172, 167,
+	168, 174, 173, 178, 179, 180, 181, 0, 0, 154,
+	155, 156, 157, 149, 150, 151, 152, 153, 158, 159,
+	160, 171, 165, 164, 169, 166, 0, 177, 176, 175,
+	182, 183, 172, 167, 168, 174, 173, 178, 179, 180,
+	181, 0, 0, 154, 155, 156, 157, 149, 150, 151,
+	152, 153, 158, 159, 160, 171, 164, 169, 166, 0,
+	177, 176, 175, 182, 183, 172, 167, 168, 174, 173,
+	178, 179, 180, 181, 0, 0, 154, 155, 156, 157,
+	149, 150, 151, 152, 153, 158, 159, 160, 171, 169,
+	166, 0, 177, 176, 175, 182, 183, 172, 167, 168,
+	174, 173, 178, 179, 180, 181, 0, 0, 154, 155,
+	156, 157, 149, 150, 151, 152, 153, 158, 159, 160,
+	171, 44, 102, 112, 114, 113, 103, 0, 104, 105,
+	106, 0, 95, 0, 0, 0, 0, 107, 0, 0,
+	0, 98, 0, 94, 0, 0, 44, 102, 112, 114,
+	113, 103, 0, 104, 105, 106, 0, 95, 0, 334,
+	0, 0, 107, 0, 109, 0, 98, 35, 94, 0,
+	0, 0, 0, 35, 0, 44, 102, 112, 114, 113,
+	103, 0, 104, 105, 106, 0, 95, 0, 13, 109,
+	0, 107, 0, 0, 0, 98, 0, 94, 0, 0,
+	0, 0, 0, 29, 0, 24, 30, 31, 0, 0,
+	560, 24, 32, 562, 33, 34, 0, 0, 109, 0,
+	26, 27, 28, 15, 0, 44, 102, 112, 114, 113,
+	103, 93, 104, 105, 106, 110, 95, 111, 559, 0,
+	0, 107, 0, 0, 0, 98, 0, 94, 0, 0,
+	0, 0, 35, 0, 91, 90, 93, 0, 0, 0,
+	110, 0, 111, 92, 584, 0, 0, 583, 109, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 91,
+	90, 0, 0, 0, 0, 93, 0, 0, 92, 110,
+	24, 111, 0, 0, 44, 102, 112, 114, 113, 103,
+	0, 104, 105, 106, 0, 95, 0, 0, 91, 90,
+	107, 0, 0, 0, 98, 0, 94, 92, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 65, 64, 83, 0, 0, 0, 84, 0, 85,
-	66, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 65, 64, 58, 0,
-	0, 0, 0, 83, 0, 66, 0, 84, 0, 85,
-	0, 0, 41, 76, 86, 88, 87, 77, 0, 78,
-	79, 80, 0, 68, 0, 0, 65, 64, 81, 0,
-	0, 0, 71, 0, 67, 66, 137, 138, 139, 140,
-	0, 286, 111, 112, 113, 114, 106, 107, 108, 109,
-	110, 115, 116, 117, 128, 82, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 93, 0, 109, 0, 110,
+	0, 111, 0, 0, 44, 102, 112, 114, 113, 103,
+	0, 104, 105, 106, 0, 95, 0, 0, 91, 90,
+	107, 0, 0, 0, 98, 0, 94, 92, 0, 44,
+	102, 112, 114, 113, 103, 0, 104, 105, 106, 0,
+	95, 0, 0, 560, 0, 107, 562, 109, 0, 98,
+	0, 94, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 412, 0, 0, 93, 0, 0, 0, 110, 0,
+	111, 0, 109, 44, 102, 112, 114, 113, 103, 0,
+	104, 105, 106, 0, 95, 0, 0, 91, 90, 107,
+	0, 0, 0, 98, 0, 94, 92, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 93, 0, 109, 0, 110, 0,
+	111, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 91, 90, 93,
+	0, 0, 0, 110, 0, 111, 92, 0, 0, 0,
+	44, 102, 112, 114, 113, 103, 0, 104, 105, 106,
+	0, 95, 91, 90, 126, 0, 107, 0, 0, 0,
+	98, 92, 94, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 93, 0, 264, 0, 110, 0, 111,
+	0, 0, 0, 109, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 91, 90, 0, 0,
+	178, 179, 180, 181, 0, 92, 154, 155, 156, 157,
+	149, 150, 151, 152, 153, 158, 159, 160, 171, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 83, 0, 0, 0, 84, 0, 85,
+	93, 0, 0, 0, 110, 0, 111, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 65, 64, 0, 0,
-	0, 0, 0, 0, 0, 66,
+	0, 0, 0, 91, 90, 0, 0, 0, 0, 0,
+	0, 0, 92,
 }
 
 var yyPact = [...]int16{
-	260, -1000, -1000, 330, 314, -1000, -1000, -1000, 297, 202,
-	-1000, -1000, -1000, -1000, -1000, -1000, 224, 120, 275, 508,
-	167, 167, 193, -1000, -1000, -1000, 390, 234, 278, 253,
-	218, -1000, -1000, 508, 1478, -1000, -1000, 508, 508, 85,
-	-1000, -1000, 147, -1000, -1000, -1000, -1000, 121, 508, -1000,
-	-1000, 1608, -1000, 1608, -1000, 482, 290, -1000, -1000, 527,
-	334, -1000, -1000, -1000, 1608, 1608, 1608, 1608, 1508, -1000,
-	-1000, 481, -1000, -1000, 480, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, 477, 213, 479, 478, -1000, -1000, -1000, 205,
-	264, 433, 508, 167, 508, 112, 623, 321, 450, -1000,
-	575, 508, 264, 1478, 437, -1000, 1608, 1608, 1608, 1608,
-	1608, 1608, 1608, 1608, 1608, 1608, 1608, 1608, 1608, 1608,
-	1608, 1608, 1608, 1453, -1000, -1000, 126, 1608, 508, 475,
-	-1000, 503, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, 69, -1000, -1000, -1000, 288,
-	1265, 1231, 415, 1608, 6, -1000, 278, 477, 1608, 1608,
-	173, 89, 508, -1000, 1608, 264, 445, -1000, -1000, 508,
-	-50, -1000, 68, -1000, 269, 58, 58, 58, 1608, 1608,
-	508, 1608, 225, -1000, -1000, 424, -1000, 240, 255, -1000,
-	111, 111, 320, 301, -34, -34, -64, -64, -64, -42,
-	-42, -42, -42, -76, -76, -76, 261, -77, 1528, 1330,
-	1298, 261, 1608, -1000, 475, -1000, -1000, -1000, -1000, -1000,
-	1197, -1000, -1000, 438, -1000, -1000, -1000, -1000, 291, -1000,
-	1608, -1000, -1000, 1160, 1608, 406, -1000, -1000, 1114, 1073,
-	474, -19, -1000, 405, -1000, 1265, -1000, 508, 396, 1608,
-	505, 505, 508, -1000, 508, 508, 1265, 1265, -1000, -1000,
-	231, 392, 188, 422, 141, -1000, -1000, -1000, -1000, 244,
-	250, 437, -1000, -1000, 44, 67, -1000, 437, -1000, -1000,
-	496, -1000, -1000, 384, 145, 129, 1608, 261, -1000, 1608,
-	-1000, 391, 1265, 281, -1000, 1608, 1039, 337, 356, 418,
-	209, 1608, 1608, 114, 1608, -1000, -19, -1000, 76, 508,
-	-1000, -1000, -52, 1265, 420, -1000, 420, 66, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, 189, 508, 141, -1000, 141,
-	-1000, 38, -1000, 1608, 1608, 9, -1000, 47, 47, 47,
-	42, 9, -1000, 320, -1000, -1000, 261, 261, -1000, 1608,
-	-1000, 1265, 1608, 351, -1000, -1000, -1000, 209, -1000, 473,
-	1003, 486, -1000, 472, 389, -1000, 471, -1000, 469, 504,
-	508, 187, 468, 466, -1000, -1000, -1000, 165, 485, -1000,
-	465, 163, 155, 464, 1265, 418, -1000, 1608, 463, 16,
-	-1000, 13, 12, -1000, -1000, -1000, 1265, 1265, -1000, -1000,
-	266, -1000, -1000, 1608, 1608, -1000, 70, 508, 1608, -1000,
-	-1000, 456, 508, 1608, 243, -1000, 1608, 1608, -1000, -1000,
-	498, 498, 298, 1608, 1265, 508, -1000, -1000, -1000, 1608,
-	955, 382, 125, 380, 377, 508, 375, 908, -1000, -1000,
-	-1000, 860, 813, -1000, -1000, 439, 765, 349, 718, -1000,
-	-1000, -1000, 84, 264, -1000, 346, -1000, -92, -1000, -1000,
-	-1000, -1000, 1608, 139, -1000, -1000, 433, -1000, -1000, 508,
-	243, 508, 670, -1000, -1000, -1000, 264, -1000, -1000, -1000,
-	139, -1000, -1000,
+	1566, -32768, -32768, 411, 411, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, 298, -32768, 575, -32768, -32768, -32768, -32768,
+	-32768, -32768, 303, 315, 534, 315, 575, 575, 575, 193,
+	377, 575, 185, 185, 259, 311, -32768, 407, -32768, -32768,
+	425, 575, 575, 42, -32768, 324, 349, 261, 283, -32768,
+	-32768, 508, 1916, 254, -32768, -32768, -32768, -32768, 575, 575,
+	177, 73, -32768, -32768, -32768, -32768, 72, 575, 1795, -32768,
+	-32768, -32768, -32768, 549, 540, 132, 132, -32768, 1916, 1916,
+	303, 534, -32768, 539, 491, 1340, -32768, -32768, -32768, 427,
+	1916, 1916, 1916, 1916, 1916, 1651, -32768, -32768, 538, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, 512, 537,
+	536, 535, -32768, -32768, -32768, 303, 534, 292, 362, 505,
+	575, 185, 575, 124, 384, -32768, -32768, 511, 422, 575,
+	396, 575, -32768, -32768, -32768, -32768, -32768, 562, 562, 470,
+	719, 509, -32768, 676, 324, 508, 1916, 1916, -32768, 1916,
+	1916, 1916, 1916, 1916, 1916, 1916, 1916, 1916, 1916, 1916,
+	1916, 1916, 1916, 1916, 1916, 1916, 1839, -32768, -32768, 199,
+	1916, 575, 409, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, 575, -32768, -32768, -32768, 1405, 388,
+	1340, 1306, 468, 1916, 30, -32768, 226, 1916, 1916, 324,
+	508, 244, 182, -32768, 1916, 362, 503, -32768, -32768, 575,
+	-59, -32768, 142, -32768, 371, 121, 121, 121, 362, 1795,
+	-32768, 506, -32768, -32768, 570, -32768, -32768, 52, 501, -32768,
+	336, 226, 489, -32768, -32768, -32768, -32768, 1916, 1916, 1916,
+	285, -32768, -32768, -32768, 481, 1340, -80, -80, -72, -72,
+	-72, -91, -91, -91, -91, -90, -90, -90, 1853, -31,
+	604, 1405, 1373, 1853, 1916, -32768, 409, -32768, -32768, -32768,
+	-32768, -32768, 1265, -32768, -32768, 1572, -32768, -32768, -32768, 385,
+	-32768, 1916, -32768, -32768, 1227, 1916, 460, -32768, 1192, 1148,
+	-32768, 531, -42, -32768, 1340, -32768, 575, 477, 1916, 571,
+	571, 575, -32768, 575, 575, 340, -32768, 181, 181, 428,
+	269, -32768, -32768, 455, 274, 497, 222, -32768, -32768, -32768,
+	-32768, -32768, 575, -32768, 1340, 1340, -32768, -32768, 327, -32768,
+	1916, 1853, -32768, 1916, -32768, 467, 380, -32768, 1916, 1106,
+	413, 449, 484, 277, 1916, 1916, 60, 1770, -32768, -42,
+	-32768, 169, -32768, -60, 1340, 495, -32768, 495, 137, -32768,
+	-32768, 331, 347, 506, -32768, -32768, 21, 10, 39, 506,
+	-32768, -32768, 567, -32768, -32768, 443, 281, 168, -32768, -32768,
+	-32768, 290, 575, 222, -32768, 222, -32768, 167, -32768, -32768,
+	-32768, 1853, 1853, -32768, -32768, 1340, 1916, 440, -32768, -32768,
+	-32768, -32768, 277, 108, 528, 1062, 641, -32768, 527, 463,
+	-32768, 1340, -32768, -32768, 525, 524, 521, 575, -32768, 1916,
+	1916, -28, -32768, -32768, 40, 40, 40, 7, -28, -32768,
+	428, -32768, -32768, 264, 523, 522, -32768, -32768, -32768, 257,
+	553, -32768, 519, 116, 233, 518, 1340, -32768, 108, -32768,
+	517, 365, -32768, -32768, 1916, 1770, -32768, 1770, 160, 575,
+	1916, -32768, -32768, 1340, 484, -32768, 1916, 515, 6, -32768,
+	-10, -25, -32768, -32768, -32768, 513, 575, 1916, 339, -32768,
+	1916, 1916, -32768, -32768, -32768, 512, 394, 1916, -32768, 104,
+	1916, 1018, 458, -32768, 150, 454, 452, 1340, 575, -32768,
+	-32768, -32768, 575, 435, 984, 581, -32768, -32768, 948, 904,
+	507, 870, 303, 341, 834, -32768, -32768, -32768, 176, 362,
+	-32768, 433, 431, -32768, -102, -32768, -32768, -32768, -32768, -32768,
+	1916, 229, 126, 1916, -32768, 505, -32768, -32768, -32768, 575,
+	339, 575, 790, -32768, -32768, -32768, 437, 1547, -32768, -32768,
+	-32768, 484, 362, -32768, -32768, -32768, 229, -32768, 51, 1720,
+	93, 756, 80, -32768, -32768, -32768, 207, 551, -32768, 585,
+	-32768, -32768, -32768, 46, 79, -32768, -32768, 1601, -32768, -32768,
+	-32768, 51, 548, 76, 85, -32768, -32768, -32768, -32768, -32768,
 }
 
 var yyPgo = [...]int16{
-	0, 327, 599, 29, 598, 578, 0, 8, 577, 576,
-	575, 574, 572, 571, 26, 570, 569, 568, 567, 566,
-	564, 563, 562, 561, 4, 64, 559, 3, 558, 32,
-	11, 19, 12, 147, 554, 93, 553, 36, 22, 551,
-	6, 550, 25, 549, 548, 547, 546, 33, 545, 20,
-	544, 27, 9, 7, 543, 542, 2, 53, 28, 541,
-	1, 540, 17, 538, 23, 13, 536, 535, 5, 16,
-	534, 530, 529, 528, 527, 526, 24, 10, 525, 524,
-	523, 522, 521, 21, 367, 520, 519, 15, 517, 14,
-	515, 18, 514, 513, 511, 31,
+	0, 372, 867, 866, 865, 835, 830, 32, 45, 829,
+	828, 827, 354, 826, 0, 20, 233, 825, 18, 824,
+	823, 822, 17, 5, 25, 808, 792, 9, 787, 773,
+	772, 752, 751, 750, 701, 4, 78, 411, 81, 696,
+	42, 11, 26, 19, 58, 695, 40, 694, 203, 3,
+	664, 15, 659, 34, 627, 44, 16, 622, 39, 620,
+	24, 619, 618, 30, 14, 13, 77, 616, 7, 35,
+	38, 615, 6, 614, 12, 613, 29, 21, 612, 611,
+	98, 22, 610, 609, 608, 133, 607, 606, 605, 31,
+	8, 604, 603, 602, 601, 599, 36, 393, 597, 596,
+	28, 595, 27, 593, 23, 37, 10, 592, 591, 2,
+	590, 589, 588, 1, 587, 581, 577, 41,
 }
 
 var yyR1 = [...]int8{
-	0, 93, 85, 85, 2, 2, 86, 86, 1, 1,
-	1, 1, 1, 1, 94, 94, 3, 3, 26, 26,
-	26, 26, 4, 22, 22, 22, 36, 36, 35, 35,
-	35, 28, 28, 28, 29, 29, 50, 50, 49, 49,
-	49, 49, 30, 30, 30, 31, 31, 51, 51, 91,
-	91, 91, 91, 91, 91, 91, 61, 61, 62, 62,
-	52, 52, 52, 38, 38, 16, 16, 39, 39, 45,
-	45, 46, 46, 47, 21, 21, 21, 48, 48, 48,
-	44, 44, 44, 44, 37, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 7, 7, 7, 7,
-	7, 7, 27, 53, 53, 17, 17, 17, 17, 17,
-	17, 17, 17, 18, 18, 18, 18, 19, 19, 20,
-	20, 41, 41, 41, 58, 58, 58, 57, 12, 12,
-	8, 8, 8, 9, 9, 59, 59, 14, 14, 15,
-	15, 40, 40, 10, 10, 42, 43, 43, 11, 11,
-	5, 63, 63, 64, 23, 23, 23, 23, 67, 67,
-	66, 66, 65, 65, 65, 65, 65, 65, 65, 65,
-	65, 32, 32, 24, 24, 24, 13, 13, 68, 68,
-	68, 60, 60, 60, 71, 71, 70, 70, 69, 69,
-	69, 55, 55, 56, 34, 34, 72, 72, 72, 54,
-	54, 73, 73, 87, 87, 88, 88, 89, 89, 90,
-	90, 74, 75, 77, 77, 78, 78, 79, 76, 80,
-	81, 83, 83, 84, 84, 25, 25, 25, 82, 82,
-	82, 95, 95, 33, 92,
+	0, 114, 98, 98, 2, 2, 2, 2, 2, 99,
+	99, 1, 1, 1, 1, 1, 1, 115, 115, 116,
+	116, 8, 8, 8, 9, 9, 9, 10, 10, 10,
+	11, 11, 11, 7, 7, 37, 37, 37, 37, 12,
+	33, 33, 33, 47, 47, 46, 46, 46, 39, 39,
+	39, 40, 40, 62, 62, 61, 61, 60, 60, 60,
+	60, 41, 41, 41, 42, 42, 63, 63, 104, 104,
+	104, 104, 104, 104, 104, 104, 73, 73, 74, 74,
+	64, 64, 64, 49, 49, 26, 26, 50, 50, 56,
+	56, 57, 57, 58, 32, 32, 32, 59, 59, 59,
+	55, 55, 55, 55, 48, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 15, 15, 15,
+	15, 15, 15, 38, 65, 65, 28, 28, 28, 28,
+	28, 28, 28, 28, 29, 29, 29, 29, 30, 30,
+	31, 31, 52, 52, 52, 52, 70, 70, 70, 70,
+	69, 21, 17, 17, 17, 18, 18, 71, 71, 24,
+	24, 25, 25, 51, 51, 106, 106, 107, 107, 108,
+	108, 108, 112, 112, 112, 109, 109, 109, 110, 110,
+	111, 111, 111, 111, 113, 113, 113, 113, 113, 19,
+	19, 53, 54, 54, 20, 20, 13, 6, 75, 75,
+	76, 34, 34, 34, 34, 79, 79, 78, 78, 77,
+	77, 77, 77, 77, 77, 77, 77, 77, 77, 77,
+	43, 43, 35, 35, 35, 22, 22, 80, 80, 80,
+	72, 72, 72, 83, 83, 82, 82, 81, 81, 81,
+	67, 67, 68, 45, 45, 84, 84, 84, 66, 66,
+	85, 85, 86, 86, 27, 27, 23, 23, 100, 100,
+	101, 101, 102, 102, 103, 103, 87, 88, 90, 90,
+	91, 91, 92, 89, 93, 94, 96, 96, 97, 97,
+	36, 36, 36, 95, 95, 95, 3, 4, 4, 4,
+	4, 4, 4, 5, 5, 5, 16, 16, 16, 16,
+	117, 117, 44, 105,
 }
 
 var yyR2 = [...]int8{
-	0, 1, 2, 2, 1, 1, 1, 3, 1, 1,
-	1, 1, 1, 1, 0, 1, 3, 3, 1, 2,
-	1, 1, 7, 0, 1, 1, 1, 3, 1, 2,
-	3, 0, 1, 2, 1, 1, 2, 2, 2, 4,
-	3, 3, 0, 1, 2, 1, 1, 4, 4, 1,
-	1, 2, 4, 4, 4, 3, 0, 1, 0, 1,
+	0, 1, 2, 2, 1, 1, 1, 1, 1, 1,
+	3, 1, 1, 1, 1, 1, 1, 0, 1, 1,
+	2, 3, 5, 4, 2, 5, 4, 1, 3, 4,
+	2, 3, 4, 1, 1, 1, 2, 1, 1, 7,
+	0, 1, 1, 1, 3, 1, 2, 3, 0, 1,
+	2, 1, 1, 0, 1, 2, 2, 2, 4, 3,
+	3, 0, 1, 2, 1, 1, 4, 4, 1, 1,
+	2, 2, 4, 4, 4, 3, 0, 1, 0, 1,
 	0, 2, 4, 0, 2, 0, 3, 0, 2, 0,
 	3, 1, 3, 3, 0, 1, 1, 0, 2, 2,
 	0, 2, 4, 4, 1, 1, 1, 1, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 5, 2, 2, 2, 3, 3,
-	3, 4, 2, 2, 3, 5, 5, 3, 3, 3,
-	4, 1, 1, 6, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 3, 1, 1, 1, 2, 1,
-	2, 1, 2, 1, 1, 1, 1, 1, 2, 1,
-	2, 1, 1, 1, 2, 1, 3, 3, 2, 3,
-	6, 6, 8, 6, 5, 0, 1, 1, 3, 0,
-	1, 0, 5, 0, 1, 4, 1, 2, 0, 2,
-	7, 1, 3, 3, 1, 1, 1, 1, 0, 1,
-	1, 2, 4, 3, 2, 5, 5, 3, 3, 8,
-	6, 0, 2, 0, 1, 1, 2, 2, 1, 1,
-	1, 0, 1, 1, 0, 1, 2, 3, 6, 5,
-	5, 1, 3, 3, 0, 2, 7, 5, 6, 0,
-	3, 3, 5, 0, 1, 1, 2, 5, 8, 0,
-	4, 4, 5, 1, 1, 1, 3, 7, 3, 6,
-	6, 1, 3, 1, 3, 1, 1, 1, 8, 6,
-	6, 0, 1, 1, 1,
+	3, 4, 2, 2, 2, 3, 5, 5, 3, 3,
+	3, 4, 1, 1, 6, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 3, 1, 1, 1, 2,
+	1, 2, 1, 2, 1, 1, 1, 1, 1, 2,
+	1, 2, 1, 1, 1, 1, 2, 1, 3, 1,
+	3, 2, 6, 6, 8, 7, 6, 0, 1, 1,
+	3, 0, 1, 0, 5, 0, 6, 0, 3, 0,
+	3, 6, 1, 1, 1, 2, 2, 2, 1, 2,
+	2, 2, 2, 2, 0, 3, 3, 2, 2, 0,
+	1, 4, 1, 2, 0, 2, 7, 6, 1, 3,
+	3, 1, 1, 1, 1, 0, 1, 1, 2, 4,
+	5, 3, 2, 5, 5, 3, 3, 3, 8, 6,
+	0, 2, 0, 1, 1, 2, 2, 1, 1, 1,
+	0, 1, 1, 0, 1, 2, 3, 6, 5, 5,
+	1, 3, 3, 0, 2, 7, 5, 6, 0, 3,
+	3, 5, 3, 5, 1, 3, 1, 1, 0, 1,
+	1, 2, 5, 8, 0, 4, 4, 5, 1, 1,
+	1, 3, 7, 3, 6, 6, 1, 3, 1, 3,
+	1, 1, 1, 8, 6, 6, 1, 1, 2, 1,
+	2, 1, 2, 2, 4, 5, 1, 1, 1, 1,
+	0, 1, 1, 1,
 }
 
 var yyChk = [...]int16{
-	-1000, -93, -85, -2, -86, -3, -5, -1, -4, 52,
-	-72, -74, -75, -80, -81, -82, 31, 66, 69, 70,
-	75, 77, 78, -94, 19, -94, 19, -45, -26, 39,
-	49, 50, 51, 53, -22, 45, 46, 67, 32, -37,
-	-33, 4, -84, -25, 66, 70, 69, -84, 53, -1,
-	-44, 37, -3, 35, 46, -37, -36, -35, 120, -6,
-	-37, -7, -92, -27, 119, 118, 127, 26, 15, -57,
-	-12, 24, -8, -9, -33, -68, 5, 9, 11, 12,
-	13, 20, 47, 95, 99, 101, 6, 8, 7, -37,
-	-37, 71, 91, 16, 91, -37, -6, -37, -46, -47,
-	-6, 15, -50, 16, 32, -28, 118, 119, 120, 121,
-	122, 114, 115, 116, 117, 123, 124, 125, -17, -18,
-	-19, 94, 93, 96, 104, 105, 95, -20, 126, 103,
-	-29, 25, 107, 106, 100, 99, 98, 108, 109, 110,
-	111, 101, 102, -33, 5, 18, -6, -6, -6, -10,
-	-6, -6, -3, 15, 15, -57, 15, 47, 15, 15,
-	-54, 60, 15, -38, 33, -77, -78, -79, -76, 15,
-	-27, -33, -37, -25, -37, 79, 81, 82, 16, 38,
-	18, 16, -21, 40, 41, -63, -64, -27, -38, -35,
-	-49, -51, -37, 15, -6, -6, -6, -6, -6, -6,
-	-6, -6, -6, -6, -6, -6, -6, -6, -6, -6,
-	-6, -6, 97, 13, 103, 100, 99, 98, 101, 102,
-	-6, -33, -58, 15, -57, -29, 120, -27, -43, -42,
-	27, 17, 17, -6, -59, 120, 45, -57, -6, -6,
-	68, -3, 68, -53, -27, -6, -38, 16, -53, 107,
-	76, 32, -95, 80, -95, -95, -6, -6, -47, -48,
-	42, -71, 16, -70, -23, 54, 22, 23, 55, -16,
-	34, -91, 90, 16, 89, -61, 87, -91, -30, -31,
-	25, -33, 5, -3, -49, -51, 113, -6, -58, 14,
-	17, -14, -6, -11, -42, 29, -6, 25, -15, -14,
-	17, 16, 16, -73, 15, -87, -88, -89, 91, 16,
-	17, -76, 17, -6, -83, 5, -83, -27, -64, -27,
-	43, 44, 17, -64, -69, -32, 65, 16, -67, -66,
-	-65, -32, -39, 36, 35, -49, 90, 86, 83, 84,
-	85, -49, -31, 17, 17, 17, -6, -6, 17, 16,
-	30, -6, 28, -41, 21, 23, 22, 17, -40, 48,
-	-6, -6, -87, 16, -14, -89, 72, -27, 107, 16,
-	76, 56, 58, 59, -33, -69, -65, 56, 95, 58,
-	59, 60, 61, 25, -6, -14, -52, 91, 92, -62,
-	88, -62, -62, 90, -52, -30, -6, -6, 17, -40,
-	15, 17, 17, 16, 15, 17, -90, 15, 15, 5,
-	-27, 57, 15, 15, 57, 13, 15, 15, -7, -13,
-	118, 119, 62, 15, -6, 15, 90, 90, 90, 33,
-	-6, -14, 73, -53, -14, 15, -53, -6, -24, 40,
-	41, -6, -6, -68, -68, 25, -6, -53, -6, 17,
-	17, 74, 70, 17, 17, -55, -56, -27, 17, 17,
-	17, 17, 15, 17, 17, 17, 71, -38, 17, 16,
-	-34, 126, -6, -60, 63, 64, -77, -56, -24, -33,
-	17, -38, -60,
+	-32768, -114, -98, -2, -99, -7, -13, -6, -3, -5,
+	-1, -8, -9, 52, -4, 87, -84, -87, -88, -93,
+	-94, -95, -12, -10, 69, -11, 84, 85, 86, 67,
+	70, 71, 76, 78, 79, 31, -115, -116, 19, -115,
+	-116, 53, 54, -44, 4, -56, 39, -37, 49, 50,
+	51, -85, 15, -37, -44, -48, -44, -48, 68, 32,
+	-48, -97, -36, 67, 71, 70, -97, 53, -33, 45,
+	46, 19, -1, -48, -48, 126, 15, -55, 37, 35,
+	-12, 69, 46, 16, -24, -14, -15, -105, -38, -48,
+	138, 137, 146, 114, 26, 15, -69, -21, 24, -17,
+	-18, -80, 5, 9, 11, 12, 13, 20, -44, 47,
+	118, 120, 6, 8, 7, -12, 69, -48, -48, 72,
+	110, 16, 110, -48, -47, -46, 139, -14, -48, 15,
+	-66, 15, -16, -22, -80, 5, -44, 137, 138, -16,
+	-14, -57, -58, -14, -56, -85, 15, 16, 17, 137,
+	138, 139, 140, 141, 133, 134, 135, 136, 142, 143,
+	144, -28, -29, -30, 113, 112, 115, 123, 124, 114,
+	-31, 145, 122, 126, 125, 119, 118, 117, 127, 128,
+	129, 130, 120, 121, 18, -14, -14, -14, -14, -19,
+	-14, -14, -7, 15, 15, -69, 15, 15, 15, -56,
+	-85, -66, 61, -49, 33, -90, -91, -92, -89, 15,
+	-38, -44, -48, -36, -48, 80, 82, 83, -62, 16,
+	-61, 32, -39, -40, 25, -44, 5, 18, -75, -76,
+	-38, 25, -65, -38, -80, -80, 17, 16, 38, 16,
+	-32, 40, 41, -55, -24, -14, -14, -14, -14, -14,
+	-14, -14, -14, -14, -14, -14, -14, -14, -14, -14,
+	-14, -14, -14, -14, 116, 13, 122, 119, 118, 117,
+	120, 121, -14, -44, -70, 15, -69, -105, -38, -54,
+	-53, 27, 17, 17, -14, -71, 139, 45, -14, -14,
+	-55, 69, -8, 69, -14, -49, 16, -65, 126, 77,
+	32, -117, 81, -117, -117, -49, -46, -60, -63, -48,
+	15, -40, 139, -83, 16, -82, -34, 55, 22, 23,
+	56, -7, 16, 17, -14, -14, -58, -59, 42, 17,
+	132, -14, -70, 14, 17, -24, -20, -53, 29, -14,
+	25, -25, -24, 17, 16, 16, -86, 15, -100, -101,
+	-102, 110, -89, 17, -14, -96, 5, -96, -38, -76,
+	-38, -26, 34, -104, 109, 16, 108, 106, -73, -104,
+	-41, -42, 25, -44, 5, -7, -60, -63, 17, -76,
+	-81, -43, 66, 16, -79, -78, -77, -43, -38, 43,
+	44, -14, -14, 17, 30, -14, 28, -52, 21, 23,
+	22, 4, 17, -51, 48, -14, -14, -100, 16, -27,
+	-23, -14, 61, -102, 73, 126, 16, 77, -50, 36,
+	35, -60, 109, 109, 105, 102, 103, 104, -60, -42,
+	17, 17, 17, 57, 59, 60, -44, -81, -77, 57,
+	114, 59, 60, 61, 62, 25, -14, 17, -51, -106,
+	88, 15, 17, 17, 16, 15, 17, 16, -103, 15,
+	15, 5, -38, -14, -24, -64, 110, 111, -74, 107,
+	-74, -74, 109, -64, -41, 58, 15, 15, 58, 13,
+	15, 15, -15, -22, -18, -44, 63, 15, -106, 15,
+	33, -14, -27, -23, 74, -65, -24, -14, 15, 109,
+	109, 109, 15, -65, -14, -35, 40, 41, -14, -14,
+	25, -14, -107, 89, -14, 17, 17, 75, 71, 17,
+	17, -65, -67, -68, -38, 17, 17, 4, 17, 17,
+	15, 17, -56, 35, 17, 72, -49, 17, 17, 16,
+	-45, 145, -14, -72, 64, 65, -108, -112, 90, 91,
+	92, -24, -90, -68, -35, -44, 17, 17, -109, 121,
+	93, -14, 96, -49, -72, -113, 98, -110, -109, -14,
+	94, 94, 97, 99, 96, 34, 101, 14, 95, 100,
+	97, -111, -14, 96, 93, -113, 94, 95, 97, 95,
 }
 
 var yyDef = [...]int16{
-	0, -2, 1, 14, 14, 4, 5, 6, 69, 0,
-	8, 9, 10, 11, 12, 13, 23, 0, 0, 0,
-	0, 0, 0, 2, 15, 3, 15, 80, 0, 0,
-	18, 20, 21, 0, 0, 24, 25, 0, 0, 0,
-	84, 263, 0, 253, 255, 256, 257, 0, 0, 7,
-	16, 0, 17, 0, 19, 0, 0, 26, 28, 31,
-	0, 85, 86, 87, 0, 0, 0, 173, 0, 121,
-	122, 0, 124, 125, -2, 126, 127, 128, 129, 130,
-	131, 264, 0, 0, 0, 0, 208, 209, 210, 229,
-	63, 0, 0, 0, 0, 0, 81, 0, 70, 71,
-	74, 0, 63, 0, 0, 29, 0, 0, 0, 0,
+	0, -2, 1, 17, 17, 4, 5, 6, 7, 8,
+	9, 33, 34, 0, 316, 0, 11, 12, 13, 14,
+	15, 16, -2, 0, 0, 0, 317, 319, 321, 0,
+	0, 0, 0, 0, 0, 40, 2, 18, 19, 3,
+	18, 0, 0, 323, 332, 100, 0, 0, 35, 37,
+	38, -2, 0, 0, 318, 320, 104, 322, 0, 0,
+	0, 0, 308, 310, 311, 312, 0, 0, 0, 41,
+	42, 20, 10, 0, 278, 0, 0, 21, 0, 0,
+	-2, 0, 36, 0, 0, 189, 105, 106, 107, 0,
+	0, 0, 0, 0, 219, 0, 142, 143, 0, 145,
+	146, 147, 148, 149, 150, 151, 152, 333, -2, 0,
+	0, 0, 257, 258, 259, -2, 0, 278, 83, 0,
+	0, 0, 0, 0, 53, 43, 45, 48, 0, 0,
+	0, 0, 324, 326, 327, 328, 329, 0, 0, 0,
+	101, 90, 91, 94, 100, -2, 0, 0, 280, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 112, 113, 0, 0, 0, 0,
-	32, 0, 135, 136, 137, 139, 141, 143, 144, 145,
-	146, 147, 149, 34, 35, 0, 105, 106, 107, 0,
-	174, 0, 0, 0, 165, 158, 0, 0, 0, 0,
-	0, 0, 0, 241, 0, 63, 243, 244, 245, 0,
-	0, 132, 0, 254, 0, 261, 261, 261, 0, 0,
-	0, 0, 77, 75, 76, 214, 181, 0, 65, 27,
-	-2, -2, 42, 0, 89, 90, 91, 92, 93, 94,
-	95, 96, 97, 98, 99, 100, 101, 102, 103, 108,
-	109, 110, 0, 114, 0, 138, 140, 142, 148, 150,
-	0, 117, 119, 0, 155, 33, 30, 88, 178, 176,
-	0, 118, 157, 0, 169, 0, 166, 159, 0, 0,
-	0, 233, 227, 0, 133, 64, 242, 0, 0, 0,
-	0, 0, 0, 262, 0, 0, 82, 83, 72, 73,
-	0, 0, 201, 215, -2, 184, 185, 186, 187, 67,
-	0, 0, 49, 50, 0, 0, 57, 0, 38, 43,
-	0, 45, 46, 0, 56, 56, 0, 111, 120, 0,
-	154, 0, 167, 0, 177, 0, 0, 0, 0, 170,
-	171, 0, 0, 233, 0, 228, 234, 235, 0, 0,
-	230, 246, 0, 248, 249, 251, 250, 0, 259, 260,
-	78, 79, 180, 182, 216, 0, 0, 201, 183, -2,
-	190, 0, 22, 0, 0, 60, 51, 58, 58, 58,
-	0, 60, 44, 42, 40, 41, 104, 115, 156, 0,
-	116, 179, 0, 0, 151, 152, 153, 171, 164, 0,
-	0, 0, 226, 0, 0, 236, 239, 134, 0, 0,
-	0, 0, 0, 0, 202, 217, 191, 0, 0, 194,
-	0, 0, 0, 0, 68, 66, 47, 0, 0, 0,
-	59, 0, 0, 55, 48, 39, 168, 175, 123, 163,
-	0, 160, 161, 0, 0, 231, 0, 0, 0, 252,
-	258, 0, 0, 0, 203, 193, 0, 0, 197, 198,
-	0, 0, 0, 0, 61, 0, 52, 53, 54, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 192, 204,
-	205, 0, 0, 206, 207, 0, 0, 0, 0, 162,
-	232, 237, 0, 63, 247, 0, 221, 224, 219, 220,
-	195, 196, 0, 211, 62, 172, 0, 240, 218, 0,
-	203, 0, 0, 200, 212, 213, 63, 222, 223, 225,
-	211, 238, 199,
+	0, 0, 0, 0, 0, 0, 0, 133, 134, 0,
+	0, 0, 0, 156, 157, 158, 160, 162, 164, 165,
+	166, 167, 168, 170, 0, 125, 126, 127, 132, 0,
+	220, 0, 0, 0, 187, 181, 0, 0, 0, 100,
+	-2, 0, 0, 296, 0, 83, 298, 299, 300, 0,
+	0, 153, 0, 309, 0, 330, 330, 330, 83, 0,
+	54, 0, 46, 49, 0, 51, 52, 0, 263, 228,
+	0, 0, 0, 154, 255, 256, 325, 0, 0, 0,
+	97, 95, 96, 22, 0, 190, 109, 110, 111, 112,
+	113, 114, 115, 116, 117, 118, 119, 120, 121, 122,
+	123, 128, 129, 130, 0, 135, 0, 159, 161, 163,
+	169, 171, 0, 138, 140, 0, 177, 179, 108, 224,
+	222, 0, 139, 180, 0, 191, 0, 188, 0, 0,
+	25, 0, 288, 276, 84, 297, 0, 0, 0, 0,
+	0, 0, 331, 0, 0, 85, 44, -2, -2, 61,
+	0, 50, 47, 0, 250, 264, -2, 231, 232, 233,
+	234, 227, 0, 279, 102, 103, 92, 93, 0, 281,
+	0, 131, 141, 0, 176, 0, 0, 223, 0, 0,
+	0, 0, 192, 193, 0, 0, 288, 0, 277, 289,
+	290, 0, 301, 0, 303, 304, 306, 305, 0, 314,
+	315, 87, 0, 0, 68, 69, 0, 77, 0, 0,
+	57, 62, 0, 64, 65, 0, 76, 76, 226, 229,
+	265, 0, 0, 250, 230, -2, 237, 0, 155, 98,
+	99, 124, 136, 178, 137, 225, 0, 0, 172, 173,
+	174, 175, 193, 195, 0, 0, 0, 275, 0, 0,
+	284, 286, 287, 291, 294, 0, 0, 0, 39, 0,
+	0, 80, 70, 71, 78, 78, 78, 0, 80, 63,
+	61, 59, 60, 0, 0, 0, 251, 266, 238, 0,
+	0, 242, 0, 0, 0, 0, 221, 144, 195, 186,
+	0, 0, 182, 183, 0, 0, 282, 0, 0, 0,
+	0, 307, 313, 88, 86, 66, 0, 0, 0, 79,
+	0, 0, 75, 67, 58, 0, 0, 0, 252, 241,
+	0, 0, 245, 246, 247, 0, 0, 0, 185, 197,
+	0, 0, 0, 285, 0, 0, 0, 81, 0, 72,
+	73, 74, 0, 0, 0, 239, 253, 254, 0, 0,
+	0, 0, 89, 0, 0, 184, 283, 292, 0, 83,
+	302, 0, 0, 270, 273, 268, 269, 240, 243, 244,
+	0, 260, 199, 0, 194, 0, 295, 82, 267, 0,
+	252, 0, 0, 249, 261, 262, 0, 0, 202, 203,
+	204, 198, 83, 271, 272, 274, 260, 196, 214, 0,
+	0, 0, 0, 293, 248, 200, 0, 0, 208, 0,
+	205, 206, 207, 0, 0, 217, 218, 0, 209, 215,
+	216, 214, 0, 0, 0, 201, 210, 212, 211, 213,
 }
 
-var yyTok1 = [...]int8{
+var yyTok1 = [...]uint8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 122, 114, 3,
-	15, 17, 120, 118, 16, 119, 18, 121, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 141, 133, 3,
+	15, 17, 139, 137, 16, 138, 18, 140, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 19,
-	108, 107, 109, 20, 3, 3, 3, 3, 3, 3,
+	127, 126, 128, 20, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 115, 3, 127,
+	3, 3, 3, 3, 134, 3, 146,
 }
 
 var yyTok2 = [...]uint8{
@@ -814,8 +989,10 @@ var yyTok2 = [...]uint8{
 	68, 69, 70, 71, 72, 73, 74, 75, 76, 77,
 	78, 79, 80, 81, 82, 83, 84, 85, 86, 87,
 	88, 89, 90, 91, 92, 93, 94, 95, 96, 97,
-	98, 99, 100, 101, 102, 103, 104, 105, 106, 110,
-	111, 112, 113, 116, 117, 123, 124, 125, 126, 128,
+	98, 99, 100, 101, 102, 103, 104, 105, 106, 107,
+	108, 109, 110, 111, 112, 113, 114, 115, 116, 117,
+	118, 119, 120, 121, 122, 123, 124, 125, 129, 130,
+	131, 132, 135, 136, 142, 143, 144, 145, 147,
 }
 
 var yyTok3 = [...]int8{
@@ -859,7 +1036,7 @@ func yyNewParser() yyParser {
 	return &yyParserImpl{}
 }
 
-const yyFlag = -1000
+const yyFlag = -32768
 
 func yyTokname(c int) string {
 	if c >= 1 && c-1 < len(yyToknames) {
@@ -1175,102 +1352,213 @@ yydefault:
 	case 4:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			if containsExcludedOutsideUpsert(yyDollar[1].readStmt) {
+				yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+			}
+			yylex.(*Lexer).validateJoins(yyDollar[1].readStmt)
+			yylex.(*Lexer).validateLimitOnSelect(yyDollar[1].readStmt)
 			yyVAL.statement = yyDollar[1].readStmt
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			if containsInListParam(yyDollar[1].createTableStmt) {
+				yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+			}
 			yyVAL.statement = yyDollar[1].createTableStmt
 		}
 	case 6:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
-			yyVAL.stmts = []Statement{yyDollar[1].statement}
+			if containsInListParam(yyDollar[1].statement) {
+				yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+			}
+			yyVAL.statement = yyDollar[1].statement
 		}
 	case 7:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.statement = yyDollar[1].statement
+		}
+	case 8:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.statement = yyDollar[1].statement
+		}
+	case 9:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmts = []Statement{yyDollar[1].statement}
+		}
+	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmts = append(yyDollar[1].stmts, yyDollar[3].statement)
 		}
-	case 8:
+	case 11:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			if containsExcludedOutsideUpsert(yyDollar[1].insertStmt) {
+				yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+			}
+			if containsInListParam(yyDollar[1].insertStmt) {
+				yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+			}
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].insertStmt
 		}
-	case 9:
+	case 12:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			if containsExcludedOutsideUpsert(yyDollar[1].deleteStmt) {
+				yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+			}
+			if containsInListParam(yyDollar[1].deleteStmt) {
+				yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+			}
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].deleteStmt
 		}
-	case 10:
+	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			if containsExcludedOutsideUpsert(yyDollar[1].updateStmt) {
+				yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+			}
+			if containsInListParam(yyDollar[1].updateStmt) {
+				yylex.(*Lexer).AddError(&ErrInParamNotAllowed{})
+			}
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].updateStmt
 		}
-	case 11:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].grant
 		}
-	case 12:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].revoke
 		}
-	case 13:
+	case 16:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*Lexer).statementIdx++
 			yyVAL.statement = yyDollar[1].alterTableStmt
 		}
-	case 14:
+	case 17:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 		}
-	case 15:
+	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 		}
-	case 16:
+	case 19:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+		}
+	case 20:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+		}
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
+			yylex.(*Lexer).validateOrderByOrdinals(yyDollar[1].baseSelect.SelectColumnList, yyDollar[2].orderBy)
 			yyDollar[1].baseSelect.OrderBy = yyDollar[2].orderBy
 			yyDollar[1].baseSelect.Limit = yyDollar[3].limit
 			yyVAL.readStmt = yyDollar[1].baseSelect
 		}
-	case 17:
+	case 22:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			// The ORDER BY and LIMIT of a compound select are kept in its last select.
+			yylex.(*Lexer).validateOrderByOrdinals(yyDollar[3].baseSelect.SelectColumnList, yyDollar[4].orderBy)
+			yyDollar[3].baseSelect.OrderBy = yyDollar[4].orderBy
+			yyDollar[3].baseSelect.Limit = yyDollar[5].limit
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: yyDollar[3].baseSelect}
+		}
+	case 23:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: &Values{Rows: yyDollar[4].insertRows}}
+		}
+	case 24:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.readStmt = &Values{Rows: yyDollar[2].insertRows}
+		}
+	case 25:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yylex.(*Lexer).validateOrderByOrdinals(yyDollar[3].baseSelect.SelectColumnList, yyDollar[4].orderBy)
+			yyDollar[3].baseSelect.OrderBy = yyDollar[4].orderBy
+			yyDollar[3].baseSelect.Limit = yyDollar[5].limit
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: yyDollar[3].baseSelect}
+		}
+	case 26:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: &Values{Rows: yyDollar[4].insertRows}}
+		}
+	case 27:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.readStmt = yyDollar[1].baseSelect
+		}
+	case 28:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].baseSelect, Right: yyDollar[3].readStmt}
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: yyDollar[3].baseSelect}
 		}
-	case 18:
+	case 29:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: &Values{Rows: yyDollar[4].insertRows}}
+		}
+	case 30:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.readStmt = &Values{Rows: yyDollar[2].insertRows}
+		}
+	case 31:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: yyDollar[3].baseSelect}
+		}
+	case 32:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.readStmt = &CompoundSelect{Type: yyDollar[2].string, Left: yyDollar[1].readStmt, Right: &Values{Rows: yyDollar[4].insertRows}}
+		}
+	case 35:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = CompoundUnionStr
 		}
-	case 19:
+	case 36:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = CompoundUnionAllStr
 		}
-	case 20:
+	case 37:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = CompoundExceptStr
 		}
-	case 21:
+	case 38:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = CompoundIntersectStr
 		}
-	case 22:
+	case 39:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
+			yylex.(*Lexer).validateGroupByOrdinals(yyDollar[3].selectColumnList, yyDollar[6].exprs)
 			yyVAL.baseSelect = &Select{
 				Distinct:         yyDollar[2].string,
 				SelectColumnList: yyDollar[3].selectColumnList,
@@ -1280,128 +1568,138 @@ yydefault:
 				Having:           yyDollar[7].where,
 			}
 		}
-	case 23:
+	case 40:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.string = ""
 		}
-	case 24:
+	case 41:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = DistinctStr
 		}
-	case 25:
+	case 42:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = AllStr
 		}
-	case 26:
+	case 43:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.selectColumnList = SelectColumnList{yyDollar[1].selectColumn}
 		}
-	case 27:
+	case 44:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.selectColumnList = append(yyDollar[1].selectColumnList, yyDollar[3].selectColumn)
 		}
-	case 28:
+	case 45:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.selectColumn = &StarSelectColumn{}
 		}
-	case 29:
+	case 46:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.selectColumn = &AliasedSelectColumn{Expr: yyDollar[1].expr, As: yyDollar[2].identifier}
 		}
-	case 30:
+	case 47:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.selectColumn = &StarSelectColumn{TableRef: yyDollar[1].table}
 		}
-	case 31:
+	case 48:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.identifier = Identifier("")
 		}
-	case 32:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[1].identifier
 		}
-	case 33:
+	case 50:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[2].identifier
 		}
-	case 34:
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[1].identifier
 		}
-	case 35:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = Identifier(string(yyDollar[1].bytes[0:len(yyDollar[1].bytes)]))
 		}
-	case 36:
+	case 53:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.tableExpr = nil
+		}
+	case 54:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.tableExpr = yyDollar[1].tableExpr
+		}
+	case 55:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.tableExpr = yyDollar[2].tableExpr
 		}
-	case 37:
+	case 56:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.tableExpr = yyDollar[2].joinTableExpr
 		}
-	case 38:
+	case 57:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyDollar[1].table.IsTarget = true
 			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].table, As: yyDollar[2].identifier}
 		}
-	case 39:
+	case 58:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.tableExpr = &AliasedTableExpr{Expr: &Subquery{Select: yyDollar[2].readStmt}, As: yyDollar[4].identifier}
 		}
-	case 40:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableExpr = &ParenTableExpr{TableExpr: yyDollar[2].tableExpr}
 		}
-	case 41:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableExpr = yyDollar[2].joinTableExpr
 		}
-	case 42:
+	case 61:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.identifier = Identifier("")
 		}
-	case 43:
+	case 62:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[1].identifier
 		}
-	case 44:
+	case 63:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[2].identifier
 		}
-	case 45:
+	case 64:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[1].identifier
 		}
-	case 46:
+	case 65:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.identifier = Identifier(string(yyDollar[1].bytes[0:len(yyDollar[1].bytes)]))
 		}
-	case 47:
+	case 66:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			if yyDollar[4].joinTableExpr == nil {
@@ -1417,7 +1715,7 @@ yydefault:
 				yyVAL.joinTableExpr = yyDollar[4].joinTableExpr
 			}
 		}
-	case 48:
+	case 67:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			if yyDollar[4].joinTableExpr == nil {
@@ -1433,580 +1731,608 @@ yydefault:
 				yyVAL.joinTableExpr = yyDollar[4].joinTableExpr
 			}
 		}
-	case 49:
+	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: JoinStr}
 		}
-	case 50:
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: JoinStr}
 		}
-	case 51:
+	case 70:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: JoinStr}
 		}
-	case 52:
+	case 71:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.joinOperator = &JoinOperator{Op: JoinStr, Natural: true}
+		}
+	case 72:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: LeftJoinStr, Natural: yyDollar[1].bool, Outer: yyDollar[3].bool}
 		}
-	case 53:
+	case 73:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: RightJoinStr, Natural: yyDollar[1].bool, Outer: yyDollar[3].bool}
 		}
-	case 54:
+	case 74:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: FullJoinStr, Natural: yyDollar[1].bool, Outer: yyDollar[3].bool}
 		}
-	case 55:
+	case 75:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.joinOperator = &JoinOperator{Op: InnerJoinStr, Natural: yyDollar[1].bool}
 		}
-	case 56:
+	case 76:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.bool = false
 		}
-	case 57:
+	case 77:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bool = true
 		}
-	case 58:
+	case 78:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.bool = false
 		}
-	case 59:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bool = true
 		}
-	case 60:
+	case 80:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joinTableExpr = nil
 		}
-	case 61:
+	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.joinTableExpr = &JoinTableExpr{On: yyDollar[2].expr}
 		}
-	case 62:
+	case 82:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.joinTableExpr = &JoinTableExpr{Using: yyDollar[3].columnList}
 		}
-	case 63:
+	case 83:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.where = nil
 		}
-	case 64:
+	case 84:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.where = NewWhere(WhereStr, yyDollar[2].expr)
 		}
-	case 65:
+	case 85:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exprs = nil
 		}
-	case 66:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exprs = yyDollar[3].exprs
 		}
-	case 67:
+	case 87:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.where = nil
 		}
-	case 68:
+	case 88:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.where = NewWhere(HavingStr, yyDollar[2].expr)
 		}
-	case 69:
+	case 89:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.orderBy = nil
 		}
-	case 70:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.orderBy = yyDollar[3].orderBy
 		}
-	case 71:
+	case 91:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.orderBy = OrderBy{yyDollar[1].orderingTerm}
 		}
-	case 72:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.orderBy = append(yyDollar[1].orderBy, yyDollar[3].orderingTerm)
 		}
-	case 73:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.orderingTerm = &OrderingTerm{Expr: yyDollar[1].expr, Direction: yyDollar[2].string, Nulls: yyDollar[3].nulls}
 		}
-	case 74:
+	case 94:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.string = AscStr
 		}
-	case 75:
+	case 95:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = AscStr
 		}
-	case 76:
+	case 96:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = DescStr
 		}
-	case 77:
+	case 97:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.nulls = NullsNil
 		}
-	case 78:
+	case 98:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.nulls = NullsFirst
 		}
-	case 79:
+	case 99:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.nulls = NullsLast
 		}
-	case 80:
+	case 100:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.limit = nil
 		}
-	case 81:
+	case 101:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
+			yylex.(*Lexer).validateLimitExpr(yyDollar[2].expr)
 			yyVAL.limit = &Limit{Limit: yyDollar[2].expr}
 		}
-	case 82:
+	case 102:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.limit = &Limit{Offset: yyDollar[2].expr, Limit: yyDollar[4].expr}
+			yylex.(*Lexer).validateLimitExpr(yyDollar[2].expr)
+			yylex.(*Lexer).validateLimitExpr(yyDollar[4].expr)
+			yyVAL.limit = &Limit{Offset: yyDollar[2].expr, Limit: yyDollar[4].expr, CommaSyntax: true}
 		}
-	case 83:
+	case 103:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
+			yylex.(*Lexer).validateLimitExpr(yyDollar[2].expr)
+			yylex.(*Lexer).validateLimitExpr(yyDollar[4].expr)
 			yyVAL.limit = &Limit{Offset: yyDollar[4].expr, Limit: yyDollar[2].expr}
 		}
-	case 84:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.table = &Table{Name: yyDollar[1].identifier}
 		}
-	case 85:
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 86:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].param
 		}
-	case 87:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].column
 		}
-	case 88:
+	case 108:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyDollar[3].column.TableRef = yyDollar[1].table
 			yyVAL.expr = yyDollar[3].column
 		}
-	case 89:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: PlusStr, Right: yyDollar[3].expr}
 		}
-	case 90:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: MinusStr, Right: yyDollar[3].expr}
 		}
-	case 91:
+	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: MultStr, Right: yyDollar[3].expr}
 		}
-	case 92:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: DivStr, Right: yyDollar[3].expr}
 		}
-	case 93:
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: ModStr, Right: yyDollar[3].expr}
 		}
-	case 94:
+	case 114:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: BitAndStr, Right: yyDollar[3].expr}
 		}
-	case 95:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: BitOrStr, Right: yyDollar[3].expr}
 		}
-	case 96:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: ShiftLeftStr, Right: yyDollar[3].expr}
 		}
-	case 97:
+	case 117:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: ShiftRightStr, Right: yyDollar[3].expr}
 		}
-	case 98:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: ConcatStr, Right: yyDollar[3].expr}
 		}
-	case 99:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: JSONExtractOp, Right: yyDollar[3].expr}
 		}
-	case 100:
+	case 120:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &BinaryExpr{Left: yyDollar[1].expr, Operator: JSONUnquoteExtractOp, Right: yyDollar[3].expr}
 		}
-	case 101:
+	case 121:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
+			yylex.(*Lexer).validateLikePattern(yyDollar[2].string, yyDollar[3].expr)
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: yyDollar[2].string, Right: yyDollar[3].expr}
 		}
-	case 102:
+	case 122:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: yyDollar[2].string, Right: yyDollar[3].expr}
 		}
-	case 103:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
+			yylex.(*Lexer).validateLikePattern(yyDollar[2].string, yyDollar[3].expr)
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: yyDollar[2].string, Right: yyDollar[3].expr}
 		}
-	case 104:
+	case 124:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
+			yylex.(*Lexer).validateLikePattern(yyDollar[2].string, yyDollar[3].expr)
+			yylex.(*Lexer).validateEscape(yyDollar[5].expr)
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: yyDollar[2].string, Right: yyDollar[3].expr, Escape: yyDollar[5].expr}
 		}
-	case 105:
+	case 125:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
-			if value, ok := yyDollar[2].expr.(*Value); ok && value.Type == IntValue {
+			// a negative literal isn't folded again, so the minus signs don't render as a -- comment
+			if value, ok := yyDollar[2].expr.(*Value); ok && value.Type == IntValue && !bytes.HasPrefix(value.Value, []byte("-")) {
 				yyVAL.expr = &Value{Type: IntValue, Value: append([]byte("-"), value.Value...)}
 			} else {
 				yyVAL.expr = &UnaryExpr{Operator: UMinusStr, Expr: yyDollar[2].expr}
 			}
 		}
-	case 106:
+	case 126:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &UnaryExpr{Operator: UPlusStr, Expr: yyDollar[2].expr}
 		}
-	case 107:
+	case 127:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &UnaryExpr{Operator: TildaStr, Expr: yyDollar[2].expr}
 		}
-	case 108:
+	case 128:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &AndExpr{Left: yyDollar[1].expr, Right: yyDollar[3].expr}
 		}
-	case 109:
+	case 129:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &OrExpr{Left: yyDollar[1].expr, Right: yyDollar[3].expr}
 		}
-	case 110:
+	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &IsExpr{Left: yyDollar[1].expr, Right: yyDollar[3].expr}
 		}
-	case 111:
+	case 131:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.expr = &IsExpr{Left: yyDollar[1].expr, Right: &NotExpr{Expr: yyDollar[4].expr}}
 		}
-	case 112:
+	case 132:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.expr = &NotExpr{Expr: yyDollar[2].expr}
+		}
+	case 133:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &IsNullExpr{Expr: yyDollar[1].expr}
 		}
-	case 113:
+	case 134:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &NotNullExpr{Expr: yyDollar[1].expr}
 		}
-	case 114:
+	case 135:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &NotNullExpr{Expr: yyDollar[1].expr}
 		}
-	case 115:
+	case 136:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.expr = &BetweenExpr{Left: yyDollar[1].expr, Operator: yyDollar[2].string, From: yyDollar[3].expr, To: yyDollar[5].expr}
 		}
-	case 116:
+	case 137:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.expr = &CaseExpr{Expr: yyDollar[2].expr, Whens: yyDollar[3].whens, Else: yyDollar[4].expr}
 		}
-	case 117:
+	case 138:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &CollateExpr{Expr: yyDollar[1].expr, CollationName: yyDollar[3].identifier}
 		}
-	case 118:
+	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &ParenExpr{Expr: yyDollar[2].expr}
 		}
-	case 119:
+	case 140:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: InStr, Right: yyDollar[3].colTuple}
 		}
-	case 120:
+	case 141:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.expr = &CmpExpr{Left: yyDollar[1].expr, Operator: NotInStr, Right: yyDollar[4].colTuple}
 		}
-	case 121:
+	case 142:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].subquery
 		}
-	case 122:
+	case 143:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 123:
+	case 144:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.expr = &ConvertExpr{Expr: yyDollar[3].expr, Type: yyDollar[5].convertType}
 		}
-	case 126:
+	case 147:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].value
 		}
-	case 127:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			str := yyDollar[1].bytes[1 : len(yyDollar[1].bytes)-1]
-			if len(str) > MaxTextLength {
-				yylex.(*Lexer).AddError(&ErrTextTooLong{Length: len(str), MaxAllowed: MaxTextLength})
+			// the length is the number of bytes of the value, so an escaped quote counts once
+			if length := len(str) - bytes.Count(str, []byte("''")); length > MaxTextLength {
+				yylex.(*Lexer).AddError(&ErrTextTooLong{Length: length, MaxAllowed: MaxTextLength})
 			}
 			yyVAL.expr = &Value{Type: StrValue, Value: str}
 		}
-	case 128:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
+			yylex.(*Lexer).validateBlobLiteral(yyDollar[1].bytes)
 			if len(yyDollar[1].bytes) > MaxBlobLength {
 				yylex.(*Lexer).AddError(&ErrBlobTooBig{Length: len(yyDollar[1].bytes), MaxAllowed: MaxBlobLength})
 			}
 			yyVAL.expr = &Value{Type: BlobValue, Value: yyDollar[1].bytes}
 		}
-	case 129:
+	case 150:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = BoolValue(true)
 		}
-	case 130:
+	case 151:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = BoolValue(false)
 		}
-	case 131:
+	case 152:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = &NullValue{}
 		}
-	case 132:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.column = &Column{Name: Identifier(string(yyDollar[1].identifier))}
 		}
-	case 133:
+	case 154:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.columnList = ColumnList{yyDollar[1].column}
 		}
-	case 134:
+	case 155:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnList = append(yyDollar[1].columnList, yyDollar[3].column)
 		}
-	case 135:
+	case 156:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = EqualStr
 		}
-	case 136:
+	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = NotEqualStr
 		}
-	case 137:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = RegexpStr
 		}
-	case 138:
+	case 159:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = NotRegexpStr
 		}
-	case 139:
+	case 160:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = GlobStr
 		}
-	case 140:
+	case 161:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = NotGlobStr
 		}
-	case 141:
+	case 162:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = MatchStr
 		}
-	case 142:
+	case 163:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = NotMatchStr
 		}
-	case 143:
+	case 164:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = LessThanStr
 		}
-	case 144:
+	case 165:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = GreaterThanStr
 		}
-	case 145:
+	case 166:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = LessEqualStr
 		}
-	case 146:
+	case 167:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = GreaterEqualStr
 		}
-	case 147:
+	case 168:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = LikeStr
 		}
-	case 148:
+	case 169:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = NotLikeStr
 		}
-	case 149:
+	case 170:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = BetweenStr
 		}
-	case 150:
+	case 171:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.string = NotBetweenStr
 		}
-	case 151:
+	case 172:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.convertType = NoneStr
 		}
-	case 152:
+	case 173:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.convertType = TextStr
 		}
-	case 153:
+	case 174:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.convertType = IntegerStr
 		}
-	case 154:
+	case 175:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yylex.(*Lexer).AddError(&ErrInvalidCastType{Type: string(yyDollar[1].bytes)})
+			yyVAL.convertType = ConvertType(bytes.ToLower(yyDollar[1].bytes))
+		}
+	case 176:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.colTuple = Exprs{}
 		}
-	case 155:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.colTuple = yyDollar[1].subquery
 		}
-	case 156:
+	case 178:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.colTuple = yyDollar[2].exprs
 		}
-	case 157:
+	case 179:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.colTuple = yyDollar[1].param
+		}
+	case 180:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.subquery = &Subquery{Select: yyDollar[2].readStmt}
 		}
-	case 158:
+	case 181:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &ExistsExpr{Subquery: yyDollar[2].subquery}
 		}
-	case 159:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		{
-			yyVAL.expr = &NotExpr{Expr: &ExistsExpr{Subquery: yyDollar[3].subquery}}
-		}
-	case 160:
+	case 182:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.expr = &FuncExpr{Name: Identifier("glob"), Args: Exprs{yyDollar[3].expr, yyDollar[5].expr}}
 		}
-	case 161:
+	case 183:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.expr = &FuncExpr{Name: Identifier("like"), Args: Exprs{yyDollar[3].expr, yyDollar[5].expr}}
 		}
-	case 162:
+	case 184:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.expr = &FuncExpr{Name: Identifier("like"), Args: Exprs{yyDollar[3].expr, yyDollar[5].expr, yyDollar[7].expr}}
 		}
-	case 163:
-		yyDollar = yyS[yypt-6 : yypt+1]
+	case 185:
+		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			lowered := strings.ToLower(string(yyDollar[1].identifier))
 			isCustom, ok := AllowedFunctions[lowered]
@@ -2022,13 +2348,29 @@ yydefault:
 				if yyDollar[6].where != nil {
 					yylex.(*Lexer).AddError(errors.New("custom function cannot have FILTER"))
 				}
+
+				if yyDollar[7].windowDef != nil {
+					yylex.(*Lexer).AddError(errors.New("custom function cannot have OVER"))
+				}
 				yyVAL.expr = &CustomFuncExpr{Name: Identifier(lowered), Args: yyDollar[4].exprs}
 			} else {
-				yyVAL.expr = &FuncExpr{Name: Identifier(lowered), Distinct: yyDollar[3].bool, Args: yyDollar[4].exprs, Filter: yyDollar[6].where}
+				if yyDollar[3].bool && len(yyDollar[4].exprs) == 0 {
+					yylex.(*Lexer).AddError(errors.New("function with DISTINCT must have at least one argument"))
+				}
+				if err := validateFunctionArity(lowered, yyDollar[4].exprs); err != nil {
+					yylex.(*Lexer).AddError(err)
+				}
+				if ok && yyDollar[6].where != nil && !isAggregateFunction(lowered, yyDollar[4].exprs) {
+					yylex.(*Lexer).AddError(&ErrFilterOnNonAggregate{FunctionName: lowered})
+				}
+				if ok && yyDollar[7].windowDef != nil && !isAggregateFunction(lowered, yyDollar[4].exprs) {
+					yylex.(*Lexer).AddError(&ErrOverOnNonAggregate{FunctionName: lowered})
+				}
+				yyVAL.expr = &FuncExpr{Name: Identifier(lowered), Distinct: yyDollar[3].bool, Args: yyDollar[4].exprs, Filter: yyDollar[6].where, Over: yyDollar[7].windowDef}
 			}
 		}
-	case 164:
-		yyDollar = yyS[yypt-5 : yypt+1]
+	case 186:
+		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			lowered := strings.ToLower(string(yyDollar[1].identifier))
 			isCustom, ok := AllowedFunctions[lowered]
@@ -2039,90 +2381,221 @@ yydefault:
 			if isCustom {
 				yylex.(*Lexer).AddError(errors.New("custom function cannot be used with *"))
 			} else {
-				yyVAL.expr = &FuncExpr{Name: Identifier(lowered), Distinct: false, Args: nil, Filter: yyDollar[5].where}
+				if err := validateFunctionArity(lowered, nil); err != nil {
+					yylex.(*Lexer).AddError(err)
+				}
+				if ok && yyDollar[5].where != nil && !isAggregateFunction(lowered, nil) {
+					yylex.(*Lexer).AddError(&ErrFilterOnNonAggregate{FunctionName: lowered})
+				}
+				if ok && yyDollar[6].windowDef != nil && !isAggregateFunction(lowered, nil) {
+					yylex.(*Lexer).AddError(&ErrOverOnNonAggregate{FunctionName: lowered})
+				}
+				yyVAL.expr = &FuncExpr{Name: Identifier(lowered), Distinct: false, Args: nil, Filter: yyDollar[5].where, Over: yyDollar[6].windowDef}
 			}
 		}
-	case 165:
+	case 187:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.bool = false
 		}
-	case 166:
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bool = true
 		}
-	case 167:
+	case 189:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exprs = Exprs{yyDollar[1].expr}
 		}
-	case 168:
+	case 190:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
+		}
+	case 191:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.exprs = Exprs{}
+		}
+	case 192:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.exprs = yyDollar[1].exprs
+		}
+	case 193:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.where = nil
+		}
+	case 194:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.where = &Where{Type: WhereStr, Expr: yyDollar[4].expr}
+		}
+	case 195:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.windowDef = nil
+		}
+	case 196:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.windowDef = &WindowDef{PartitionBy: yyDollar[3].exprs, OrderBy: yyDollar[4].orderBy, Frame: yyDollar[5].frameSpec}
+		}
+	case 197:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.exprs = nil
+		}
+	case 198:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
+			yyVAL.exprs = yyDollar[3].exprs
+		}
+	case 199:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.frameSpec = nil
+		}
+	case 200:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.frameSpec = &FrameSpec{Unit: yyDollar[1].string, Start: yyDollar[2].frameBound, Exclude: yyDollar[3].string}
+		}
+	case 201:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.frameSpec = &FrameSpec{Unit: yyDollar[1].string, Start: yyDollar[3].frameBound, End: yyDollar[5].frameBound, Exclude: yyDollar[6].string}
+		}
+	case 202:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.string = FrameRowsStr
+		}
+	case 203:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.string = FrameRangeStr
+		}
+	case 204:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.string = FrameGroupsStr
+		}
+	case 205:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameUnboundedPrecedingStr}
+		}
+	case 206:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FramePrecedingStr, Expr: yyDollar[1].expr}
+		}
+	case 207:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameCurrentRowStr}
+		}
+	case 208:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.frameBound = yyDollar[1].frameBound
+		}
+	case 209:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameFollowingStr, Expr: yyDollar[1].expr}
+		}
+	case 210:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FramePrecedingStr, Expr: yyDollar[1].expr}
+		}
+	case 211:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameCurrentRowStr}
+		}
+	case 212:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameFollowingStr, Expr: yyDollar[1].expr}
+		}
+	case 213:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.frameBound = &FrameBound{Type: FrameUnboundedFollowingStr}
 		}
-	case 169:
+	case 214:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
-			yyVAL.exprs = Exprs{}
+			yyVAL.string = ""
 		}
-	case 170:
-		yyDollar = yyS[yypt-1 : yypt+1]
+	case 215:
+		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.exprs = yyDollar[1].exprs
+			yyVAL.string = FrameExcludeNoOthersStr
 		}
-	case 171:
-		yyDollar = yyS[yypt-0 : yypt+1]
+	case 216:
+		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.where = nil
+			yyVAL.string = FrameExcludeCurrentRowStr
 		}
-	case 172:
-		yyDollar = yyS[yypt-5 : yypt+1]
+	case 217:
+		yyDollar = yyS[yypt-2 : yypt+1]
 		{
-			yyVAL.where = &Where{Type: WhereStr, Expr: yyDollar[4].expr}
+			yyVAL.string = FrameExcludeGroupStr
 		}
-	case 173:
+	case 218:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.string = FrameExcludeTiesStr
+		}
+	case 219:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.expr = nil
 		}
-	case 174:
+	case 220:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 175:
+	case 221:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.when = &When{Condition: yyDollar[2].expr, Value: yyDollar[4].expr}
 		}
-	case 176:
+	case 222:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.whens = []*When{yyDollar[1].when}
 		}
-	case 177:
+	case 223:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.whens = append(yyDollar[1].whens, yyDollar[2].when)
 		}
-	case 178:
+	case 224:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.expr = nil
 		}
-	case 179:
+	case 225:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[2].expr
 		}
-	case 180:
+	case 226:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			if len(yyDollar[5].columnDefList) > MaxAllowedColumns {
 				yylex.(*Lexer).AddError(&ErrTooManyColumns{ColumnCount: len(yyDollar[5].columnDefList), MaxAllowed: MaxAllowedColumns})
 			}
+			yylex.(*Lexer).validateUniqueColumnNames(yyDollar[5].columnDefList)
+			yylex.(*Lexer).validateUniqueConstraintNames(yyDollar[5].columnDefList, yyDollar[6].tableConstraints)
 
 			// We have to replace a primary key table constraint with an equivalent column constraint primary key,
 			// so we can add the autoincrement flag, as part of the rules of the Tableland Protocol.
@@ -2147,66 +2620,79 @@ yydefault:
 			}
 			yyDollar[3].table.IsTarget = true
 			yyVAL.createTableStmt = &CreateTable{Table: yyDollar[3].table, ColumnsDef: yyDollar[5].columnDefList, Constraints: yyDollar[6].tableConstraints}
+			yylex.(*Lexer).validateNoCustomFunctions(yyVAL.createTableStmt)
 		}
-	case 181:
+	case 227:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			if containsExcludedOutsideUpsert(yyDollar[6].readStmt) {
+				yylex.(*Lexer).AddError(&ErrExcludedOutsideUpsert{})
+			}
+			yylex.(*Lexer).validateJoins(yyDollar[6].readStmt)
+
+			yyDollar[3].table.IsTarget = true
+			yyVAL.statement = &CreateView{View: yyDollar[3].table, Columns: yyDollar[4].columnList, Select: yyDollar[6].readStmt}
+			yylex.(*Lexer).validateNoCustomFunctions(yyVAL.statement)
+		}
+	case 228:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.columnDefList = []*ColumnDef{yyDollar[1].columnDef}
 		}
-	case 182:
+	case 229:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnDefList = append(yyDollar[1].columnDefList, yyDollar[3].columnDef)
 		}
-	case 183:
+	case 230:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if isRowID(yyDollar[1].column.Name) {
 				yylex.(*Lexer).AddError(&ErrRowIDNotAllowed{})
 			}
 
-			if yyDollar[2].string == TypeIntegerStr {
-				for _, constraint := range yyDollar[3].columnConstraints {
-					if primaryKey, ok := constraint.(*ColumnConstraintPrimaryKey); ok {
-						if primaryKey.Order != PrimaryKeyOrderDesc {
-							primaryKey.AutoIncrement = true
-						}
+			for _, constraint := range yyDollar[3].columnConstraints {
+				if primaryKey, ok := constraint.(*ColumnConstraintPrimaryKey); ok {
+					if yyDollar[2].string == TypeIntegerStr && primaryKey.Order != PrimaryKeyOrderDesc {
+						primaryKey.AutoIncrement = true
+					} else if primaryKey.AutoIncrement {
+						yylex.(*Lexer).AddError(&ErrAutoIncrementNotAllowed{})
 					}
 				}
 			}
 			yyVAL.columnDef = &ColumnDef{Column: yyDollar[1].column, Type: yyDollar[2].string, Constraints: yyDollar[3].columnConstraints}
 		}
-	case 184:
+	case 231:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = TypeIntStr
 		}
-	case 185:
+	case 232:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = TypeIntegerStr
 		}
-	case 186:
+	case 233:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = TypeTextStr
 		}
-	case 187:
+	case 234:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = TypeBlobStr
 		}
-	case 188:
+	case 235:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.columnConstraints = []ColumnConstraint{}
 		}
-	case 189:
+	case 236:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.columnConstraints = yyDollar[1].columnConstraints
 		}
-	case 190:
+	case 237:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if _, ok := yyDollar[1].columnConstraint.(*ColumnConstraintPrimaryKey); ok {
@@ -2218,7 +2704,7 @@ yydefault:
 			}
 			yyVAL.columnConstraints = []ColumnConstraint{yyDollar[1].columnConstraint}
 		}
-	case 191:
+	case 238:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			if _, ok := yyDollar[2].columnConstraint.(*ColumnConstraintPrimaryKey); ok && yylex.(*Lexer).createStmtHasPrimaryKey {
@@ -2226,129 +2712,148 @@ yydefault:
 			}
 			yyVAL.columnConstraints = append(yyDollar[1].columnConstraints, yyDollar[2].columnConstraint)
 		}
-	case 192:
+	case 239:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintPrimaryKey{Name: yyDollar[1].identifier, Order: yyDollar[4].string}
 		}
-	case 193:
+	case 240:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			// AUTOINCREMENT is not allowed as an identifier, so it is lexed as one.
+			if !strings.EqualFold(string(yyDollar[5].bytes), "autoincrement") {
+				yylex.Error("syntax error")
+			}
+			yyVAL.columnConstraint = &ColumnConstraintPrimaryKey{Name: yyDollar[1].identifier, Order: yyDollar[4].string, AutoIncrement: true}
+		}
+	case 241:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintNotNull{Name: yyDollar[1].identifier}
 		}
-	case 194:
+	case 242:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintUnique{Name: yyDollar[1].identifier}
 		}
-	case 195:
+	case 243:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintCheck{Name: yyDollar[1].identifier, Expr: yyDollar[4].expr}
 		}
-	case 196:
+	case 244:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintDefault{Name: yyDollar[1].identifier, Expr: yyDollar[4].expr, Parenthesis: true}
 		}
-	case 197:
+	case 245:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintDefault{Name: yyDollar[1].identifier, Expr: yyDollar[3].expr}
 		}
-	case 198:
+	case 246:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintDefault{Name: yyDollar[1].identifier, Expr: yyDollar[3].expr}
 		}
-	case 199:
+	case 247:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			// SQLite requires a function call to be parenthesized, but a call to a custom function is accepted here,
+			// so it's rejected by validateNoCustomFunctions with a specific error instead of a syntax error.
+			if _, ok := yyDollar[3].expr.(*CustomFuncExpr); !ok {
+				yylex.(*Lexer).AddError(errors.New("a DEFAULT function call must be parenthesized"))
+			}
+			yyVAL.columnConstraint = &ColumnConstraintDefault{Name: yyDollar[1].identifier, Expr: yyDollar[3].expr}
+		}
+	case 248:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintGenerated{Name: yyDollar[1].identifier, Expr: yyDollar[6].expr, GeneratedAlways: true, IsStored: yyDollar[8].bool}
 		}
-	case 200:
+	case 249:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.columnConstraint = &ColumnConstraintGenerated{Name: yyDollar[1].identifier, Expr: yyDollar[4].expr, GeneratedAlways: false, IsStored: yyDollar[6].bool}
 		}
-	case 201:
+	case 250:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.identifier = Identifier("")
 		}
-	case 202:
+	case 251:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.identifier = yyDollar[2].identifier
 		}
-	case 203:
+	case 252:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.string = PrimaryKeyOrderEmpty
 		}
-	case 204:
+	case 253:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = PrimaryKeyOrderAsc
 		}
-	case 205:
+	case 254:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = PrimaryKeyOrderDesc
 		}
-	case 206:
+	case 255:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[2].value
 		}
-	case 207:
+	case 256:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyDollar[2].value.Value = append([]byte("-"), yyDollar[2].value.Value...)
 			yyVAL.expr = yyDollar[2].value
 		}
-	case 208:
+	case 257:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Value{Type: IntValue, Value: yyDollar[1].bytes}
 		}
-	case 209:
+	case 258:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*Lexer).AddError(&ErrNumericLiteralFloat{Value: yyDollar[1].bytes})
 			yyVAL.value = &Value{Type: FloatValue, Value: yyDollar[1].bytes}
 		}
-	case 210:
+	case 259:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Value{Type: HexNumValue, Value: yyDollar[1].bytes}
 		}
-	case 211:
+	case 260:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.bool = false
 		}
-	case 212:
+	case 261:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bool = true
 		}
-	case 213:
+	case 262:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bool = false
 		}
-	case 214:
+	case 263:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.tableConstraints = []TableConstraint{}
 		}
-	case 215:
+	case 264:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableConstraints = yyDollar[1].tableConstraints
 		}
-	case 216:
+	case 265:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			if _, ok := yyDollar[2].tableConstraint.(*TableConstraintPrimaryKey); ok {
@@ -2360,7 +2865,7 @@ yydefault:
 			}
 			yyVAL.tableConstraints = []TableConstraint{yyDollar[2].tableConstraint}
 		}
-	case 217:
+	case 266:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if _, ok := yyDollar[3].tableConstraint.(*TableConstraintPrimaryKey); ok && yylex.(*Lexer).createStmtHasPrimaryKey {
@@ -2368,47 +2873,47 @@ yydefault:
 			}
 			yyVAL.tableConstraints = append(yyDollar[1].tableConstraints, yyDollar[3].tableConstraint)
 		}
-	case 218:
+	case 267:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.tableConstraint = &TableConstraintPrimaryKey{Name: yyDollar[1].identifier, Columns: yyDollar[5].indexedColumnList}
 		}
-	case 219:
+	case 268:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.tableConstraint = &TableConstraintUnique{Name: yyDollar[1].identifier, Columns: yyDollar[4].columnList}
 		}
-	case 220:
+	case 269:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.tableConstraint = &TableConstraintCheck{Name: yyDollar[1].identifier, Expr: yyDollar[4].expr}
 		}
-	case 221:
+	case 270:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.indexedColumnList = IndexedColumnList{yyDollar[1].indexedColumn}
 		}
-	case 222:
+	case 271:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.indexedColumnList = append(yyDollar[1].indexedColumnList, yyDollar[3].indexedColumn)
 		}
-	case 223:
+	case 272:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.indexedColumn = &IndexedColumn{Column: yyDollar[1].column, CollationName: yyDollar[2].identifier, Order: yyDollar[3].string}
 		}
-	case 224:
+	case 273:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.identifier = Identifier("")
 		}
-	case 225:
+	case 274:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.identifier = Identifier(string(yyDollar[2].identifier))
 		}
-	case 226:
+	case 275:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			for i := 0; i < len(yyDollar[4].columnList); i++ {
@@ -2417,7 +2922,15 @@ yydefault:
 				}
 			}
 
-			for _, row := range yyDollar[6].insertRows {
+			// without a column list, every row must have as many values as the first one
+			columnsCount := len(yyDollar[4].columnList)
+			if columnsCount == 0 && len(yyDollar[6].insertRows) > 0 {
+				columnsCount = len(yyDollar[6].insertRows[0])
+			}
+			for i, row := range yyDollar[6].insertRows {
+				if len(row) != columnsCount {
+					yylex.(*Lexer).AddError(&ErrInsertArityMismatch{RowIndex: i, ColumnsCount: columnsCount, ValuesCount: len(row)})
+				}
 				for _, expr := range row {
 					if containsSubquery(expr) {
 						yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "insert"})
@@ -2427,13 +2940,13 @@ yydefault:
 			yyDollar[3].table.IsTarget = true
 			yyVAL.insertStmt = &Insert{Table: yyDollar[3].table, Columns: yyDollar[4].columnList, Rows: yyDollar[6].insertRows, Upsert: yyDollar[7].upsertClause}
 		}
-	case 227:
+	case 276:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyDollar[3].table.IsTarget = true
 			yyVAL.insertStmt = &Insert{Table: yyDollar[3].table, Columns: ColumnList{}, Rows: []Exprs{}, DefaultValues: true}
 		}
-	case 228:
+	case 277:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyDollar[3].table.IsTarget = true
@@ -2454,9 +2967,9 @@ yydefault:
 			}
 
 			if sel, ok := yyDollar[5].readStmt.(*Select); ok {
-				if sel.OrderBy == nil {
-					sel.OrderBy = OrderBy{&OrderingTerm{Expr: &Column{Name: Identifier("rowid")}, Direction: AscStr, Nulls: NullsNil}}
-				} else {
+				// The rowid ordering term is not added again if it is already there (e.g. when parsing a deparsed statement).
+				// A SELECT without FROM has no rowid to order by.
+				if !yylex.(*Lexer).opts.DisableAutoOrderByRowid && sel.From != nil && !endsWithRowIDOrderingTerm(sel.OrderBy) {
 					sel.OrderBy = append(sel.OrderBy, &OrderingTerm{Expr: &Column{Name: Identifier("rowid")}, Direction: AscStr, Nulls: NullsNil})
 				}
 
@@ -2466,32 +2979,60 @@ yydefault:
 				yyVAL.insertStmt = &Insert{Table: yyDollar[3].table, Columns: yyDollar[4].columnList, Rows: []Exprs{}, Upsert: yyDollar[6].upsertClause}
 			}
 		}
-	case 229:
+	case 278:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.columnList = ColumnList{}
 		}
-	case 230:
+	case 279:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.columnList = yyDollar[2].columnList
 		}
-	case 231:
+	case 280:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.insertRows = []Exprs{yyDollar[2].exprs}
 		}
-	case 232:
+	case 281:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.insertRows = append(yyDollar[1].insertRows, yyDollar[4].exprs)
 		}
-	case 233:
+	case 282:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.insertRows = []Exprs{yyDollar[2].exprs}
+		}
+	case 283:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.insertRows = append(yyDollar[1].insertRows, yyDollar[4].exprs)
+		}
+	case 284:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.exprs = Exprs{yyDollar[1].expr}
+		}
+	case 285:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
+		}
+	case 287:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			if !yylex.(*Lexer).opts.AllowDefaultKeyword {
+				yylex.(*Lexer).AddError(&ErrDefaultKeywordNotAllowed{})
+			}
+			yyVAL.expr = &DefaultExpr{}
+		}
+	case 288:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.upsertClause = nil
 		}
-	case 234:
+	case 289:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			allConflictClausesExceptLast := yyDollar[1].onConflictClauseList[0 : len(yyDollar[1].onConflictClauseList)-1]
@@ -2502,24 +3043,24 @@ yydefault:
 			}
 			yyVAL.upsertClause = yyDollar[1].onConflictClauseList
 		}
-	case 235:
+	case 290:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.onConflictClauseList = []*OnConflictClause{yyDollar[1].onConflictClause}
 		}
-	case 236:
+	case 291:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.onConflictClauseList = append(yyDollar[1].onConflictClauseList, yyDollar[2].onConflictClause)
 		}
-	case 237:
+	case 292:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.onConflictClause = &OnConflictClause{
 				Target: yyDollar[3].onConflictTarget,
 			}
 		}
-	case 238:
+	case 293:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			if yyDollar[8].where != nil && containsSubquery(yyDollar[8].where) {
@@ -2534,12 +3075,12 @@ yydefault:
 				},
 			}
 		}
-	case 239:
+	case 294:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.onConflictTarget = nil
 		}
-	case 240:
+	case 295:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			if yyDollar[4].where != nil && containsSubquery(yyDollar[4].where) {
@@ -2551,35 +3092,37 @@ yydefault:
 				Where:   yyDollar[4].where,
 			}
 		}
-	case 241:
+	case 296:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			if yyDollar[4].where != nil && containsSubquery(yyDollar[4].where) {
 				yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "delete"})
 			}
+			yylex.(*Lexer).validateWhereOnDestructive("delete", yyDollar[4].where)
 			yyDollar[3].table.IsTarget = true
 			yyVAL.deleteStmt = &Delete{Table: yyDollar[3].table, Where: yyDollar[4].where}
 		}
-	case 242:
+	case 297:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			if yyDollar[5].where != nil && containsSubquery(yyDollar[5].where) {
 				yylex.(*Lexer).AddError(&ErrStatementContainsSubquery{StatementKind: "where"})
 			}
+			yylex.(*Lexer).validateWhereOnDestructive("update", yyDollar[5].where)
 			yyDollar[2].table.IsTarget = true
 			yyVAL.updateStmt = &Update{Table: yyDollar[2].table, Exprs: yyDollar[4].updateList, Where: yyDollar[5].where}
 		}
-	case 243:
+	case 298:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.updateList = yyDollar[1].updateList
 		}
-	case 244:
+	case 299:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.updateList = yyDollar[1].updateList
 		}
-	case 245:
+	case 300:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if containsSubquery(yyDollar[1].updateExpression.Expr) {
@@ -2587,12 +3130,12 @@ yydefault:
 			}
 			yyVAL.updateList = []*UpdateExpr{yyDollar[1].updateExpression}
 		}
-	case 246:
+	case 301:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.updateList = append(yyDollar[1].updateList, yyDollar[3].updateExpression)
 		}
-	case 247:
+	case 302:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			if len(yyDollar[2].columnList) != len(yyDollar[6].exprs) {
@@ -2609,7 +3152,7 @@ yydefault:
 				yyVAL.updateList = exprs
 			}
 		}
-	case 248:
+	case 303:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if isRowID(yyDollar[1].column.Name) {
@@ -2617,36 +3160,36 @@ yydefault:
 			}
 			yyVAL.updateExpression = &UpdateExpr{Column: yyDollar[1].column, Expr: yyDollar[3].expr}
 		}
-	case 249:
+	case 304:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyDollar[4].table.IsTarget = true
 			yyVAL.grant = &Grant{Table: yyDollar[4].table, Privileges: yyDollar[2].privileges, Roles: yyDollar[6].strings}
 		}
-	case 250:
+	case 305:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyDollar[4].table.IsTarget = true
 			yyVAL.revoke = &Revoke{Table: yyDollar[4].table, Privileges: yyDollar[2].privileges, Roles: yyDollar[6].strings}
 		}
-	case 251:
+	case 306:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.strings = []string{string(yyDollar[1].bytes[1 : len(yyDollar[1].bytes)-1])}
 		}
-	case 252:
+	case 307:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.strings = append(yyDollar[1].strings, string(yyDollar[3].bytes[1:len(yyDollar[3].bytes)-1]))
 		}
-	case 253:
+	case 308:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			privileges := make(map[string]struct{})
 			privileges[yyDollar[1].string] = struct{}{}
 			yyVAL.privileges = Privileges(privileges)
 		}
-	case 254:
+	case 309:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if _, ok := yyDollar[1].privileges[yyDollar[3].string]; ok {
@@ -2656,22 +3199,22 @@ yydefault:
 			yyDollar[1].privileges[yyDollar[3].string] = struct{}{}
 			yyVAL.privileges = yyDollar[1].privileges
 		}
-	case 255:
+	case 310:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = "insert"
 		}
-	case 256:
+	case 311:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = "update"
 		}
-	case 257:
+	case 312:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.string = "delete"
 		}
-	case 258:
+	case 313:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyDollar[3].table.IsTarget = true
@@ -2683,7 +3226,7 @@ yydefault:
 				},
 			}
 		}
-	case 259:
+	case 314:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 
@@ -2711,6 +3254,13 @@ yydefault:
 				}
 			}
 
+			yylex.(*Lexer).validateNoCustomFunctions(yyDollar[6].columnDef)
+
+			// a NOT NULL column without a DEFAULT has a NULL default value
+			if hasNotNull && !hasDefault {
+				yylex.(*Lexer).AddError(&ErrNotNullConstraintDefaultNotNull{})
+			}
+
 			if hasNotNull && hasDefault && defaultConstraint != nil {
 				if _, ok := defaultConstraint.Expr.(*NullValue); ok {
 					yylex.(*Lexer).AddError(&ErrNotNullConstraintDefaultNotNull{})
@@ -2725,7 +3275,7 @@ yydefault:
 				},
 			}
 		}
-	case 260:
+	case 315:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 
@@ -2737,15 +3287,101 @@ yydefault:
 				},
 			}
 		}
-	case 261:
+	case 316:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			if !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+				yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+			}
+			yyVAL.statement = yyDollar[1].statement
+		}
+	case 317:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.statement = &Vacuum{}
+		}
+	case 318:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.statement = &Vacuum{Schema: yyDollar[2].identifier}
+		}
+	case 319:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.statement = &Analyze{}
+		}
+	case 320:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyDollar[2].table.IsTarget = true
+			yyVAL.statement = &Analyze{Table: yyDollar[2].table}
+		}
+	case 321:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.statement = &Reindex{}
+		}
+	case 322:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyDollar[2].table.IsTarget = true
+			yyVAL.statement = &Reindex{Table: yyDollar[2].table}
+		}
+	case 323:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			pragma := &Pragma{Name: yyDollar[2].identifier}
+			if !pragma.IsReadOnly() && !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+				yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+			}
+			yyVAL.statement = pragma
+		}
+	case 324:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.statement = &Pragma{Name: yyDollar[2].identifier, Value: yyDollar[4].expr}
+			if !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+				yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+			}
+		}
+	case 325:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			pragma := &Pragma{Name: yyDollar[2].identifier, Arg: yyDollar[4].expr}
+			if !pragma.IsReadOnly() && !yylex.(*Lexer).opts.AllowMaintenanceStatements {
+				yylex.(*Lexer).AddError(&ErrMaintenanceStatementNotAllowed{})
+			}
+			yyVAL.statement = pragma
+		}
+	case 326:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.expr = yyDollar[1].expr
+		}
+	case 327:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.expr = yyDollar[1].value
+		}
+	case 328:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.expr = &Value{Type: StrValue, Value: yyDollar[1].bytes[1 : len(yyDollar[1].bytes)-1]}
+		}
+	case 329:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.expr = &Column{Name: yyDollar[1].identifier}
+		}
+	case 330:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 		}
-	case 262:
+	case 331:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 		}
-	case 263:
+	case 332:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			literalUpper := bytes.ToUpper(yyDollar[1].bytes)
@@ -2755,7 +3391,7 @@ yydefault:
 
 			yyVAL.identifier = Identifier(yyDollar[1].bytes)
 		}
-	case 264:
+	case 333:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.param = &Param{}